@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -47,6 +48,7 @@ type Program struct {
 	*Attr
 	SourceType string
 	Body       []Statement
+	Directives []*Directive
 }
 
 func (p *Program) GetAttr() *Attr {
@@ -107,166 +109,1333 @@ func (e *ExpressionStatement) String() string {
 	return e.Expression.String()
 }
 
+type BlockStatement struct {
+	*Attr
+	Body []Statement
+	// Directives are the directive prologue of a function body
+	Directives []*Directive
+}
+
+func (b *BlockStatement) statementNode() {}
+
+func (b *BlockStatement) GetAttr() *Attr {
+	return b.Attr
+}
+
+func (b *BlockStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("{ ")
+	for _, s := range b.Body {
+		out.WriteString(s.String())
+		out.WriteString("; ")
+	}
+	out.WriteString("}")
+
+	return out.String()
+}
+
+type EmptyStatement struct {
+	*Attr
+}
+
+func (e *EmptyStatement) statementNode() {}
+
+func (e *EmptyStatement) GetAttr() *Attr {
+	return e.Attr
+}
+
+func (e *EmptyStatement) String() string {
+	return ";"
+}
+
+type IfStatement struct {
+	*Attr
+	Test       Expression
+	Consequent Statement
+	Alternate  Statement
+}
+
+func (i *IfStatement) statementNode() {}
+
+func (i *IfStatement) GetAttr() *Attr {
+	return i.Attr
+}
+
+func (i *IfStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("if (")
+	out.WriteString(i.Test.String())
+	out.WriteString(") ")
+	out.WriteString(i.Consequent.String())
+	if i.Alternate != nil {
+		out.WriteString(" else ")
+		out.WriteString(i.Alternate.String())
+	}
+
+	return out.String()
+}
+
+type ForStatement struct {
+	*Attr
+	// Init is either a VariableDeclaration or an Expression
+	Init   Node
+	Test   Expression
+	Update Expression
+	Body   Statement
+}
+
+func (f *ForStatement) statementNode() {}
+
+func (f *ForStatement) GetAttr() *Attr {
+	return f.Attr
+}
+
+func (f *ForStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("for (")
+	if f.Init != nil {
+		out.WriteString(f.Init.String())
+	}
+	out.WriteString("; ")
+	if f.Test != nil {
+		out.WriteString(f.Test.String())
+	}
+	out.WriteString("; ")
+	if f.Update != nil {
+		out.WriteString(f.Update.String())
+	}
+	out.WriteString(") ")
+	out.WriteString(f.Body.String())
+
+	return out.String()
+}
+
+type WhileStatement struct {
+	*Attr
+	Test Expression
+	Body Statement
+}
+
+func (w *WhileStatement) statementNode() {}
+
+func (w *WhileStatement) GetAttr() *Attr {
+	return w.Attr
+}
+
+func (w *WhileStatement) String() string {
+	return fmt.Sprintf("while (%s) %s", w.Test, w.Body)
+}
+
+type DoWhileStatement struct {
+	*Attr
+	Body Statement
+	Test Expression
+}
+
+func (d *DoWhileStatement) statementNode() {}
+
+func (d *DoWhileStatement) GetAttr() *Attr {
+	return d.Attr
+}
+
+func (d *DoWhileStatement) String() string {
+	return fmt.Sprintf("do %s while (%s)", d.Body, d.Test)
+}
+
+type ForInStatement struct {
+	*Attr
+	// Left is either a VariableDeclaration or a Pattern
+	Left  Node
+	Right Expression
+	Body  Statement
+}
+
+func (f *ForInStatement) statementNode() {}
+
+func (f *ForInStatement) GetAttr() *Attr {
+	return f.Attr
+}
+
+func (f *ForInStatement) String() string {
+	return fmt.Sprintf("for (%s in %s) %s", f.Left, f.Right, f.Body)
+}
+
+type ForOfStatement struct {
+	*Attr
+	// Left is either a VariableDeclaration or a Pattern
+	Left  Node
+	Right Expression
+	Body  Statement
+}
+
+func (f *ForOfStatement) statementNode() {}
+
+func (f *ForOfStatement) GetAttr() *Attr {
+	return f.Attr
+}
+
+func (f *ForOfStatement) String() string {
+	return fmt.Sprintf("for (%s of %s) %s", f.Left, f.Right, f.Body)
+}
+
+type SwitchStatement struct {
+	*Attr
+	Discriminant Expression
+	Cases        []*SwitchCase
+}
+
+func (s *SwitchStatement) statementNode() {}
+
+func (s *SwitchStatement) GetAttr() *Attr {
+	return s.Attr
+}
+
+func (s *SwitchStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(fmt.Sprintf("switch (%s) { ", s.Discriminant))
+	for _, c := range s.Cases {
+		out.WriteString(c.String())
+		out.WriteString(" ")
+	}
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// SwitchCase is a clause of a switch statement, whose Test is nil for the
+// default clause
+type SwitchCase struct {
+	*Attr
+	Test       Expression
+	Consequent []Statement
+}
+
+func (s *SwitchCase) GetAttr() *Attr {
+	return s.Attr
+}
+
+func (s *SwitchCase) String() string {
+	var out bytes.Buffer
+
+	if s.Test == nil {
+		out.WriteString("default:")
+	} else {
+		out.WriteString("case " + s.Test.String() + ":")
+	}
+	for _, st := range s.Consequent {
+		out.WriteString(" ")
+		out.WriteString(st.String())
+		out.WriteString(";")
+	}
+
+	return out.String()
+}
+
+type BreakStatement struct {
+	*Attr
+	Label *Identifier
+}
+
+func (b *BreakStatement) statementNode() {}
+
+func (b *BreakStatement) GetAttr() *Attr {
+	return b.Attr
+}
+
+func (b *BreakStatement) String() string {
+	if b.Label == nil {
+		return "break"
+	}
+
+	return "break " + b.Label.String()
+}
+
+type ContinueStatement struct {
+	*Attr
+	Label *Identifier
+}
+
+func (c *ContinueStatement) statementNode() {}
+
+func (c *ContinueStatement) GetAttr() *Attr {
+	return c.Attr
+}
+
+func (c *ContinueStatement) String() string {
+	if c.Label == nil {
+		return "continue"
+	}
+
+	return "continue " + c.Label.String()
+}
+
+type LabeledStatement struct {
+	*Attr
+	Label *Identifier
+	Body  Statement
+}
+
+func (l *LabeledStatement) statementNode() {}
+
+func (l *LabeledStatement) GetAttr() *Attr {
+	return l.Attr
+}
+
+func (l *LabeledStatement) String() string {
+	return fmt.Sprintf("%s: %s", l.Label, l.Body)
+}
+
+type ReturnStatement struct {
+	*Attr
+	Argument Expression
+}
+
+func (r *ReturnStatement) statementNode() {}
+
+func (r *ReturnStatement) GetAttr() *Attr {
+	return r.Attr
+}
+
+func (r *ReturnStatement) String() string {
+	if r.Argument == nil {
+		return "return"
+	}
+
+	return "return " + r.Argument.String()
+}
+
+type ThrowStatement struct {
+	*Attr
+	Argument Expression
+}
+
+func (t *ThrowStatement) statementNode() {}
+
+func (t *ThrowStatement) GetAttr() *Attr {
+	return t.Attr
+}
+
+func (t *ThrowStatement) String() string {
+	return "throw " + t.Argument.String()
+}
+
+type TryStatement struct {
+	*Attr
+	Block     *BlockStatement
+	Handler   *CatchClause
+	Finalizer *BlockStatement
+}
+
+func (t *TryStatement) statementNode() {}
+
+func (t *TryStatement) GetAttr() *Attr {
+	return t.Attr
+}
+
+func (t *TryStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("try ")
+	out.WriteString(t.Block.String())
+	if t.Handler != nil {
+		out.WriteString(" ")
+		out.WriteString(t.Handler.String())
+	}
+	if t.Finalizer != nil {
+		out.WriteString(" finally ")
+		out.WriteString(t.Finalizer.String())
+	}
+
+	return out.String()
+}
+
+// CatchClause is the handler of a try statement, whose parameter is
+// optional
+type CatchClause struct {
+	*Attr
+	Param Pattern
+	Body  *BlockStatement
+}
+
+func (c *CatchClause) GetAttr() *Attr {
+	return c.Attr
+}
+
+func (c *CatchClause) String() string {
+	if c.Param == nil {
+		return "catch " + c.Body.String()
+	}
+
+	return fmt.Sprintf("catch (%s) %s", c.Param, c.Body)
+}
+
 // declarations
 
-type Declaration interface {
-	Statement
-	declarationNode()
+type Declaration interface {
+	Statement
+	declarationNode()
+}
+
+type VariableDeclaration struct {
+	*Attr
+	Declarations []*VariableDeclarator
+	Kind         string
+}
+
+func (v *VariableDeclaration) statementNode() {}
+
+func (v *VariableDeclaration) declarationNode() {}
+
+func (v *VariableDeclaration) GetAttr() *Attr {
+	return v.Attr
+}
+
+func (v *VariableDeclaration) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(v.Kind)
+	out.WriteString(" ")
+	for _, d := range v.Declarations {
+		out.WriteString(d.String())
+	}
+
+	return out.String()
+}
+
+type FunctionDeclaration struct {
+	*Attr
+	*Function
+}
+
+func (f *FunctionDeclaration) statementNode() {}
+
+func (f *FunctionDeclaration) declarationNode() {}
+
+func (f *FunctionDeclaration) GetAttr() *Attr {
+	return f.Attr
+}
+
+type ClassDeclaration struct {
+	*Attr
+	*Class
+}
+
+func (c *ClassDeclaration) statementNode() {}
+
+func (c *ClassDeclaration) declarationNode() {}
+
+func (c *ClassDeclaration) GetAttr() *Attr {
+	return c.Attr
+}
+
+type VariableDeclarator struct {
+	*Attr
+	ID   Pattern
+	Init Expression
+}
+
+func (v *VariableDeclarator) GetAttr() *Attr {
+	return v.Attr
+}
+
+func (v *VariableDeclarator) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(v.ID.String())
+	if v.Init != nil {
+		out.WriteString(" = ")
+		out.WriteString(v.Init.String())
+	}
+
+	return out.String()
+}
+
+// expressions
+
+type Expression interface {
+	Node
+	expressionNode()
+}
+
+type Identifier struct {
+	*Attr
+	Name string
+}
+
+func (i *Identifier) expressionNode() {}
+
+func (i *Identifier) patternNode() {}
+
+func (i *Identifier) GetAttr() *Attr {
+	return i.Attr
+}
+
+func (i *Identifier) String() string {
+	return i.Name
+}
+
+type FunctionExpression struct {
+	*Attr
+	*Function
+}
+
+func (f *FunctionExpression) expressionNode() {}
+
+func (f *FunctionExpression) GetAttr() *Attr {
+	return f.Attr
+}
+
+// ArrowFunctionExpression is an arrow function
+// A concise body is unmarshalled to a block that returns the expression, and
+// Expression is true.
+type ArrowFunctionExpression struct {
+	*Attr
+	*Function
+	Expression bool
+}
+
+func (a *ArrowFunctionExpression) expressionNode() {}
+
+func (a *ArrowFunctionExpression) GetAttr() *Attr {
+	return a.Attr
+}
+
+func (a *ArrowFunctionExpression) String() string {
+	var params []string
+	for _, p := range a.Params {
+		params = append(params, p.String())
+	}
+
+	body := a.Body.String()
+	if a.Expression {
+		body = a.Body.Body[0].(*ReturnStatement).Argument.String()
+	}
+
+	out := "(" + strings.Join(params, ", ") + ") => " + body
+	if a.Async {
+		out = "async " + out
+	}
+
+	return out
+}
+
+// ArrayExpression is an array literal
+// Elements that are left out, like in [1, , 3], are nil.
+type ArrayExpression struct {
+	*Attr
+	Elements []Expression
+}
+
+func (a *ArrayExpression) expressionNode() {}
+
+func (a *ArrayExpression) GetAttr() *Attr {
+	return a.Attr
+}
+
+func (a *ArrayExpression) String() string {
+	var elements []string
+	for _, e := range a.Elements {
+		if e == nil {
+			elements = append(elements, "")
+		} else {
+			elements = append(elements, e.String())
+		}
+	}
+
+	return fmt.Sprintf("[%s]", strings.Join(elements, ", "))
+}
+
+type ObjectExpression struct {
+	*Attr
+	Properties []Property
+}
+
+func (o *ObjectExpression) expressionNode() {}
+
+func (o *ObjectExpression) GetAttr() *Attr {
+	return o.Attr
+}
+
+func (o *ObjectExpression) String() string {
+	var props []string
+	for _, p := range o.Properties {
+		props = append(props, p.String())
+	}
+
+	return fmt.Sprintf("{%s}", strings.Join(props, ", "))
+}
+
+// Property is a property of an object literal
+type Property interface {
+	Node
+	propertyNode()
+}
+
+type ObjectProperty struct {
+	*Attr
+	Key       Expression
+	Value     Expression
+	Computed  bool
+	Shorthand bool
+}
+
+func (p *ObjectProperty) propertyNode() {}
+
+func (p *ObjectProperty) GetAttr() *Attr {
+	return p.Attr
+}
+
+func (p *ObjectProperty) String() string {
+	if p.Shorthand {
+		return p.Value.String()
+	}
+
+	return fmt.Sprintf("%s: %s", propertyKeyString(p.Key, p.Computed), p.Value)
+}
+
+// ObjectMethod is a method, getter or setter of an object literal
+type ObjectMethod struct {
+	*Attr
+	*Function
+	Kind     string
+	Key      Expression
+	Computed bool
+}
+
+func (m *ObjectMethod) propertyNode() {}
+
+func (m *ObjectMethod) GetAttr() *Attr {
+	return m.Attr
+}
+
+func (m *ObjectMethod) String() string {
+	var out bytes.Buffer
+
+	if m.Kind != "method" {
+		out.WriteString(m.Kind)
+		out.WriteString(" ")
+	}
+	out.WriteString(propertyKeyString(m.Key, m.Computed))
+	out.WriteString("(")
+
+	var params []string
+	for _, p := range m.Params {
+		params = append(params, p.String())
+	}
+	out.WriteString(strings.Join(params, ", "))
+
+	out.WriteString(") ")
+	out.WriteString(m.Body.String())
+
+	return out.String()
+}
+
+// SpreadElement spreads the values of an iterable in the arguments of a call
+// or the elements of an array literal, like f(...a) or [...a], or the own
+// properties of an object in an object literal, like {...o}
+type SpreadElement struct {
+	*Attr
+	Argument Expression
+}
+
+func (s *SpreadElement) expressionNode() {}
+
+func (s *SpreadElement) propertyNode() {}
+
+func (s *SpreadElement) GetAttr() *Attr {
+	return s.Attr
+}
+
+func (s *SpreadElement) String() string {
+	return "..." + s.Argument.String()
+}
+
+type YieldExpression struct {
+	*Attr
+	// Argument is nil for a yield without a value
+	Argument Expression
+	Delegate bool
+}
+
+func (y *YieldExpression) expressionNode() {}
+
+func (y *YieldExpression) GetAttr() *Attr {
+	return y.Attr
+}
+
+func (y *YieldExpression) String() string {
+	out := "yield"
+	if y.Delegate {
+		out += "*"
+	}
+	if y.Argument != nil {
+		out += " " + y.Argument.String()
+	}
+
+	return out
+}
+
+// AwaitExpression is an await in an async function
+type AwaitExpression struct {
+	*Attr
+	Argument Expression
+}
+
+func (a *AwaitExpression) expressionNode() {}
+
+func (a *AwaitExpression) GetAttr() *Attr {
+	return a.Attr
+}
+
+func (a *AwaitExpression) String() string {
+	return "await " + a.Argument.String()
+}
+
+func propertyKeyString(key Expression, computed bool) string {
+	if computed {
+		return fmt.Sprintf("[%s]", key)
+	}
+
+	return key.String()
+}
+
+type CallExpression struct {
+	*Attr
+	Callee    Expression
+	Arguments []Expression
+}
+
+func (c *CallExpression) expressionNode() {}
+
+func (c *CallExpression) GetAttr() *Attr {
+	return c.Attr
+}
+
+func (c *CallExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(c.Callee.String())
+	out.WriteString("(")
+
+	var args []string
+	for _, arg := range c.Arguments {
+		args = append(args, arg.String())
+	}
+	out.WriteString(strings.Join(args, ", "))
+
+	out.WriteString(")")
+
+	return out.String()
+}
+
+type MemberExpression struct {
+	*Attr
+	Object   Expression
+	Property Expression
+	Computed bool
+}
+
+func (e *MemberExpression) expressionNode() {}
+
+func (e *MemberExpression) patternNode() {}
+
+func (m *MemberExpression) GetAttr() *Attr {
+	return m.Attr
+}
+
+func (e *MemberExpression) String() string {
+	return fmt.Sprintf("%s.%s", e.Object, e.Property)
+}
+
+type AssignmentExpression struct {
+	*Attr
+	Operator AssignmentOperator
+	Left     Pattern
+	Right    Expression
+}
+
+func (a *AssignmentExpression) expressionNode() {}
+
+func (a *AssignmentExpression) GetAttr() *Attr {
+	return a.Attr
+}
+
+func (a *AssignmentExpression) String() string {
+	return fmt.Sprintf("%s %s %s", a.Left, a.Operator, a.Right)
+}
+
+type AssignmentOperator string
+
+type BinaryExpression struct {
+	*Attr
+	Operator BinaryOperator
+	Left     Expression
+	Right    Expression
+}
+
+func (b *BinaryExpression) expressionNode() {}
+
+func (b *BinaryExpression) GetAttr() *Attr {
+	return b.Attr
+}
+
+func (a *BinaryExpression) String() string {
+	return fmt.Sprintf("%s %s %s", a.Left, a.Operator, a.Right)
+}
+
+type BinaryOperator string
+
+type UnaryExpression struct {
+	*Attr
+	Operator UnaryOperator
+	Prefix   bool
+	Argument Expression
+}
+
+func (u *UnaryExpression) expressionNode() {}
+
+func (u *UnaryExpression) GetAttr() *Attr {
+	return u.Attr
+}
+
+func (u *UnaryExpression) String() string {
+	if len(u.Operator) > 1 {
+		return fmt.Sprintf("%s %s", u.Operator, u.Argument)
+	}
+
+	return fmt.Sprintf("%s%s", u.Operator, u.Argument)
+}
+
+type UnaryOperator string
+
+type UpdateExpression struct {
+	*Attr
+	Operator UpdateOperator
+	Prefix   bool
+	Argument Expression
 }
 
-type VariableDeclaration struct {
+func (u *UpdateExpression) expressionNode() {}
+
+func (u *UpdateExpression) GetAttr() *Attr {
+	return u.Attr
+}
+
+func (u *UpdateExpression) String() string {
+	if u.Prefix {
+		return fmt.Sprintf("%s%s", u.Operator, u.Argument)
+	}
+
+	return fmt.Sprintf("%s%s", u.Argument, u.Operator)
+}
+
+type UpdateOperator string
+
+type LogicalExpression struct {
 	*Attr
-	Declarations []*VariableDeclarator
-	Kind         string
+	Operator LogicalOperator
+	Left     Expression
+	Right    Expression
 }
 
-func (v *VariableDeclaration) statementNode() {}
+func (l *LogicalExpression) expressionNode() {}
 
-func (v *VariableDeclaration) declarationNode() {}
+func (l *LogicalExpression) GetAttr() *Attr {
+	return l.Attr
+}
 
-func (v *VariableDeclaration) GetAttr() *Attr {
-	return v.Attr
+func (l *LogicalExpression) String() string {
+	return fmt.Sprintf("%s %s %s", l.Left, l.Operator, l.Right)
 }
 
-func (v *VariableDeclaration) String() string {
-	var out bytes.Buffer
+type LogicalOperator string
 
-	out.WriteString(v.Kind)
-	out.WriteString(" ")
-	for _, d := range v.Declarations {
-		out.WriteString(d.String())
+type ConditionalExpression struct {
+	*Attr
+	Test       Expression
+	Consequent Expression
+	Alternate  Expression
+}
+
+func (c *ConditionalExpression) expressionNode() {}
+
+func (c *ConditionalExpression) GetAttr() *Attr {
+	return c.Attr
+}
+
+func (c *ConditionalExpression) String() string {
+	return fmt.Sprintf("%s ? %s : %s", c.Test, c.Consequent, c.Alternate)
+}
+
+type SequenceExpression struct {
+	*Attr
+	Expressions []Expression
+}
+
+func (s *SequenceExpression) expressionNode() {}
+
+func (s *SequenceExpression) GetAttr() *Attr {
+	return s.Attr
+}
+
+func (s *SequenceExpression) String() string {
+	var exprs []string
+	for _, e := range s.Expressions {
+		exprs = append(exprs, e.String())
 	}
 
-	return out.String()
+	return strings.Join(exprs, ", ")
 }
 
-type VariableDeclarator struct {
+// TemplateLiteral is a template literal, which has one more quasi than it
+// has expressions
+type TemplateLiteral struct {
 	*Attr
-	ID   *Identifier
-	Init Expression
+	Quasis      []*TemplateElement
+	Expressions []Expression
 }
 
-func (v *VariableDeclarator) GetAttr() *Attr {
-	return v.Attr
+func (t *TemplateLiteral) expressionNode() {}
+
+func (t *TemplateLiteral) GetAttr() *Attr {
+	return t.Attr
 }
 
-func (v *VariableDeclarator) String() string {
+func (t *TemplateLiteral) String() string {
 	var out bytes.Buffer
 
-	out.WriteString(v.ID.String())
-	if v.Init != nil {
-		out.WriteString(" = ")
-		out.WriteString(v.Init.String())
+	out.WriteString("`")
+	for i, q := range t.Quasis {
+		out.WriteString(q.Raw)
+		if i < len(t.Expressions) {
+			out.WriteString("${")
+			out.WriteString(t.Expressions[i].String())
+			out.WriteString("}")
+		}
 	}
+	out.WriteString("`")
 
 	return out.String()
 }
 
-// expressions
+// TemplateElement is a string part of a template literal
+// Cooked is the string with escape sequences interpreted, Raw is the source
+// text.
+type TemplateElement struct {
+	*Attr
+	Raw    string
+	Cooked string
+	Tail   bool
+}
 
-type Expression interface {
-	Node
-	expressionNode()
+func (t *TemplateElement) GetAttr() *Attr {
+	return t.Attr
 }
 
-type Identifier struct {
+func (t *TemplateElement) String() string {
+	return t.Raw
+}
+
+// TaggedTemplateExpression calls a tag function with the strings and the
+// values of a template literal
+type TaggedTemplateExpression struct {
 	*Attr
-	Name string
+	Tag   Expression
+	Quasi *TemplateLiteral
 }
 
-func (i *Identifier) expressionNode() {}
+func (t *TaggedTemplateExpression) expressionNode() {}
 
-func (i *Identifier) GetAttr() *Attr {
-	return i.Attr
+func (t *TaggedTemplateExpression) GetAttr() *Attr {
+	return t.Attr
 }
 
-func (i *Identifier) String() string {
-	return i.Name
+func (t *TaggedTemplateExpression) String() string {
+	return t.Tag.String() + t.Quasi.String()
 }
 
-type CallExpression struct {
+type ThisExpression struct {
+	*Attr
+}
+
+func (t *ThisExpression) expressionNode() {}
+
+func (t *ThisExpression) GetAttr() *Attr {
+	return t.Attr
+}
+
+func (t *ThisExpression) String() string {
+	return "this"
+}
+
+// Super is the super keyword of a super call or a super property reference
+type Super struct {
+	*Attr
+}
+
+func (s *Super) expressionNode() {}
+
+func (s *Super) GetAttr() *Attr {
+	return s.Attr
+}
+
+func (s *Super) String() string {
+	return "super"
+}
+
+type NewExpression struct {
 	*Attr
 	Callee    Expression
 	Arguments []Expression
 }
 
-func (c *CallExpression) expressionNode() {}
+func (n *NewExpression) expressionNode() {}
 
-func (c *CallExpression) GetAttr() *Attr {
+func (n *NewExpression) GetAttr() *Attr {
+	return n.Attr
+}
+
+func (n *NewExpression) String() string {
+	var args []string
+	for _, arg := range n.Arguments {
+		args = append(args, arg.String())
+	}
+
+	return fmt.Sprintf("new %s(%s)", n.Callee, strings.Join(args, ", "))
+}
+
+// MetaProperty is a meta property like new.target
+type MetaProperty struct {
+	*Attr
+	Meta     *Identifier
+	Property *Identifier
+}
+
+func (m *MetaProperty) expressionNode() {}
+
+func (m *MetaProperty) GetAttr() *Attr {
+	return m.Attr
+}
+
+func (m *MetaProperty) String() string {
+	return fmt.Sprintf("%s.%s", m.Meta, m.Property)
+}
+
+type ClassExpression struct {
+	*Attr
+	*Class
+}
+
+func (c *ClassExpression) expressionNode() {}
+
+func (c *ClassExpression) GetAttr() *Attr {
 	return c.Attr
 }
 
-func (c *CallExpression) String() string {
+// functions
+
+// Function holds the parts shared by function declarations and expressions
+type Function struct {
+	ID        *Identifier
+	Params    []Pattern
+	Body      *BlockStatement
+	Generator bool
+	Async     bool
+}
+
+func (f *Function) String() string {
 	var out bytes.Buffer
 
-	out.WriteString(c.Callee.String())
+	if f.Async {
+		out.WriteString("async ")
+	}
+	out.WriteString("function")
+	if f.Generator {
+		out.WriteString("*")
+	}
+	if f.ID != nil {
+		out.WriteString(" ")
+		out.WriteString(f.ID.String())
+	}
 	out.WriteString("(")
 
-	var args []string
-	for _, arg := range c.Arguments {
-		args = append(args, arg.String())
+	var params []string
+	for _, p := range f.Params {
+		params = append(params, p.String())
 	}
-	out.WriteString(strings.Join(args, ", "))
+	out.WriteString(strings.Join(params, ", "))
 
-	out.WriteString(")")
+	out.WriteString(") ")
+	out.WriteString(f.Body.String())
 
 	return out.String()
 }
 
-type MemberExpression struct {
+// Directive is a directive like "use strict" in the prologue of a script or
+// a function body
+type Directive struct {
 	*Attr
-	Object   Expression
-	Property Expression
-	Computed bool
+	Value string
 }
 
-func (e *MemberExpression) expressionNode() {}
+// HasUseStrict reports whether directives contain "use strict"
+func HasUseStrict(directives []*Directive) bool {
+	for _, d := range directives {
+		if d.Value == "use strict" {
+			return true
+		}
+	}
 
-func (m *MemberExpression) GetAttr() *Attr {
-	return m.Attr
+	return false
 }
 
-func (e *MemberExpression) String() string {
-	return fmt.Sprintf("%s.%s", e.Object, e.Property)
+// patterns
+
+// Pattern is the target of a binding or of an assignment, which is an
+// identifier, a member expression or a destructuring pattern
+type Pattern interface {
+	Node
+	patternNode()
 }
 
-type AssignmentExpression struct {
+// ObjectPattern destructures the properties of an object
+// Rest collects the remaining own enumerable properties.
+type ObjectPattern struct {
 	*Attr
-	Operator AssignmentOperator
-	Left     Expression
-	Right    Expression
+	Properties []*AssignmentProperty
+	Rest       *RestElement
 }
 
-func (a *AssignmentExpression) expressionNode() {}
+func (o *ObjectPattern) patternNode() {}
 
-func (a *AssignmentExpression) GetAttr() *Attr {
+func (o *ObjectPattern) GetAttr() *Attr {
+	return o.Attr
+}
+
+func (o *ObjectPattern) String() string {
+	var props []string
+	for _, p := range o.Properties {
+		props = append(props, p.String())
+	}
+	if o.Rest != nil {
+		props = append(props, o.Rest.String())
+	}
+
+	return "{ " + strings.Join(props, ", ") + " }"
+}
+
+// AssignmentProperty is a property of an object pattern, whose value is
+// bound to a pattern
+type AssignmentProperty struct {
+	*Attr
+	Key       Expression
+	Value     Pattern
+	Computed  bool
+	Shorthand bool
+}
+
+func (a *AssignmentProperty) GetAttr() *Attr {
 	return a.Attr
 }
 
-func (a *AssignmentExpression) String() string {
-	return fmt.Sprintf("%s %s %s", a.Left, a.Operator, a.Right)
+func (a *AssignmentProperty) String() string {
+	if a.Shorthand {
+		return a.Value.String()
+	}
+	if a.Computed {
+		return fmt.Sprintf("[%s]: %s", a.Key, a.Value)
+	}
+
+	return fmt.Sprintf("%s: %s", a.Key, a.Value)
 }
 
-type AssignmentOperator string
+// ArrayPattern destructures the values of an iterable
+// Elements that are left out, like in [a, , b], are nil, and the last
+// element may be a RestElement.
+type ArrayPattern struct {
+	*Attr
+	Elements []Pattern
+}
 
-type BinaryExpression struct {
+func (a *ArrayPattern) patternNode() {}
+
+func (a *ArrayPattern) GetAttr() *Attr {
+	return a.Attr
+}
+
+func (a *ArrayPattern) String() string {
+	var elements []string
+	for _, e := range a.Elements {
+		if e == nil {
+			elements = append(elements, "")
+		} else {
+			elements = append(elements, e.String())
+		}
+	}
+
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+// AssignmentPattern is a pattern with a default value, which is used when
+// the value is undefined
+type AssignmentPattern struct {
 	*Attr
-	Operator BinaryOperator
-	Left     Expression
-	Right    Expression
+	Left  Pattern
+	Right Expression
 }
 
-func (b *BinaryExpression) expressionNode() {}
+func (a *AssignmentPattern) patternNode() {}
 
-func (b *BinaryExpression) GetAttr() *Attr {
-	return b.Attr
+func (a *AssignmentPattern) GetAttr() *Attr {
+	return a.Attr
 }
 
-func (a *BinaryExpression) String() string {
-	return fmt.Sprintf("%s %s %s", a.Left, a.Operator, a.Right)
+func (a *AssignmentPattern) String() string {
+	return fmt.Sprintf("%s = %s", a.Left, a.Right)
 }
 
-type BinaryOperator string
+// RestElement collects the remaining values of an array pattern or the
+// remaining arguments of a function
+type RestElement struct {
+	*Attr
+	Argument Pattern
+}
+
+func (r *RestElement) patternNode() {}
+
+func (r *RestElement) GetAttr() *Attr {
+	return r.Attr
+}
+
+func (r *RestElement) String() string {
+	return "..." + r.Argument.String()
+}
+
+// BoundNames returns the identifiers that a binding pattern declares
+func BoundNames(p Pattern) []*Identifier {
+	switch v := p.(type) {
+	case *Identifier:
+		return []*Identifier{v}
+	case *ObjectPattern:
+		var names []*Identifier
+		for _, prop := range v.Properties {
+			names = append(names, BoundNames(prop.Value)...)
+		}
+		if v.Rest != nil {
+			names = append(names, BoundNames(v.Rest)...)
+		}
+
+		return names
+	case *ArrayPattern:
+		var names []*Identifier
+		for _, e := range v.Elements {
+			if e != nil {
+				names = append(names, BoundNames(e)...)
+			}
+		}
+
+		return names
+	case *AssignmentPattern:
+		return BoundNames(v.Left)
+	case *RestElement:
+		return BoundNames(v.Argument)
+	default:
+		return nil
+	}
+}
+
+// classes
+
+// Class holds the parts shared by class declarations and expressions
+type Class struct {
+	ID         *Identifier
+	SuperClass Expression
+	Body       *ClassBody
+}
+
+func (c *Class) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("class")
+	if c.ID != nil {
+		out.WriteString(" ")
+		out.WriteString(c.ID.String())
+	}
+	if c.SuperClass != nil {
+		out.WriteString(" extends ")
+		out.WriteString(c.SuperClass.String())
+	}
+	out.WriteString(" ")
+	out.WriteString(c.Body.String())
+
+	return out.String()
+}
+
+type ClassBody struct {
+	*Attr
+	Body []*ClassMethod
+}
+
+func (c *ClassBody) GetAttr() *Attr {
+	return c.Attr
+}
+
+func (c *ClassBody) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("{ ")
+	for _, m := range c.Body {
+		out.WriteString(m.String())
+		out.WriteString(" ")
+	}
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// ClassMethod is a method definition of a class body: the constructor, a
+// method, a getter or a setter
+type ClassMethod struct {
+	*Attr
+	*Function
+	Kind     string
+	Key      Expression
+	Computed bool
+	Static   bool
+}
+
+func (m *ClassMethod) GetAttr() *Attr {
+	return m.Attr
+}
+
+func (m *ClassMethod) String() string {
+	var out bytes.Buffer
+
+	if m.Static {
+		out.WriteString("static ")
+	}
+	if m.Kind == "get" || m.Kind == "set" {
+		out.WriteString(m.Kind)
+		out.WriteString(" ")
+	}
+	out.WriteString(propertyKeyString(m.Key, m.Computed))
+	out.WriteString("(")
+
+	var params []string
+	for _, p := range m.Params {
+		params = append(params, p.String())
+	}
+	out.WriteString(strings.Join(params, ", "))
+
+	out.WriteString(") ")
+	out.WriteString(m.Body.String())
+
+	return out.String()
+}
 
 // literals
 
@@ -293,6 +1462,39 @@ func (s *StringLiteral) String() string {
 	return fmt.Sprintf(`"%s"`, s.Value)
 }
 
+type BooleanLiteral struct {
+	*Attr
+	Value bool
+}
+
+func (b *BooleanLiteral) expressionNode() {}
+
+func (b *BooleanLiteral) literalNode() {}
+
+func (b *BooleanLiteral) GetAttr() *Attr {
+	return b.Attr
+}
+
+func (b *BooleanLiteral) String() string {
+	return fmt.Sprintf("%t", b.Value)
+}
+
+type NullLiteral struct {
+	*Attr
+}
+
+func (n *NullLiteral) expressionNode() {}
+
+func (n *NullLiteral) literalNode() {}
+
+func (n *NullLiteral) GetAttr() *Attr {
+	return n.Attr
+}
+
+func (n *NullLiteral) String() string {
+	return "null"
+}
+
 // TODO: Value is always float64
 // Can delay conversion and adapt to int vs. float
 type NumericLiteral struct {
@@ -310,5 +1512,5 @@ func (n *NumericLiteral) GetAttr() *Attr {
 }
 
 func (n *NumericLiteral) String() string {
-	return fmt.Sprintf("%.16f", n.Value)
+	return strconv.FormatFloat(n.Value, 'g', -1, 64)
 }
@@ -1,10 +1,13 @@
 package ast
 
+import "math"
+
 func unmarshalProgram(m m) *Program {
 	p := &Program{}
 	p.Attr = unmarshalAttr(m)
 	p.SourceType = convertString(m["sourceType"])
 	p.Body = unmarshalStatements(convertSliceMap(m["body"]))
+	p.Directives = unmarshalDirectives(m["directives"])
 
 	return p
 }
@@ -60,8 +63,42 @@ func unmarshalStatement(m m) Statement {
 	switch t {
 	case "VariableDeclaration":
 		s = unmarshalVariableDeclaration(m)
+	case "FunctionDeclaration":
+		s = unmarshalFunctionDeclaration(m)
 	case "ExpressionStatement":
 		s = unmarshalExpressionStatement(m)
+	case "BlockStatement":
+		s = unmarshalBlockStatement(m)
+	case "EmptyStatement":
+		s = &EmptyStatement{unmarshalAttr(m)}
+	case "ReturnStatement":
+		s = unmarshalReturnStatement(m)
+	case "IfStatement":
+		s = unmarshalIfStatement(m)
+	case "ForStatement":
+		s = unmarshalForStatement(m)
+	case "ForInStatement":
+		s = unmarshalForInStatement(m)
+	case "ForOfStatement":
+		s = unmarshalForOfStatement(m)
+	case "WhileStatement":
+		s = unmarshalWhileStatement(m)
+	case "DoWhileStatement":
+		s = unmarshalDoWhileStatement(m)
+	case "BreakStatement":
+		s = unmarshalBreakStatement(m)
+	case "ContinueStatement":
+		s = unmarshalContinueStatement(m)
+	case "LabeledStatement":
+		s = unmarshalLabeledStatement(m)
+	case "ThrowStatement":
+		s = unmarshalThrowStatement(m)
+	case "TryStatement":
+		s = unmarshalTryStatement(m)
+	case "SwitchStatement":
+		s = unmarshalSwitchStatement(m)
+	case "ClassDeclaration":
+		s = unmarshalClassDeclaration(m)
 	default:
 		panic("unsupport statement type " + t)
 	}
@@ -77,6 +114,206 @@ func unmarshalExpressionStatement(m m) *ExpressionStatement {
 	return e
 }
 
+func unmarshalBlockStatement(m m) *BlockStatement {
+	b := &BlockStatement{}
+	b.Attr = unmarshalAttr(m)
+	b.Body = unmarshalStatements(convertSliceMap(m["body"]))
+	b.Directives = unmarshalDirectives(m["directives"])
+
+	return b
+}
+
+func unmarshalReturnStatement(m m) *ReturnStatement {
+	r := &ReturnStatement{}
+	r.Attr = unmarshalAttr(m)
+	if arg := m["argument"]; arg != nil {
+		r.Argument = unmarshalExpression(convertMap(arg))
+	}
+
+	return r
+}
+
+func unmarshalThrowStatement(m m) *ThrowStatement {
+	t := &ThrowStatement{}
+	t.Attr = unmarshalAttr(m)
+	t.Argument = unmarshalExpression(convertMap(m["argument"]))
+
+	return t
+}
+
+func unmarshalTryStatement(m m) *TryStatement {
+	t := &TryStatement{}
+	t.Attr = unmarshalAttr(m)
+	t.Block = unmarshalBlockStatement(convertMap(m["block"]))
+	if h := m["handler"]; h != nil {
+		t.Handler = unmarshalCatchClause(convertMap(h))
+	}
+	if f := m["finalizer"]; f != nil {
+		t.Finalizer = unmarshalBlockStatement(convertMap(f))
+	}
+
+	return t
+}
+
+func unmarshalCatchClause(m m) *CatchClause {
+	c := &CatchClause{}
+	c.Attr = unmarshalAttr(m)
+	if p := m["param"]; p != nil {
+		c.Param = unmarshalPattern(convertMap(p))
+	}
+	c.Body = unmarshalBlockStatement(convertMap(m["body"]))
+
+	return c
+}
+
+func unmarshalSwitchStatement(m m) *SwitchStatement {
+	s := &SwitchStatement{}
+	s.Attr = unmarshalAttr(m)
+	s.Discriminant = unmarshalExpression(convertMap(m["discriminant"]))
+	for _, c := range convertSliceMap(m["cases"]) {
+		s.Cases = append(s.Cases, unmarshalSwitchCase(c))
+	}
+
+	return s
+}
+
+func unmarshalSwitchCase(m m) *SwitchCase {
+	c := &SwitchCase{}
+	c.Attr = unmarshalAttr(m)
+	if test := m["test"]; test != nil {
+		c.Test = unmarshalExpression(convertMap(test))
+	}
+	c.Consequent = unmarshalStatements(convertSliceMap(m["consequent"]))
+
+	return c
+}
+
+func unmarshalIfStatement(m m) *IfStatement {
+	i := &IfStatement{}
+	i.Attr = unmarshalAttr(m)
+	i.Test = unmarshalExpression(convertMap(m["test"]))
+	i.Consequent = unmarshalStatement(convertMap(m["consequent"]))
+	if alt := m["alternate"]; alt != nil {
+		i.Alternate = unmarshalStatement(convertMap(alt))
+	}
+
+	return i
+}
+
+func unmarshalForStatement(m m) *ForStatement {
+	f := &ForStatement{}
+	f.Attr = unmarshalAttr(m)
+	if init := m["init"]; init != nil {
+		if i := convertMap(init); convertString(i["type"]) == "VariableDeclaration" {
+			f.Init = unmarshalVariableDeclaration(i)
+		} else {
+			f.Init = unmarshalExpression(i)
+		}
+	}
+	if test := m["test"]; test != nil {
+		f.Test = unmarshalExpression(convertMap(test))
+	}
+	if update := m["update"]; update != nil {
+		f.Update = unmarshalExpression(convertMap(update))
+	}
+	f.Body = unmarshalStatement(convertMap(m["body"]))
+
+	return f
+}
+
+func unmarshalForInStatement(m m) *ForInStatement {
+	f := &ForInStatement{}
+	f.Attr = unmarshalAttr(m)
+	f.Left = unmarshalForLeft(convertMap(m["left"]))
+	f.Right = unmarshalExpression(convertMap(m["right"]))
+	f.Body = unmarshalStatement(convertMap(m["body"]))
+
+	return f
+}
+
+func unmarshalForOfStatement(m m) *ForOfStatement {
+	f := &ForOfStatement{}
+	f.Attr = unmarshalAttr(m)
+	f.Left = unmarshalForLeft(convertMap(m["left"]))
+	f.Right = unmarshalExpression(convertMap(m["right"]))
+	f.Body = unmarshalStatement(convertMap(m["body"]))
+
+	return f
+}
+
+// unmarshalForLeft unmarshals the declaration or the assignment target of a
+// for-in or for-of statement
+func unmarshalForLeft(m m) Node {
+	if convertString(m["type"]) == "VariableDeclaration" {
+		return unmarshalVariableDeclaration(m)
+	}
+
+	return unmarshalPattern(m)
+}
+
+func unmarshalWhileStatement(m m) *WhileStatement {
+	w := &WhileStatement{}
+	w.Attr = unmarshalAttr(m)
+	w.Test = unmarshalExpression(convertMap(m["test"]))
+	w.Body = unmarshalStatement(convertMap(m["body"]))
+
+	return w
+}
+
+func unmarshalDoWhileStatement(m m) *DoWhileStatement {
+	d := &DoWhileStatement{}
+	d.Attr = unmarshalAttr(m)
+	d.Body = unmarshalStatement(convertMap(m["body"]))
+	d.Test = unmarshalExpression(convertMap(m["test"]))
+
+	return d
+}
+
+func unmarshalBreakStatement(m m) *BreakStatement {
+	b := &BreakStatement{}
+	b.Attr = unmarshalAttr(m)
+	if label := m["label"]; label != nil {
+		b.Label = unmarshalIdentifier(convertMap(label))
+	}
+
+	return b
+}
+
+func unmarshalContinueStatement(m m) *ContinueStatement {
+	c := &ContinueStatement{}
+	c.Attr = unmarshalAttr(m)
+	if label := m["label"]; label != nil {
+		c.Label = unmarshalIdentifier(convertMap(label))
+	}
+
+	return c
+}
+
+func unmarshalLabeledStatement(m m) *LabeledStatement {
+	l := &LabeledStatement{}
+	l.Attr = unmarshalAttr(m)
+	l.Label = unmarshalIdentifier(convertMap(m["label"]))
+	l.Body = unmarshalStatement(convertMap(m["body"]))
+
+	return l
+}
+
+func unmarshalFunctionDeclaration(m m) *FunctionDeclaration {
+	f := &FunctionDeclaration{}
+	f.Attr = unmarshalAttr(m)
+	f.Function = unmarshalFunction(m)
+
+	return f
+}
+
+func unmarshalClassDeclaration(m m) *ClassDeclaration {
+	c := &ClassDeclaration{}
+	c.Attr = unmarshalAttr(m)
+	c.Class = unmarshalClass(m)
+
+	return c
+}
+
 func unmarshalVariableDeclaration(m m) *VariableDeclaration {
 	v := &VariableDeclaration{}
 	v.Attr = unmarshalAttr(m)
@@ -107,6 +344,18 @@ func unmarshalExpression(m m) Expression {
 		e = unmarshalStringLiteral(m)
 	case "NumericLiteral":
 		e = unmarshalNumericLiteral(m)
+	case "BooleanLiteral":
+		e = unmarshalBooleanLiteral(m)
+	case "NullLiteral":
+		e = unmarshalNullLiteral(m)
+	case "FunctionExpression":
+		e = unmarshalFunctionExpression(m)
+	case "ArrowFunctionExpression":
+		e = unmarshalArrowFunctionExpression(m)
+	case "ArrayExpression":
+		e = unmarshalArrayExpression(m)
+	case "ObjectExpression":
+		e = unmarshalObjectExpression(m)
 	case "CallExpression":
 		e = unmarshalCallExpression(m)
 	case "MemberExpression":
@@ -115,6 +364,36 @@ func unmarshalExpression(m m) Expression {
 		e = unmarshalAssignmentExpression(m)
 	case "BinaryExpression":
 		e = unmarshalBinaryExpression(m)
+	case "UnaryExpression":
+		e = unmarshalUnaryExpression(m)
+	case "UpdateExpression":
+		e = unmarshalUpdateExpression(m)
+	case "LogicalExpression":
+		e = unmarshalLogicalExpression(m)
+	case "ConditionalExpression":
+		e = unmarshalConditionalExpression(m)
+	case "SequenceExpression":
+		e = unmarshalSequenceExpression(m)
+	case "ThisExpression":
+		e = &ThisExpression{unmarshalAttr(m)}
+	case "Super":
+		e = &Super{unmarshalAttr(m)}
+	case "NewExpression":
+		e = unmarshalNewExpression(m)
+	case "MetaProperty":
+		e = unmarshalMetaProperty(m)
+	case "ClassExpression":
+		e = unmarshalClassExpression(m)
+	case "TemplateLiteral":
+		e = unmarshalTemplateLiteral(m)
+	case "TaggedTemplateExpression":
+		e = unmarshalTaggedTemplateExpression(m)
+	case "SpreadElement":
+		e = unmarshalSpreadElement(m)
+	case "YieldExpression":
+		e = unmarshalYieldExpression(m)
+	case "AwaitExpression":
+		e = unmarshalAwaitExpression(m)
 	default:
 		panic("unsupport expression type " + t)
 	}
@@ -130,6 +409,107 @@ func unmarshalIdentifier(m m) *Identifier {
 	return i
 }
 
+func unmarshalFunctionExpression(m m) *FunctionExpression {
+	f := &FunctionExpression{}
+	f.Attr = unmarshalAttr(m)
+	f.Function = unmarshalFunction(m)
+
+	return f
+}
+
+func unmarshalArrowFunctionExpression(m m) *ArrowFunctionExpression {
+	a := &ArrowFunctionExpression{}
+	a.Attr = unmarshalAttr(m)
+	a.Function = unmarshalFunction(m)
+	a.Expression = convertBool(m["expression"])
+	if a.Expression {
+		// a concise body is a shorthand for a block that returns it
+		ret := unmarshalExpression(convertMap(m["body"]))
+		a.Body = &BlockStatement{Attr: ret.GetAttr(), Body: []Statement{&ReturnStatement{Attr: ret.GetAttr(), Argument: ret}}}
+	}
+
+	return a
+}
+
+func unmarshalArrayExpression(m m) *ArrayExpression {
+	a := &ArrayExpression{}
+	a.Attr = unmarshalAttr(m)
+	for _, e := range m["elements"].([]interface{}) {
+		if e == nil {
+			a.Elements = append(a.Elements, nil)
+		} else {
+			a.Elements = append(a.Elements, unmarshalExpression(convertMap(e)))
+		}
+	}
+
+	return a
+}
+
+func unmarshalObjectExpression(m m) *ObjectExpression {
+	o := &ObjectExpression{}
+	o.Attr = unmarshalAttr(m)
+	for _, p := range convertSliceMap(m["properties"]) {
+		o.Properties = append(o.Properties, unmarshalProperty(p))
+	}
+
+	return o
+}
+
+func unmarshalProperty(m m) Property {
+	t := convertString(m["type"])
+	switch t {
+	case "ObjectProperty":
+		p := &ObjectProperty{}
+		p.Attr = unmarshalAttr(m)
+		p.Key = unmarshalExpression(convertMap(m["key"]))
+		p.Value = unmarshalExpression(convertMap(m["value"]))
+		p.Computed = convertBool(m["computed"])
+		p.Shorthand = convertBool(m["shorthand"])
+
+		return p
+	case "ObjectMethod":
+		p := &ObjectMethod{}
+		p.Attr = unmarshalAttr(m)
+		p.Function = unmarshalFunction(m)
+		p.Kind = convertString(m["kind"])
+		p.Key = unmarshalExpression(convertMap(m["key"]))
+		p.Computed = convertBool(m["computed"])
+
+		return p
+	case "SpreadElement", "SpreadProperty":
+		return unmarshalSpreadElement(m)
+	default:
+		panic("unsupport property type " + t)
+	}
+}
+
+func unmarshalSpreadElement(m m) *SpreadElement {
+	s := &SpreadElement{}
+	s.Attr = unmarshalAttr(m)
+	s.Argument = unmarshalExpression(convertMap(m["argument"]))
+
+	return s
+}
+
+func unmarshalYieldExpression(m m) *YieldExpression {
+	y := &YieldExpression{}
+	y.Attr = unmarshalAttr(m)
+	if arg := m["argument"]; arg != nil {
+		y.Argument = unmarshalExpression(convertMap(arg))
+	}
+	y.Delegate = convertBool(m["delegate"])
+
+	return y
+}
+
+func unmarshalAwaitExpression(m m) *AwaitExpression {
+	a := &AwaitExpression{}
+	a.Attr = unmarshalAttr(m)
+	a.Argument = unmarshalExpression(convertMap(m["argument"]))
+
+	return a
+}
+
 func unmarshalCallExpression(m m) *CallExpression {
 	c := &CallExpression{}
 	c.Attr = unmarshalAttr(m)
@@ -152,7 +532,7 @@ func unmarshalMemberExpression(m m) *MemberExpression {
 func unmarshalAssignmentExpression(m m) *AssignmentExpression {
 	a := &AssignmentExpression{}
 	a.Attr = unmarshalAttr(m)
-	a.Left = unmarshalExpression(convertMap(m["left"]))
+	a.Left = unmarshalPattern(convertMap(m["left"]))
 	a.Right = unmarshalExpression(convertMap(m["right"]))
 	a.Operator = AssignmentOperator(convertString(m["operator"]))
 
@@ -169,12 +549,117 @@ func unmarshalBinaryExpression(m m) *BinaryExpression {
 	return b
 }
 
+func unmarshalUnaryExpression(m m) *UnaryExpression {
+	u := &UnaryExpression{}
+	u.Attr = unmarshalAttr(m)
+	u.Operator = UnaryOperator(convertString(m["operator"]))
+	u.Prefix = convertBool(m["prefix"])
+	u.Argument = unmarshalExpression(convertMap(m["argument"]))
+
+	return u
+}
+
+func unmarshalUpdateExpression(m m) *UpdateExpression {
+	u := &UpdateExpression{}
+	u.Attr = unmarshalAttr(m)
+	u.Operator = UpdateOperator(convertString(m["operator"]))
+	u.Prefix = convertBool(m["prefix"])
+	u.Argument = unmarshalExpression(convertMap(m["argument"]))
+
+	return u
+}
+
+func unmarshalLogicalExpression(m m) *LogicalExpression {
+	l := &LogicalExpression{}
+	l.Attr = unmarshalAttr(m)
+	l.Left = unmarshalExpression(convertMap(m["left"]))
+	l.Right = unmarshalExpression(convertMap(m["right"]))
+	l.Operator = LogicalOperator(convertString(m["operator"]))
+
+	return l
+}
+
+func unmarshalConditionalExpression(m m) *ConditionalExpression {
+	c := &ConditionalExpression{}
+	c.Attr = unmarshalAttr(m)
+	c.Test = unmarshalExpression(convertMap(m["test"]))
+	c.Consequent = unmarshalExpression(convertMap(m["consequent"]))
+	c.Alternate = unmarshalExpression(convertMap(m["alternate"]))
+
+	return c
+}
+
+func unmarshalSequenceExpression(m m) *SequenceExpression {
+	s := &SequenceExpression{}
+	s.Attr = unmarshalAttr(m)
+	s.Expressions = unmarshalExpressions(convertSliceMap(m["expressions"]))
+
+	return s
+}
+
+func unmarshalTemplateLiteral(m m) *TemplateLiteral {
+	t := &TemplateLiteral{}
+	t.Attr = unmarshalAttr(m)
+	for _, q := range convertSliceMap(m["quasis"]) {
+		t.Quasis = append(t.Quasis, unmarshalTemplateElement(q))
+	}
+	t.Expressions = unmarshalExpressions(convertSliceMap(m["expressions"]))
+
+	return t
+}
+
+func unmarshalTemplateElement(m m) *TemplateElement {
+	t := &TemplateElement{}
+	t.Attr = unmarshalAttr(m)
+	value := convertMap(m["value"])
+	t.Raw = convertString(value["raw"])
+	t.Cooked = convertString(value["cooked"])
+	t.Tail = convertBool(m["tail"])
+
+	return t
+}
+
+func unmarshalTaggedTemplateExpression(m m) *TaggedTemplateExpression {
+	t := &TaggedTemplateExpression{}
+	t.Attr = unmarshalAttr(m)
+	t.Tag = unmarshalExpression(convertMap(m["tag"]))
+	t.Quasi = unmarshalTemplateLiteral(convertMap(m["quasi"]))
+
+	return t
+}
+
+func unmarshalNewExpression(m m) *NewExpression {
+	n := &NewExpression{}
+	n.Attr = unmarshalAttr(m)
+	n.Callee = unmarshalExpression(convertMap(m["callee"]))
+	n.Arguments = unmarshalExpressions(convertSliceMap(m["arguments"]))
+
+	return n
+}
+
+func unmarshalMetaProperty(m m) *MetaProperty {
+	p := &MetaProperty{}
+	p.Attr = unmarshalAttr(m)
+	p.Meta = unmarshalIdentifier(convertMap(m["meta"]))
+	p.Property = unmarshalIdentifier(convertMap(m["property"]))
+
+	return p
+}
+
+func unmarshalClassExpression(m m) *ClassExpression {
+	c := &ClassExpression{}
+	c.Attr = unmarshalAttr(m)
+	c.Class = unmarshalClass(m)
+
+	return c
+}
+
 func unmarshalVariableDeclarator(m []m) []*VariableDeclarator {
 	var d []*VariableDeclarator
 	for _, mm := range m {
 		dd := &VariableDeclarator{}
 		dd.Attr = unmarshalAttr(mm)
-		dd.ID = unmarshalIdentifier(convertMap(mm["id"]))
+		dd.ID = unmarshalPattern(convertMap(mm["id"]))
 		if init := mm["init"]; init != nil {
 			dd.Init = unmarshalExpression(convertMap(init))
 		}
@@ -185,6 +670,157 @@ func unmarshalVariableDeclarator(m []m) []*VariableDeclarator {
 	return d
 }
 
+func unmarshalDirectives(i interface{}) []*Directive {
+	if i == nil {
+		return nil
+	}
+
+	var directives []*Directive
+	for _, m := range convertSliceMap(i) {
+		d := &Directive{}
+		d.Attr = unmarshalAttr(m)
+		d.Value = convertString(convertMap(m["value"])["value"])
+		directives = append(directives, d)
+	}
+
+	return directives
+}
+
+// functions
+
+func unmarshalFunction(m m) *Function {
+	f := &Function{}
+	if id := m["id"]; id != nil {
+		f.ID = unmarshalIdentifier(convertMap(id))
+	}
+	for _, p := range convertSliceMap(m["params"]) {
+		f.Params = append(f.Params, unmarshalPattern(p))
+	}
+	if body := convertMap(m["body"]); body["type"] == "BlockStatement" {
+		f.Body = unmarshalBlockStatement(body)
+	}
+	f.Generator = convertBool(m["generator"])
+	f.Async = convertBool(m["async"])
+
+	return f
+}
+
+// patterns
+
+func unmarshalPattern(m m) Pattern {
+	var p Pattern
+
+	t := convertString(m["type"])
+	switch t {
+	case "Identifier":
+		p = unmarshalIdentifier(m)
+	case "MemberExpression":
+		p = unmarshalMemberExpression(m)
+	case "ObjectPattern":
+		p = unmarshalObjectPattern(m)
+	case "ArrayPattern":
+		p = unmarshalArrayPattern(m)
+	case "AssignmentPattern":
+		p = unmarshalAssignmentPattern(m)
+	case "RestElement":
+		p = unmarshalRestElement(m)
+	default:
+		panic("unsupport pattern type " + t)
+	}
+
+	return p
+}
+
+func unmarshalObjectPattern(m m) *ObjectPattern {
+	o := &ObjectPattern{}
+	o.Attr = unmarshalAttr(m)
+	for _, p := range convertSliceMap(m["properties"]) {
+		if t := convertString(p["type"]); t == "RestElement" || t == "RestProperty" {
+			o.Rest = unmarshalRestElement(p)
+			continue
+		}
+
+		a := &AssignmentProperty{}
+		a.Attr = unmarshalAttr(p)
+		a.Key = unmarshalExpression(convertMap(p["key"]))
+		a.Value = unmarshalPattern(convertMap(p["value"]))
+		a.Computed = convertBool(p["computed"])
+		a.Shorthand = convertBool(p["shorthand"])
+		o.Properties = append(o.Properties, a)
+	}
+
+	return o
+}
+
+func unmarshalArrayPattern(m m) *ArrayPattern {
+	a := &ArrayPattern{}
+	a.Attr = unmarshalAttr(m)
+	for _, e := range m["elements"].([]interface{}) {
+		if e == nil {
+			a.Elements = append(a.Elements, nil)
+		} else {
+			a.Elements = append(a.Elements, unmarshalPattern(convertMap(e)))
+		}
+	}
+
+	return a
+}
+
+func unmarshalAssignmentPattern(m m) *AssignmentPattern {
+	a := &AssignmentPattern{}
+	a.Attr = unmarshalAttr(m)
+	a.Left = unmarshalPattern(convertMap(m["left"]))
+	a.Right = unmarshalExpression(convertMap(m["right"]))
+
+	return a
+}
+
+func unmarshalRestElement(m m) *RestElement {
+	r := &RestElement{}
+	r.Attr = unmarshalAttr(m)
+	r.Argument = unmarshalPattern(convertMap(m["argument"]))
+
+	return r
+}
+
+// classes
+
+func unmarshalClass(m m) *Class {
+	c := &Class{}
+	if id := m["id"]; id != nil {
+		c.ID = unmarshalIdentifier(convertMap(id))
+	}
+	if superClass := m["superClass"]; superClass != nil {
+		c.SuperClass = unmarshalExpression(convertMap(superClass))
+	}
+	c.Body = unmarshalClassBody(convertMap(m["body"]))
+
+	return c
+}
+
+func unmarshalClassBody(m m) *ClassBody {
+	b := &ClassBody{}
+	b.Attr = unmarshalAttr(m)
+	for _, mm := range convertSliceMap(m["body"]) {
+		if t := convertString(mm["type"]); t != "ClassMethod" {
+			panic("unsupport class element type " + t)
+		}
+
+		cm := &ClassMethod{}
+		cm.Attr = unmarshalAttr(mm)
+		cm.Function = unmarshalFunction(mm)
+		cm.Kind = convertString(mm["kind"])
+		cm.Key = unmarshalExpression(convertMap(mm["key"]))
+		cm.Computed = convertBool(mm["computed"])
+		cm.Static = convertBool(mm["static"])
+		b.Body = append(b.Body, cm)
+	}
+
+	return b
+}
+
+// literals
+
 func unmarshalStringLiteral(m m) *StringLiteral {
 	s := &StringLiteral{}
 	s.Attr = unmarshalAttr(m)
@@ -194,10 +830,30 @@ func unmarshalStringLiteral(m m) *StringLiteral {
 	return s
 }
 
+func unmarshalBooleanLiteral(m m) *BooleanLiteral {
+	b := &BooleanLiteral{}
+	b.Attr = unmarshalAttr(m)
+	b.Value = convertBool(m["value"])
+
+	return b
+}
+
+func unmarshalNullLiteral(m m) *NullLiteral {
+	n := &NullLiteral{}
+	n.Attr = unmarshalAttr(m)
+
+	return n
+}
+
 func unmarshalNumericLiteral(m m) *NumericLiteral {
 	n := &NumericLiteral{}
 	n.Attr = unmarshalAttr(m)
-	n.Value = convertFloat(m["value"])
+	if m["value"] == nil {
+		// JSON has no Infinity, which a literal too large for a float64 is
+		n.Value = math.Inf(1)
+	} else {
+		n.Value = convertFloat(m["value"])
+	}
 	n.Extra = unmarshalExtra(convertMap(m["extra"]))
 
 	return n
@@ -15,8 +15,10 @@ import (
 	"github.com/jingweno/godzilla/source"
 )
 
-func Run(parserPath string, r io.Reader) (string, error) {
-	source, err := compileSource(parserPath, r)
+// Run compiles a script to a Go main file and returns the path of the file
+// The filename of the script names it in stack traces.
+func Run(parserPath, filename string, r io.Reader) (string, error) {
+	source, err := compileSource(parserPath, filename, r)
 	if err != nil {
 		return "", err
 	}
@@ -33,7 +35,7 @@ func Run(parserPath string, r io.Reader) (string, error) {
 	return main, nil
 }
 
-func compileSource(parserPath string, r io.Reader) (*source.Code, error) {
+func compileSource(parserPath, filename string, r io.Reader) (*source.Code, error) {
 	c := exec.Command(parserPath)
 	c.Stdin = r
 	stdoutStderr, err := c.CombinedOutput()
@@ -46,7 +48,7 @@ func compileSource(parserPath string, r io.Reader) (*source.Code, error) {
 		return nil, err
 	}
 
-	return compiler.Compile(f), nil
+	return compiler.Compile(f, filename), nil
 }
 
 func writeMainFile(code *source.Code) (string, error) {
@@ -60,7 +62,7 @@ func writeMainFile(code *source.Code) (string, error) {
 		return "", err
 	}
 
-	if err := code.WriteTo(mainFile); err != nil {
+	if _, err := code.WriteTo(mainFile); err != nil {
 		return "", err
 	}
 
@@ -75,7 +77,7 @@ func goFmt(mainFile string) error {
 	cmd := exec.Command("go", "fmt", mainFile)
 	out, err := cmd.CombinedOutput()
 	if err != nil {
-		fmt.Errorf("error running `go fmt %s`: error=%s out=%s", err, out)
+		return fmt.Errorf("error running `go fmt %s`: error=%s out=%s", mainFile, err, out)
 	}
 
 	return nil
@@ -40,7 +40,7 @@ func main() {
 }
 
 func runBuild(cmd *cobra.Command, args []string) error {
-	r := os.Stdin
+	r, filename := os.Stdin, "[stdin]"
 	if len(args) > 0 {
 		f, err := os.Open(args[0])
 		if err != nil {
@@ -48,13 +48,13 @@ func runBuild(cmd *cobra.Command, args []string) error {
 		}
 		defer f.Close()
 
-		r = f
+		r, filename = f, args[0]
 		if buildGoOutFile == "" {
 			buildGoOutFile = strings.TrimSuffix(f.Name(), filepath.Ext(f.Name()))
 		}
 	}
 
-	mainFile, err := build.Run(parserPath, r)
+	mainFile, err := build.Run(parserPath, filename, r)
 	if err != nil {
 		return err
 	}
@@ -70,17 +70,17 @@ func runBuild(cmd *cobra.Command, args []string) error {
 }
 
 func runRun(cmd *cobra.Command, args []string) error {
-	r := os.Stdin
+	r, filename := os.Stdin, "[stdin]"
 	if len(args) > 0 {
 		f, err := os.Open(args[0])
 		if err != nil {
 			return err
 		}
 		defer f.Close()
-		r = f
+		r, filename = f, args[0]
 	}
 
-	mainFile, err := build.Run(parserPath, r)
+	mainFile, err := build.Run(parserPath, filename, r)
 	if err != nil {
 		return err
 	}
@@ -24,17 +24,17 @@ func main() {
 }
 
 func run(cmd *cobra.Command, args []string) error {
-	r := os.Stdin
+	r, filename := os.Stdin, "[stdin]"
 	if len(args) > 0 {
 		f, err := os.Open(args[0])
 		if err != nil {
 			return err
 		}
 		defer f.Close()
-		r = f
+		r, filename = f, args[0]
 	}
 
-	mainFile, err := build.Run(parserPath, r)
+	mainFile, err := build.Run(parserPath, filename, r)
 	if err != nil {
 		return err
 	}
@@ -1,7 +1,13 @@
 package compiler
 
 import (
+	"bytes"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/jingweno/godzilla/ast"
 	"github.com/jingweno/godzilla/runtime"
@@ -9,12 +15,23 @@ import (
 	"github.com/jingweno/godzilla/utils"
 )
 
-func Compile(f *ast.File) *source.Code {
+// Compile compiles a script to the main package of a Go program
+// The filename of the script names it in stack traces.
+func Compile(f *ast.File, filename string) *source.Code {
 	code := source.NewCode()
 
+	r := newResolver()
+	r.resolveProgram(f.Program)
+
 	c := &compiler{
-		code: code,
-		ctx:  runtime.NewDefaultContext(),
+		code:           code,
+		scopes:         r.scopes,
+		refs:           r.refs,
+		checks:         r.checks,
+		labelNames:     r.labelNames,
+		goNames:        r.goNames,
+		blockFunctions: r.blockFunctions,
+		filename:       filename,
 	}
 	c.compile(f)
 
@@ -22,8 +39,80 @@ func Compile(f *ast.File) *source.Code {
 }
 
 type compiler struct {
-	code *source.Code
-	ctx  *runtime.Context
+	code       *source.Code
+	scopes     map[interface{}]*scope
+	refs       map[*ast.Identifier]*binding
+	checks     map[*ast.Identifier]bool
+	labelNames map[*ast.LabeledStatement]string
+	goNames    map[string]bool
+	// blockFunctions are the var bindings of the functions declared in
+	// blocks of sloppy mode code
+	blockFunctions map[*ast.FunctionDeclaration]*binding
+	// labels are the labeled statements enclosing the current statement
+	labels []*label
+	// loopLabel is the Go label of the loop being compiled
+	loopLabel string
+	// loops is the number of loops around the statement being compiled in
+	// the current Go function, and switches the number of switch statements
+	loops    int
+	switches int
+	// try is the try statement whose blocks are being compiled to closures
+	try *tryBlocks
+	// fn is the function being compiled, which is nil for the program
+	fn *function
+	// templates is the number of tagged templates compiled so far
+	templates int
+	// strict is true while compiling strict mode code, where failed
+	// assignments throw
+	strict   bool
+	filename string
+}
+
+// function describes the function being compiled
+type function struct {
+	// newTarget is true for ordinary functions and class constructors, whose
+	// Go function is told new.target
+	newTarget bool
+	// class is the Go variable of the class of a constructor or method, and
+	// home is the Go variable of the object a method is defined on, where
+	// super properties are looked up
+	class string
+	home  string
+	// derived is true for the constructor of a derived class, whose this is
+	// initialized by calling super
+	derived bool
+	// arrow is true for arrow functions, which share this, new.target and
+	// super with the enclosing function, or with the program when global is
+	// true
+	arrow  bool
+	global bool
+}
+
+// tryBlocks tracks the jumps out of the blocks of a try statement, which
+// are compiled to Go closures called by Try, or out of the body of a for-of
+// loop, which is compiled to a Go closure called by ForOf
+// A closure returns a Completion for a return statement, and for a break or
+// continue statement that leaves the closure. The jump is compiled again
+// after the call of Try.
+type tryBlocks struct {
+	// labels is the number of labels around the try statement
+	labels  int
+	returns bool
+	jumps   []ast.Statement
+	// loop is true for the body of a for-of loop, where a break or continue
+	// targets the loop unless it's nested in another loop, and goLabel is the
+	// Go label of the loop
+	loop    bool
+	goLabel string
+}
+
+// label maps a JavaScript label to the Go label of the statement it labels
+// A break out of a labeled loop is a Go break, while a break out of any
+// other labeled statement jumps to the end of the statement.
+type label struct {
+	name    string
+	goLabel string
+	loop    bool
 }
 
 func (c *compiler) compile(f *ast.File) {
@@ -31,7 +120,71 @@ func (c *compiler) compile(f *ast.File) {
 }
 
 func (c *compiler) compileProgram(p *ast.Program) {
-	for _, s := range p.Body {
+	c.strict = c.scopes[p].strict
+	c.declareScope(c.scopes[p])
+	c.compileStatements(p.Body)
+}
+
+// declareScope declares Go variables for the bindings of a scope
+// Parameters and the arguments object are declared by the function prologue,
+// and let and const bindings that may be referenced early start out
+// uninitialized. A var declaration in the body of a function whose
+// parameters have expressions starts out with the value of the parameter of
+// the same name.
+func (c *compiler) declareScope(s *scope) {
+	for _, name := range s.names {
+		b := s.bindings[name]
+		if b.kind == bindingParam || b.kind == bindingArguments {
+			continue
+		}
+
+		var param *binding
+		if s.kind == bodyScope && b.kind == bindingVar {
+			param = s.parent.bindings[name]
+		}
+
+		if param != nil {
+			c.code.WriteLine(fmt.Sprintf("var %s Object = %s", b.goName, param.goName))
+		} else if b.tdz {
+			c.code.WriteLine(fmt.Sprintf("var %s Object = Uninitialized", b.goName))
+		} else {
+			c.code.WriteLine(fmt.Sprintf("var %s Object", b.goName))
+		}
+		c.code.WriteLine(fmt.Sprintf("_ = %s", b.goName))
+	}
+}
+
+// compileStatements compiles a statement list of a program or function body
+// Function declarations are hoisted to the top of the list
+func (c *compiler) compileStatements(stmts []ast.Statement) {
+	c.compileFunctionDeclarations(stmts)
+	c.compileStatementList(stmts)
+}
+
+func (c *compiler) compileFunctionDeclarations(stmts []ast.Statement) {
+	for _, s := range stmts {
+		if fd, ok := s.(*ast.FunctionDeclaration); ok {
+			c.writeLineNo(fd)
+			c.compileFunctionDeclaration(fd)
+			c.code.WriteLine("")
+		}
+	}
+}
+
+// compileStatementList compiles the statements of a list other than the
+// function declarations
+// A function declared in a block of sloppy mode code is assigned to its var
+// binding where the declaration is.
+func (c *compiler) compileStatementList(stmts []ast.Statement) {
+	for _, s := range stmts {
+		if fd, ok := s.(*ast.FunctionDeclaration); ok {
+			if b := c.blockFunctions[fd]; b != nil {
+				c.writeLineNo(fd)
+				c.code.WriteLine(fmt.Sprintf("%s = %s", b.goName, c.refs[fd.ID].goName))
+			}
+			continue
+		}
+
 		c.writeLineNo(s)
 		c.compileStatement(s)
 		c.code.WriteLine("")
@@ -46,152 +199,1753 @@ func (c *compiler) compileStatement(s ast.Statement) {
 		c.compileExpressionStatement(v)
 	case *ast.VariableDeclaration:
 		c.compileVariableDeclaration(v)
+	case *ast.FunctionDeclaration:
+		c.compileFunctionDeclaration(v)
+	case *ast.ReturnStatement:
+		c.compileReturnStatement(v)
+	case *ast.BlockStatement:
+		c.compileBlockStatement(v)
+	case *ast.EmptyStatement:
+	case *ast.IfStatement:
+		c.compileIfStatement(v)
+	case *ast.ForStatement:
+		c.compileForStatement(v)
+	case *ast.ForInStatement:
+		c.compileForInStatement(v)
+	case *ast.ForOfStatement:
+		c.compileForOfStatement(v)
+	case *ast.WhileStatement:
+		c.compileWhileStatement(v)
+	case *ast.DoWhileStatement:
+		c.compileDoWhileStatement(v)
+	case *ast.LabeledStatement:
+		c.compileLabeledStatement(v)
+	case *ast.BreakStatement:
+		c.compileBreakStatement(v)
+	case *ast.ContinueStatement:
+		c.compileContinueStatement(v)
+	case *ast.ClassDeclaration:
+		c.code.Write(fmt.Sprintf("%s = ", c.refs[v.ID].goName))
+		c.compileClass(v.Class)
+	case *ast.ThrowStatement:
+		c.code.Write("Throw(")
+		c.compileExpression(v.Argument)
+		c.code.Write(")")
+	case *ast.TryStatement:
+		c.compileTryStatement(v)
+	case *ast.SwitchStatement:
+		c.compileSwitchStatement(v)
 	default:
 		panic("unknown statement type " + utils.TypeOf(v))
 	}
 }
 
 func (c *compiler) compileExpressionStatement(es *ast.ExpressionStatement) {
-	c.compileExpression(es.Expression)
+	c.compileUnusedExpression(es.Expression)
+}
+
+// compileUnusedExpression compiles an expression whose value is discarded
+// to a Go statement
+func (c *compiler) compileUnusedExpression(e ast.Expression) {
+	switch v := e.(type) {
+	case *ast.AssignmentExpression:
+		if id, ok := v.Left.(*ast.Identifier); ok && c.isPlainVar(id) {
+			c.code.Write(fmt.Sprintf("%s = ", c.refs[id].goName))
+			c.compileAssignedValue(v)
+			return
+		}
+		if _, ok := v.Left.(*ast.MemberExpression); !ok {
+			c.code.Write("_ = ")
+		}
+	case *ast.CallExpression, *ast.NewExpression:
+	case *ast.UpdateExpression:
+		// updates are calls, except for the name of a function expression in
+		// sloppy code
+		if id, ok := v.Argument.(*ast.Identifier); ok && !c.strict && c.refs[id] != nil && c.refs[id].kind == bindingFunctionName {
+			c.code.Write("_ = ")
+		}
+	default:
+		// Go doesn't allow unused expressions as statements
+		c.code.Write("_ = ")
+	}
+	c.compileExpression(e)
+}
+
+func (c *compiler) compileReturnStatement(rs *ast.ReturnStatement) {
+	if c.try != nil {
+		c.try.returns = true
+		c.code.Write("return ReturnCompletion, ")
+	} else {
+		c.code.Write("return ")
+	}
+
+	switch {
+	case c.fn != nil && c.fn.derived && !c.fn.arrow && rs.Argument == nil:
+		c.code.Write("CheckThis(this)")
+	case c.fn != nil && c.fn.derived && !c.fn.arrow:
+		c.code.Write("DerivedReturn(")
+		c.compileExpression(rs.Argument)
+		c.code.Write(", this)")
+	case rs.Argument == nil:
+		c.code.Write("Undefined")
+	default:
+		c.compileExpression(rs.Argument)
+	}
+}
+
+func (c *compiler) compileBlockStatement(bs *ast.BlockStatement) {
+	c.code.WriteLine("{")
+	c.declareScope(c.scopes[bs])
+	c.compileStatements(bs.Body)
+	c.code.Write("}")
+}
+
+// compileBody compiles the body of a control flow statement into a Go block
+func (c *compiler) compileBody(s ast.Statement) {
+	c.code.WriteLine("{")
+	if bs, ok := s.(*ast.BlockStatement); ok {
+		c.declareScope(c.scopes[bs])
+		c.compileStatements(bs.Body)
+	} else {
+		c.compileStatements([]ast.Statement{s})
+	}
+	c.code.Write("}")
+}
+
+func (c *compiler) compileIfStatement(is *ast.IfStatement) {
+	c.code.Write("if ")
+	c.compileCondition(is.Test)
+	c.code.Write(" ")
+	c.compileBody(is.Consequent)
+
+	switch v := is.Alternate.(type) {
+	case nil:
+	case *ast.IfStatement:
+		c.code.Write(" else ")
+		c.compileIfStatement(v)
+	default:
+		c.code.Write(" else ")
+		c.compileBody(v)
+	}
+}
+
+// compileForStatement compiles a for loop to a Go for loop
+// Closures capturing a let or const binding of the loop see a copy of the
+// binding per iteration, which is declared at the top of the Go loop body
+// from the binding of the previous iteration before the update runs.
+func (c *compiler) compileForStatement(fs *ast.ForStatement) {
+	goLabel := c.takeLoopLabel()
+	c.loops++
+	defer func() { c.loops-- }()
+
+	c.code.WriteLine("{")
+	s := c.scopes[fs]
+	if s != nil {
+		c.declareScope(s)
+	}
+	switch v := fs.Init.(type) {
+	case *ast.VariableDeclaration:
+		c.compileVariableDeclaration(v)
+	case ast.Expression:
+		c.compileUnusedExpression(v)
+		c.code.WriteLine("")
+	}
+
+	var captured []*binding
+	if s != nil {
+		for _, name := range s.names {
+			if b := s.bindings[name]; b.captured {
+				captured = append(captured, b)
+			}
+		}
+	}
+
+	if len(captured) == 0 {
+		c.writeLoopLabel(goLabel)
+		c.code.Write("for ; ")
+		if fs.Test != nil {
+			c.compileCondition(fs.Test)
+		}
+		c.code.Write("; ")
+		if fs.Update != nil {
+			c.compileUnusedExpression(fs.Update)
+		}
+		c.code.Write(" ")
+		c.compileBody(fs.Body)
+		c.code.Write("\n}")
+		return
+	}
+
+	prevs := make([]string, len(captured))
+	for i, b := range captured {
+		prevs[i] = c.temp(b.goName + "_prev")
+		c.code.WriteLine(fmt.Sprintf("%s := &%s", prevs[i], b.goName))
+	}
+	first := c.temp("first")
+	c.writeLoopLabel(goLabel)
+	c.code.WriteLine(fmt.Sprintf("for %s := true; ; %s = false {", first, first))
+	for i, b := range captured {
+		c.code.WriteLine(fmt.Sprintf("%s := *%s", b.goName, prevs[i]))
+		c.code.WriteLine(fmt.Sprintf("%s = &%s", prevs[i], b.goName))
+	}
+	if fs.Update != nil {
+		c.code.Write(fmt.Sprintf("if !%s {\n", first))
+		c.compileUnusedExpression(fs.Update)
+		c.code.WriteLine("\n}")
+	}
+	if fs.Test != nil {
+		c.code.Write("if !")
+		c.compileCondition(fs.Test)
+		c.code.WriteLine(" {\nbreak\n}")
+	}
+	c.compileBody(fs.Body)
+	c.code.Write("\n}\n}")
+}
+
+// compileForInStatement compiles a for-in loop to a Go for loop over the
+// keys listed by ForIn
+func (c *compiler) compileForInStatement(fs *ast.ForInStatement) {
+	goLabel := c.takeLoopLabel()
+	c.loops++
+	defer func() { c.loops-- }()
+
+	iter, key, ok := c.temp("iter"), c.temp("key"), c.temp("ok")
+	c.code.Write(fmt.Sprintf("{\n%s := ForIn(", iter))
+	c.compileExpression(fs.Right)
+	c.code.WriteLine(")")
+	c.writeLoopLabel(goLabel)
+	c.code.WriteLine(fmt.Sprintf("for %s, %s := %s.Step(); %s; %s, %s = %s.Step() {", key, ok, iter, ok, key, ok, iter))
+	c.compileForBinding(fs, fs.Left, key)
+	c.compileBody(fs.Body)
+	c.code.Write("\n}\n}")
+}
+
+// compileForOfStatement compiles the body of a for-of loop to a closure
+// called by ForOf with every value of the iterator, followed by the return
+// or the jumps out of the loop
+func (c *compiler) compileForOfStatement(fs *ast.ForOfStatement) {
+	t := &tryBlocks{labels: len(c.labels), loop: true, goLabel: c.takeLoopLabel()}
+
+	iterable := c.capture(func() { c.compileExpression(fs.Right) })
+
+	code, outer, loops, switches := c.code, c.try, c.loops, c.switches
+	c.code, c.try, c.loops, c.switches = source.NewCode(), t, 0, 0
+	value := c.temp("value")
+	c.code.WriteLine(fmt.Sprintf("func(%s Object) (Completion, Object) {", value))
+	c.compileForBinding(fs, fs.Left, value)
+	c.compileBody(fs.Body)
+	c.code.WriteLine("\nreturn NormalCompletion, Undefined")
+	c.code.Write("}")
+	body := c.code.Body()
+	c.code, c.try, c.loops, c.switches = code, outer, loops, switches
+
+	c.compileCompletion(fmt.Sprintf("ForOf(GetIterator(%s), %s)", iterable, body), t)
+}
+
+// compileForBinding binds or assigns value to the target of a for-in or
+// for-of loop at the top of the body of the loop
+// The let and const bindings of the loop are declared anew for every
+// iteration.
+func (c *compiler) compileForBinding(loop ast.Statement, left ast.Node, value string) {
+	if s := c.scopes[loop]; s != nil {
+		c.declareScope(s)
+	}
+
+	if vd, ok := left.(*ast.VariableDeclaration); ok {
+		c.compileBinding(vd.Declarations[0].ID, value)
+	} else {
+		c.code.WriteLine("{")
+		c.compilePattern(left.(ast.Pattern), value, false)
+		c.code.Write("}")
+	}
+	c.code.WriteLine("")
+}
+
+func (c *compiler) compileWhileStatement(ws *ast.WhileStatement) {
+	c.writeLoopLabel(c.takeLoopLabel())
+	c.loops++
+	defer func() { c.loops-- }()
+
+	c.code.Write("for ")
+	c.compileCondition(ws.Test)
+	c.code.Write(" ")
+	c.compileBody(ws.Body)
+}
+
+// compileDoWhileStatement compiles a do-while loop to a Go for loop that
+// skips the test on the first iteration so that continue runs the test
+func (c *compiler) compileDoWhileStatement(dws *ast.DoWhileStatement) {
+	goLabel := c.takeLoopLabel()
+	first := c.temp("first")
+	c.loops++
+	defer func() { c.loops-- }()
+
+	c.writeLoopLabel(goLabel)
+	c.code.Write(fmt.Sprintf("for %s := true; %s || ", first, first))
+	c.compileCondition(dws.Test)
+	c.code.Write(fmt.Sprintf("; %s = false ", first))
+	c.compileBody(dws.Body)
+}
+
+// compileSwitchStatement compiles a switch statement to a Go switch whose
+// clauses fall through to the next clause unless they break
+// The cases are tested with strict equality in source order, and the
+// default clause runs when none matches wherever it is, like in Go. The
+// clauses share the block of the switch, whose bindings and functions are
+// declared before the Go switch.
+func (c *compiler) compileSwitchStatement(ss *ast.SwitchStatement) {
+	discriminant := c.temp("discriminant")
+	c.code.Write(fmt.Sprintf("{\n%s := ", discriminant))
+	c.compileExpression(ss.Discriminant)
+	c.code.WriteLine(fmt.Sprintf("\n_ = %s", discriminant))
+	if s := c.scopes[ss]; s != nil {
+		c.declareScope(s)
+	}
+	for _, sc := range ss.Cases {
+		c.compileFunctionDeclarations(sc.Consequent)
+	}
+
+	c.switches++
+	defer func() { c.switches-- }()
+
+	c.code.WriteLine("switch {")
+	for i, sc := range ss.Cases {
+		if sc.Test == nil {
+			c.code.WriteLine("default:")
+		} else {
+			c.code.Write(fmt.Sprintf("case StrictEquals(%s, ", discriminant))
+			c.compileExpression(sc.Test)
+			c.code.WriteLine("):")
+		}
+		c.compileStatementList(sc.Consequent)
+		if i < len(ss.Cases)-1 {
+			c.code.WriteLine("fallthrough")
+		}
+	}
+	c.code.Write("}\n}")
+}
+
+// compileLabeledStatement compiles a labeled statement. Stacked labels of a
+// loop all share the Go label of the loop.
+func (c *compiler) compileLabeledStatement(ls *ast.LabeledStatement) {
+	var stack []*ast.LabeledStatement
+	body := ast.Statement(ls)
+	for {
+		inner, ok := body.(*ast.LabeledStatement)
+		if !ok {
+			break
+		}
+		stack = append(stack, inner)
+		body = inner.Body
+	}
+
+	switch body.(type) {
+	case *ast.ForStatement, *ast.ForInStatement, *ast.ForOfStatement, *ast.WhileStatement, *ast.DoWhileStatement:
+		n, goLabel := len(c.labels), ""
+		defer func() { c.labels = c.labels[:n] }()
+		for _, s := range stack {
+			if name, ok := c.labelNames[s]; ok {
+				if goLabel == "" {
+					goLabel = name
+				}
+				c.labels = append(c.labels, &label{name: s.Label.Name, goLabel: goLabel, loop: true})
+			}
+		}
+		c.loopLabel = goLabel
+		c.compileStatement(body)
+		return
+	}
+
+	goLabel, ok := c.labelNames[ls]
+	if !ok {
+		c.compileStatement(ls.Body)
+		return
+	}
+
+	c.labels = append(c.labels, &label{name: ls.Label.Name, goLabel: goLabel})
+	defer func() { c.labels = c.labels[:len(c.labels)-1] }()
+
+	c.compileBody(ls.Body)
+	c.code.Write(fmt.Sprintf("\n%s:", goLabel))
+}
+
+func (c *compiler) compileBreakStatement(bs *ast.BreakStatement) {
+	if c.leavesTry(bs, bs.Label) {
+		return
+	}
+
+	if bs.Label == nil {
+		c.code.Write("break")
+		return
+	}
+
+	l := c.lookupLabel(bs.Label.Name)
+	if l.loop {
+		c.code.Write("break " + l.goLabel)
+	} else {
+		c.code.Write("goto " + l.goLabel)
+	}
+}
+
+func (c *compiler) compileContinueStatement(cs *ast.ContinueStatement) {
+	if c.leavesTry(cs, cs.Label) {
+		return
+	}
+
+	if cs.Label == nil {
+		c.code.Write("continue")
+		return
+	}
+
+	c.code.Write("continue " + c.lookupLabel(cs.Label.Name).goLabel)
+}
+
+// leavesTry compiles a break or continue statement that leaves the closure
+// of a try block to a return of a Completion that identifies the statement
+func (c *compiler) leavesTry(s ast.Statement, target *ast.Identifier) bool {
+	if c.try == nil {
+		return false
+	}
+
+	if target == nil && c.loops > 0 {
+		return false
+	}
+	if _, ok := s.(*ast.BreakStatement); ok && target == nil && c.switches > 0 {
+		return false
+	}
+	if target != nil {
+		for _, l := range c.labels[c.try.labels:] {
+			if l.name == target.Name {
+				return false
+			}
+		}
+	}
+
+	if c.try.loop && (target == nil || c.lookupLabel(target.Name).goLabel == c.try.goLabel) {
+		// the body completes normally for a continue of its loop
+		if _, ok := s.(*ast.BreakStatement); ok {
+			c.code.Write("return BreakCompletion, Undefined")
+		} else {
+			c.code.Write("return NormalCompletion, Undefined")
+		}
+		return true
+	}
+
+	c.try.jumps = append(c.try.jumps, s)
+	c.code.Write(fmt.Sprintf("return %d, Undefined", int(runtime.JumpCompletion)+len(c.try.jumps)-1))
+	return true
+}
+
+func (c *compiler) lookupLabel(name string) *label {
+	for i := len(c.labels) - 1; i >= 0; i-- {
+		if c.labels[i].name == name {
+			return c.labels[i]
+		}
+	}
+
+	panic("undefined label " + name)
+}
+
+// takeLoopLabel returns the Go label for the loop being compiled
+// It must be called before compiling any nested statement
+func (c *compiler) takeLoopLabel() string {
+	goLabel := c.loopLabel
+	c.loopLabel = ""
+
+	return goLabel
+}
+
+func (c *compiler) writeLoopLabel(goLabel string) {
+	if goLabel != "" {
+		c.code.WriteLine(goLabel + ":")
+	}
+}
+
+// compileTryStatement compiles the blocks of a try statement to closures
+// called by Try, followed by the jumps out of the blocks
+func (c *compiler) compileTryStatement(ts *ast.TryStatement) {
+	code, outer, loops, switches := c.code, c.try, c.loops, c.switches
+	t := &tryBlocks{labels: len(c.labels)}
+	c.code, c.try, c.loops, c.switches = source.NewCode(), t, 0, 0
+
+	c.compileTryBlock("func() (Completion, Object) {", ts.Block)
+	c.code.Write(", ")
+	if h := ts.Handler; h == nil {
+		c.code.Write("nil")
+	} else if h.Param == nil {
+		c.compileTryBlock("func(Object) (Completion, Object) {", h.Body)
+	} else if id, ok := h.Param.(*ast.Identifier); ok {
+		param := c.refs[id].goName
+		c.compileTryBlock(fmt.Sprintf("func(%s Object) (Completion, Object) {\n_ = %s", param, param), h.Body)
+	} else {
+		param := c.temp("exception")
+		header := c.capture(func() {
+			c.code.WriteLine(fmt.Sprintf("func(%s Object) (Completion, Object) {", param))
+			c.declareBindings(ast.BoundNames(h.Param))
+			c.compileBinding(h.Param, param)
+		})
+		c.compileTryBlock(header, h.Body)
+	}
+	c.code.Write(", ")
+	if ts.Finalizer == nil {
+		c.code.Write("nil")
+	} else {
+		c.compileTryBlock("func() (Completion, Object) {", ts.Finalizer)
+	}
+
+	blocks := c.code.Body()
+	c.code, c.try, c.loops, c.switches = code, outer, loops, switches
+
+	c.compileCompletion("Try("+blocks+")", t)
+}
+
+// compileCompletion compiles a call of closures that return a Completion,
+// followed by the return or the jumps out of the closures
+func (c *compiler) compileCompletion(call string, t *tryBlocks) {
+	if !t.returns && len(t.jumps) == 0 {
+		c.code.Write(call)
+		return
+	}
+
+	completion, value := c.temp("completion"), "_"
+	if t.returns {
+		value = c.temp("value")
+	}
+	c.code.Write(fmt.Sprintf("if %s, %s := %s; ", completion, value, call))
+	if t.returns {
+		c.code.WriteLine(fmt.Sprintf("%s == ReturnCompletion {", completion))
+		if c.try != nil {
+			c.try.returns = true
+			c.code.WriteLine("return ReturnCompletion, " + value)
+		} else {
+			c.code.WriteLine("return " + value)
+		}
+		c.code.Write("}")
+		if len(t.jumps) > 0 {
+			c.code.Write(" else if ")
+		}
+	}
+	for i, s := range t.jumps {
+		if i > 0 {
+			c.code.Write(" else if ")
+		}
+		c.code.WriteLine(fmt.Sprintf("%s == %d {", completion, int(runtime.JumpCompletion)+i))
+		c.compileStatement(s)
+		c.code.Write("\n}")
+	}
+}
+
+// compileTryBlock compiles a block of a try statement to a closure that
+// completes normally at the end of the block
+func (c *compiler) compileTryBlock(header string, bs *ast.BlockStatement) {
+	c.code.WriteLine(header)
+	c.declareScope(c.scopes[bs])
+	c.compileStatements(bs.Body)
+	c.code.WriteLine("return NormalCompletion, Undefined")
+	c.code.Write("}")
+}
+
+// compileCondition compiles an expression to a Go bool
+func (c *compiler) compileCondition(e ast.Expression) {
+	// comparisons and boolean operators don't need to be converted to a
+	// JSBoolean and back
+	switch v := e.(type) {
+	case *ast.BinaryExpression:
+		if _, ok := comparisonOperators[v.Operator]; ok {
+			c.compileComparison(v)
+			return
+		}
+	case *ast.UnaryExpression:
+		if v.Operator == "!" {
+			c.code.Write("!")
+			c.compileCondition(v.Argument)
+			return
+		}
+	case *ast.BooleanLiteral:
+		c.code.Write(fmt.Sprintf("%t", v.Value))
+		return
+	case *ast.LogicalExpression:
+		if v.Operator == "&&" || v.Operator == "||" {
+			c.code.Write("(")
+			c.compileCondition(v.Left)
+			c.code.Write(fmt.Sprintf(" %s ", v.Operator))
+			c.compileCondition(v.Right)
+			c.code.Write(")")
+			return
+		}
+	}
+
+	c.code.Write("ToBoolean(")
+	c.compileExpression(e)
+	c.code.Write(")")
+}
+
+// compileFunctionDeclaration initializes the binding of a function declaration
+// The binding itself is declared with the enclosing scope
+func (c *compiler) compileFunctionDeclaration(fd *ast.FunctionDeclaration) {
+	c.code.Write(fmt.Sprintf("%s = ", c.refs[fd.ID].goName))
+	c.compileFunction(fd.Function)
 }
 
-// TODO: ignore Kind for now
 func (c *compiler) compileVariableDeclaration(vd *ast.VariableDeclaration) {
 	for _, d := range vd.Declarations {
-		c.compileVariableDeclarator(d)
+		c.compileVariableDeclarator(d, vd.Kind)
 	}
 }
 
-func (c *compiler) compileVariableDeclarator(vd *ast.VariableDeclarator) {
-	name := vd.ID.Name
+// compileVariableDeclarator initializes the binding of a declarator
+// The binding itself is declared with its scope: var bindings are hoisted
+// to the function, let and const bindings to the enclosing block.
+// A var declarator without initializer leaves the binding untouched while a
+// let declarator initializes it to undefined.
+func (c *compiler) compileVariableDeclarator(vd *ast.VariableDeclarator, kind string) {
+	id, ok := vd.ID.(*ast.Identifier)
+	if !ok {
+		c.compileBinding(vd.ID, c.capture(func() { c.compileExpression(vd.Init) }))
+		c.code.WriteLine("")
+		return
+	}
 
-	c.code.WriteLine(fmt.Sprintf("var %s Object", name))
-	c.code.WriteLine(fmt.Sprintf("_ = %s", name))
+	name := c.refs[id].goName
 	if vd.Init != nil {
 		c.code.Write(fmt.Sprintf("%s = ", name))
 		c.compileExpression(vd.Init)
 		c.code.WriteLine("")
+	} else if kind != "var" {
+		c.code.WriteLine(fmt.Sprintf("%s = Undefined", name))
 	}
-	c.code.Write(fmt.Sprintf(`global.DefineProperty("%s", %s)`, name, name))
-	c.defineVar(name)
 }
 
 // expressions
 
 func (c *compiler) compileExpression(e ast.Expression) {
 	switch v := e.(type) {
+	case *ast.FunctionExpression:
+		c.compileFunctionExpression(v)
+	case *ast.ArrowFunctionExpression:
+		c.compileArrowFunction(v)
+	case *ast.TemplateLiteral:
+		c.compileTemplateLiteral(v)
+	case *ast.TaggedTemplateExpression:
+		c.compileTaggedTemplate(v)
+	case *ast.ArrayExpression:
+		c.compileArrayExpression(v)
+	case *ast.ObjectExpression:
+		c.compileObjectExpression(v)
 	case *ast.CallExpression:
 		c.compileCallExpression(v)
 	case *ast.AssignmentExpression:
 		c.compileAssignmentExpression(v)
 	case *ast.BinaryExpression:
 		c.compileBinaryExpression(v)
+	case *ast.UnaryExpression:
+		c.compileUnaryExpression(v)
+	case *ast.UpdateExpression:
+		c.compileUpdateExpression(v)
+	case *ast.LogicalExpression:
+		c.compileLogicalExpression(v)
+	case *ast.ConditionalExpression:
+		c.compileConditionalExpression(v)
+	case *ast.SequenceExpression:
+		c.compileSequenceExpression(v)
 	case *ast.MemberExpression:
 		c.compileMemberExpression(v)
+	case *ast.NewExpression:
+		c.compileNewExpression(v)
+	case *ast.ClassExpression:
+		c.compileClass(v.Class)
+	case *ast.ThisExpression:
+		c.compileThis()
+	case *ast.MetaProperty:
+		c.compileMetaProperty(v)
 	case *ast.Identifier:
 		c.compileIdentifier(v)
 	case *ast.StringLiteral:
 		c.compileStringLiteral(v)
 	case *ast.NumericLiteral:
 		c.compileNumericLiteral(v)
+	case *ast.BooleanLiteral:
+		c.code.Write(fmt.Sprintf("JSBoolean(%t)", v.Value))
+	case *ast.NullLiteral:
+		c.code.Write("Null")
+	case *ast.YieldExpression:
+		c.compileYieldExpression(v)
+	case *ast.AwaitExpression:
+		c.code.Write("async.Await(")
+		c.compileExpression(v.Argument)
+		c.code.Write(")")
 	default:
 		panic("unknown expression type " + utils.TypeOf(v))
 	}
 }
 
+// compileFunctionExpression binds the name of a named function expression
+// in a scope that is only visible to the function itself
+func (c *compiler) compileFunctionExpression(fe *ast.FunctionExpression) {
+	if fe.ID == nil {
+		c.compileFunction(fe.Function)
+		return
+	}
+
+	name := c.refs[fe.ID].goName
+	c.code.WriteLine("func() Object {")
+	c.code.WriteLine(fmt.Sprintf("var %s Object", name))
+	c.code.Write(fmt.Sprintf("%s = ", name))
+	c.compileFunction(fe.Function)
+	c.code.WriteLine("")
+	c.code.WriteLine(fmt.Sprintf("return %s", name))
+	c.code.Write("}()")
+}
+
+// compileFunction compiles an ordinary function to a JSFunction value,
+// which is a constructor as well, or a generator or async function, which
+// isn't
+func (c *compiler) compileFunction(f *ast.Function) {
+	name := ""
+	if f.ID != nil {
+		name = f.ID.Name
+	}
+
+	if f.Generator || f.Async {
+		c.code.Write(fmt.Sprintf("%s(%q, %d, ", functionConstructor(f), name, expectedArgumentCount(f.Params)))
+		c.compileFunctionBody(f, &function{})
+		c.code.Write(")")
+		return
+	}
+
+	c.code.Write(fmt.Sprintf("NewConstructor(%q, %d, ", name, expectedArgumentCount(f.Params)))
+	c.compileFunctionBody(f, &function{newTarget: true})
+	c.code.Write(")")
+}
+
+// compileMethod compiles a method, getter or setter to a JSFunction value,
+// which isn't a constructor
+// The method is named after its property when it's defined.
+func (c *compiler) compileMethod(f *ast.Function, fn *function) {
+	c.code.Write(fmt.Sprintf("%s(\"\", %d, ", functionConstructor(f), expectedArgumentCount(f.Params)))
+	c.compileFunctionBody(f, fn)
+	c.code.Write(")")
+}
+
+// functionConstructor returns the runtime function that creates a function
+// that isn't a constructor
+func functionConstructor(f *ast.Function) string {
+	switch {
+	case f.Generator:
+		return "NewGeneratorFunction"
+	case f.Async:
+		return "NewAsyncFunction"
+	default:
+		return "NewFunction"
+	}
+}
+
+// compileArrowFunction compiles an arrow function to a JSFunction value,
+// which isn't a constructor
+// The Go function literal doesn't declare this and newTarget, so that the
+// arrow function refers to those of the enclosing Go function.
+func (c *compiler) compileArrowFunction(af *ast.ArrowFunctionExpression) {
+	fn := &function{global: true}
+	if c.fn != nil {
+		*fn = *c.fn
+	}
+	fn.arrow = true
+
+	c.code.Write(fmt.Sprintf("%s(\"\", %d, ", functionConstructor(af.Function), expectedArgumentCount(af.Params)))
+	c.compileFunctionBody(af.Function, fn)
+	c.code.Write(")")
+}
+
+// compileFunctionBody compiles a function to a Go function literal
+// Parameters are bound from the argument list, missing arguments are nil.
+// Destructured parameters are bound after the plain ones are declared, and
+// parameters with expressions are all bound in order. A sloppy mode function
+// that uses this replaces undefined and null with the global object. The
+// arguments object is only created by a function that references it. The
+// body of a generator function is a closure that is run by the generator
+// the function returns, with the parameters already bound. The parameters
+// and body of an async function are a closure run by RunAsync, so that an
+// exception thrown by either rejects the promise of the call.
+func (c *compiler) compileFunctionBody(f *ast.Function, fn *function) {
+	// labels, loops, switches and try blocks don't extend to nested
+	// functions
+	labels, loops, switches, try, outer, strict := c.labels, c.loops, c.switches, c.try, c.fn, c.strict
+	s := c.scopes[f]
+	c.labels, c.loops, c.switches, c.try, c.fn, c.strict = nil, 0, 0, nil, fn, s.strict
+	defer func() {
+		c.labels, c.loops, c.switches, c.try, c.fn, c.strict = labels, loops, switches, try, outer, strict
+	}()
+
+	switch {
+	case fn.arrow:
+		c.code.WriteLine("func(_ Object, args []Object) Object {")
+	case fn.newTarget:
+		c.code.WriteLine("func(this Object, args []Object, newTarget Object) Object {")
+	default:
+		c.code.WriteLine("func(this Object, args []Object) Object {")
+	}
+	if !fn.arrow && s.usesThis && !s.strict {
+		c.code.WriteLine("this = BindThis(this, global)")
+	}
+	if f.Async {
+		if f.Generator {
+			panic("async generator functions are not supported")
+		}
+		c.code.WriteLine("return RunAsync(func(async *Async) Object {")
+	}
+	// a rest parameter gets the remaining arguments in an array
+	params := make([]ast.Pattern, len(f.Params))
+	values := make([]string, len(f.Params))
+	for i, p := range f.Params {
+		if r, ok := p.(*ast.RestElement); ok {
+			params[i] = r.Argument
+			values[i] = fmt.Sprintf("RestArgs(args, %d)", i)
+		} else {
+			params[i] = p
+			values[i] = fmt.Sprintf("Arg(args, %d)", i)
+		}
+	}
+	expressions := hasParameterExpressions(f.Params)
+	for i, p := range params {
+		if id, ok := p.(*ast.Identifier); ok && !expressions {
+			goName := c.refs[id].goName
+			c.code.WriteLine(fmt.Sprintf("var %s Object = %s", goName, values[i]))
+			c.code.WriteLine(fmt.Sprintf("_ = %s", goName))
+		} else {
+			c.declareBindings(ast.BoundNames(p))
+		}
+	}
+	if s.arguments != nil {
+		c.declareArguments(f, s)
+	}
+	for i, p := range params {
+		if _, ok := p.(*ast.Identifier); !ok || expressions {
+			c.compileBinding(p, values[i])
+			c.code.WriteLine("")
+		}
+	}
+	if f.Generator {
+		c.code.WriteLine("return NewGenerator(func(generator *GeneratorBody) Object {")
+	}
+	c.declareScope(s)
+	if bs, ok := c.scopes[f.Body]; ok {
+		c.declareScope(bs)
+	}
+	c.compileStatements(f.Body.Body)
+	if fn.derived && !fn.arrow {
+		c.code.WriteLine("return CheckThis(this)")
+	} else {
+		c.code.WriteLine("return Undefined")
+	}
+	if f.Generator || f.Async {
+		c.code.WriteLine("})")
+	}
+	c.code.Write("}")
+}
+
+// declareArguments creates the arguments object of a function, which is
+// mapped to the parameters in sloppy mode if they are simple
+func (c *compiler) declareArguments(f *ast.Function, s *scope) {
+	goName := s.arguments.goName
+	if s.strict || !isSimpleParameterList(f.Params) {
+		c.code.WriteLine(fmt.Sprintf("var %s Object = NewArguments(args)", goName))
+	} else {
+		var params []string
+		for _, p := range f.Params {
+			params = append(params, ", &"+c.refs[p.(*ast.Identifier)].goName)
+		}
+		c.code.WriteLine(fmt.Sprintf("var %s Object = NewMappedArguments(args%s)", goName, strings.Join(params, "")))
+	}
+	c.code.WriteLine(fmt.Sprintf("_ = %s", goName))
+}
+
+// compileClass creates the constructor of a class and defines the methods
+// in source order
+// The class is compiled to a Go function literal that is called right away,
+// which declares the binding of the class name visible to the class body.
+func (c *compiler) compileClass(cl *ast.Class) {
+	name := ""
+	if cl.ID != nil {
+		name = cl.ID.Name
+	}
+
+	var constructor *ast.ClassMethod
+	for _, m := range cl.Body.Body {
+		if m.Kind == "constructor" {
+			constructor = m
+		}
+	}
+
+	class, proto := c.temp("class"), c.temp("proto")
+	derived := cl.SuperClass != nil
+
+	// class code is always strict
+	strict := c.strict
+	c.strict = true
+	defer func() { c.strict = strict }()
+
+	c.code.WriteLine("func() Object {")
+	c.declareScope(c.scopes[cl])
+	c.code.WriteLine(fmt.Sprintf("var %s *JSFunction", class))
+	c.code.WriteLine(fmt.Sprintf("var %s *JSObject", proto))
+
+	length := 0
+	if constructor != nil {
+		length = expectedArgumentCount(constructor.Params)
+	}
+	c.code.Write(fmt.Sprintf("%s, %s = NewClass(%q, %d, %t, ", class, proto, name, length, derived))
+	if derived {
+		c.compileExpression(cl.SuperClass)
+	} else {
+		c.code.Write("nil")
+	}
+	c.code.Write(", ")
+	if constructor == nil {
+		c.code.Write("nil")
+	} else {
+		c.compileFunctionBody(constructor.Function, &function{newTarget: true, class: class, home: proto, derived: derived})
+	}
+	c.code.WriteLine(")")
+	c.code.WriteLine(fmt.Sprintf("_ = %s", proto))
+	if cl.ID != nil {
+		c.code.WriteLine(fmt.Sprintf("%s = %s", c.scopes[cl].bindings[name].goName, class))
+	}
+
+	for _, m := range cl.Body.Body {
+		if m.Kind == "constructor" {
+			continue
+		}
+
+		home := proto
+		if m.Static {
+			home = class
+		}
+
+		switch m.Kind {
+		case "get":
+			c.code.Write("DefineGetter(")
+		case "set":
+			c.code.Write("DefineSetter(")
+		default:
+			c.code.Write("DefineMethod(")
+		}
+		c.code.Write(home + ", ")
+		c.compilePropertyKey(m.Key, m.Computed)
+		c.code.Write(", ")
+		c.compileMethod(m.Function, &function{class: class, home: home})
+		c.code.WriteLine(")")
+	}
+
+	c.code.WriteLine(fmt.Sprintf("return %s", class))
+	c.code.Write("}()")
+}
+
+// compileArrayExpression creates an array from its elements, with Hole for
+// the elements that are left out
+func (c *compiler) compileArrayExpression(ae *ast.ArrayExpression) {
+	c.code.Write("NewArrayLiteral(")
+	c.compileArguments(ae.Elements)
+	c.code.Write(")")
+}
+
+// compileObjectExpression creates an object and adds the properties in
+// source order, so that keys and values are evaluated left to right
+// An object with methods is kept in a Go variable, which is the home object
+// where the methods look up super properties.
+func (c *compiler) compileObjectExpression(oe *ast.ObjectExpression) {
+	home := ""
+	for _, p := range oe.Properties {
+		if _, ok := p.(*ast.ObjectMethod); ok {
+			home = c.temp("home")
+			break
+		}
+	}
+
+	if home != "" {
+		c.code.WriteLine("func() *JSObject {")
+		c.code.WriteLine(fmt.Sprintf("%s := NewObjectLiteral()", home))
+		c.code.Write("return " + home)
+	} else {
+		c.code.Write("NewObjectLiteral()")
+	}
+	for _, p := range oe.Properties {
+		c.code.WriteLine(".")
+		switch v := p.(type) {
+		case *ast.ObjectProperty:
+			if isProtoProperty(v) {
+				c.code.Write("InitProto(")
+				c.compileExpression(v.Value)
+				c.code.Write(")")
+				continue
+			}
+
+			// anonymous functions are named after the property
+			if fe, ok := v.Value.(*ast.FunctionExpression); ok && fe.ID == nil {
+				c.code.Write("InitMethod(")
+			} else {
+				c.code.Write("Init(")
+			}
+			c.compilePropertyKey(v.Key, v.Computed)
+			c.code.Write(", ")
+			c.compileExpression(v.Value)
+			c.code.Write(")")
+		case *ast.ObjectMethod:
+			switch v.Kind {
+			case "get":
+				c.code.Write("InitGetter(")
+			case "set":
+				c.code.Write("InitSetter(")
+			default:
+				c.code.Write("InitMethod(")
+			}
+			c.compilePropertyKey(v.Key, v.Computed)
+			c.code.Write(", ")
+			c.compileMethod(v.Function, &function{home: home})
+			c.code.Write(")")
+		case *ast.SpreadElement:
+			c.code.Write("InitSpread(")
+			c.compileExpression(v.Argument)
+			c.code.Write(")")
+		default:
+			panic("unknown property type " + utils.TypeOf(v))
+		}
+	}
+	if home != "" {
+		c.code.Write("\n}()")
+	}
+}
+
+// isProtoProperty returns true for a __proto__: value property, which sets
+// the prototype of an object literal instead of creating a property
+func isProtoProperty(p *ast.ObjectProperty) bool {
+	if p.Computed || p.Shorthand {
+		return false
+	}
+
+	switch k := p.Key.(type) {
+	case *ast.Identifier:
+		return k.Name == "__proto__"
+	case *ast.StringLiteral:
+		return k.Value == "__proto__"
+	}
+
+	return false
+}
+
+// compilePropertyKey compiles the key of a property, converting literal keys
+// at compile time
+func (c *compiler) compilePropertyKey(key ast.Expression, computed bool) {
+	if !computed {
+		switch k := key.(type) {
+		case *ast.Identifier:
+			c.code.Write(fmt.Sprintf("JSString(%q)", k.Name))
+			return
+		case *ast.StringLiteral:
+			c.code.Write(fmt.Sprintf("JSString(%q)", k.Value))
+			return
+		case *ast.NumericLiteral:
+			c.code.Write(fmt.Sprintf("JSString(%q)", runtime.ToString(runtime.JSNumber(k.Value))))
+			return
+		}
+	}
+
+	c.code.Write("ToPropertyKey(")
+	c.compileExpression(key)
+	c.code.Write(")")
+}
+
+// compileCallExpression compiles a call, passing the object of a method
+// call as this
+// Builtin functions of the global object are called directly.
 func (c *compiler) compileCallExpression(ce *ast.CallExpression) {
-	c.compileExpression(ce.Callee)
-	c.code.Write("([]Object{")
-	for i, arg := range ce.Arguments {
-		c.compileExpression(arg)
-		if i != len(ce.Arguments)-1 {
+	c.compileCallee(ce.Callee, ce.Arguments)
+	c.compileArguments(ce.Arguments)
+	c.code.Write(")")
+}
+
+// compileArguments creates the Go slice of the arguments of a call or of the
+// elements of an array literal
+// The values of spread elements are expanded in place.
+func (c *compiler) compileArguments(es []ast.Expression) {
+	spread := false
+	for _, e := range es {
+		if _, ok := e.(*ast.SpreadElement); ok {
+			spread = true
+		}
+	}
+
+	if spread {
+		c.code.Write("ExpandSpread([]Object{")
+		c.compileOperands(es...)
+		c.code.Write("})")
+	} else {
+		c.code.Write("[]Object{")
+		c.compileOperands(es...)
+		c.code.Write("}")
+	}
+}
+
+// compileCallee starts the call of a function up to its arguments, which
+// receives the object of a member expression as this
+func (c *compiler) compileCallee(callee ast.Expression, args []ast.Expression) {
+	switch callee := callee.(type) {
+	case *ast.Super:
+		c.code.Write(fmt.Sprintf("SuperCall(%s, &this, newTarget, ", c.fn.class))
+	case *ast.MemberExpression:
+		if _, ok := callee.Object.(*ast.Super); ok {
+			c.code.Write("Call(")
+			c.compileMemberExpression(callee)
+			c.code.Write(", ")
+			c.compileThis()
+			c.code.Write(", ")
+		} else if callee.Computed {
+			c.code.Write("Invoke(")
+			c.compileOperand(callee.Object, append([]ast.Expression{callee.Property}, args...))
+			c.code.Write(", ToPropertyKey(")
+			c.compileOperand(callee.Property, args)
+			c.code.Write("), ")
+		} else {
+			c.code.Write("Invoke(")
+			c.compileOperand(callee.Object, args)
+			c.code.Write(", ")
+			c.compilePropertyKey(callee.Property, false)
 			c.code.Write(", ")
 		}
+	default:
+		c.code.Write("Call(")
+		c.compileExpression(callee)
+		c.code.Write(", Undefined, ")
 	}
-	c.code.Write("})\n")
 }
 
-// TODO: ignoring computed value for now
+// compileTemplateLiteral concatenates the strings of a template literal with
+// its values converted to strings
+func (c *compiler) compileTemplateLiteral(tl *ast.TemplateLiteral) {
+	if len(tl.Expressions) == 0 {
+		c.code.Write(fmt.Sprintf("JSString(%q)", tl.Quasis[0].Cooked))
+		return
+	}
+
+	for i, e := range tl.Expressions {
+		if i > 0 {
+			c.code.Write(" + ")
+		}
+		if q := tl.Quasis[i].Cooked; q != "" {
+			c.code.Write(fmt.Sprintf("%q + ", q))
+		}
+		c.code.Write("ToString(")
+		c.compileOperand(e, tl.Expressions[i+1:])
+		c.code.Write(")")
+	}
+	if q := tl.Quasis[len(tl.Quasis)-1].Cooked; q != "" {
+		c.code.Write(fmt.Sprintf(" + %q", q))
+	}
+}
+
+// compileTaggedTemplate calls the tag function with the template object of
+// the call site, followed by the values of the template literal
+// The template object is created once for every call site, so the site is
+// identified by a number that is unique in the compiled file.
+func (c *compiler) compileTaggedTemplate(tt *ast.TaggedTemplateExpression) {
+	var cooked, raw []string
+	for _, q := range tt.Quasi.Quasis {
+		cooked = append(cooked, fmt.Sprintf("%q", q.Cooked))
+		raw = append(raw, fmt.Sprintf("%q", q.Raw))
+	}
+
+	c.compileCallee(tt.Tag, tt.Quasi.Expressions)
+	c.code.Write(fmt.Sprintf("[]Object{TemplateObject(%d, []string{%s}, []string{%s})", c.templates, strings.Join(cooked, ", "), strings.Join(raw, ", ")))
+	c.templates++
+	if len(tt.Quasi.Expressions) > 0 {
+		c.code.Write(", ")
+		c.compileOperands(tt.Quasi.Expressions...)
+	}
+	c.code.Write("})")
+}
+
+func (c *compiler) compileNewExpression(ne *ast.NewExpression) {
+	c.code.Write("New(")
+	c.compileOperand(ne.Callee, ne.Arguments)
+	c.code.Write(", ")
+	c.compileArguments(ne.Arguments)
+	c.code.Write(")")
+}
+
+// compileMemberExpression gets a property of an object, or of the prototype
+// of the home object of a method for a super property
+// A computed key is converted to a property key by the runtime, which
+// indexes arrays and strings directly.
 func (c *compiler) compileMemberExpression(me *ast.MemberExpression) {
+	if _, ok := me.Object.(*ast.Super); ok {
+		c.code.Write(fmt.Sprintf("SuperGet(%s, ", c.fn.home))
+		c.compilePropertyKey(me.Property, me.Computed)
+		c.code.Write(", ")
+		c.compileThis()
+		c.code.Write(")")
+		return
+	}
+
 	if me.Computed {
-		panic("computed MemberExpression is not supported")
+		c.code.Write("GetElement(")
+		c.compileOperands(me.Object, me.Property)
+		c.code.Write(")")
+		return
 	}
 
-	if builtInFunc := c.getBuiltinFunc(me.Object, me.Property); builtInFunc == "" {
+	c.code.Write("Get(")
+	c.compileExpression(me.Object)
+	c.code.Write(", ")
+	c.compilePropertyKey(me.Property, false)
+	c.code.Write(")")
+}
+
+// setter returns the runtime function that assigns a reference, or its
+// version that throws if the assignment fails in strict mode code
+func (c *compiler) setter(name string) string {
+	if c.strict {
+		return name + "Strict"
+	}
+
+	return name
+}
+
+// compileMemberAssignment assigns to a property of an object
+func (c *compiler) compileMemberAssignment(me *ast.MemberExpression, ae *ast.AssignmentExpression) {
+	if ae.Operator != "=" {
+		c.compileMemberUpdate(me, ae, func(old string) {
+			c.code.Write(c.assignmentOperator(ae) + "(" + old + ", ")
+			c.compileExpression(ae.Right)
+			c.code.Write(")")
+		})
+		return
+	}
+
+	switch {
+	case isSuper(me.Object):
+		c.code.Write(fmt.Sprintf("SuperSet(%s, ", c.fn.home))
+		c.compilePropertyKey(me.Property, me.Computed)
+		c.code.Write(", ")
+		c.compileExpression(ae.Right)
+		c.code.Write(", ")
+		c.compileThis()
+	case me.Computed:
+		c.code.Write(c.setter("SetElement") + "(")
+		c.compileOperands(me.Object, me.Property, ae.Right)
+	default:
+		c.code.Write(c.setter("Set") + "(")
+		c.compileOperand(me.Object, []ast.Expression{ae.Right})
+		c.code.Write(", ")
+		c.compilePropertyKey(me.Property, false)
+		c.code.Write(", ")
+		c.compileExpression(ae.Right)
+	}
+	c.code.Write(")")
+}
+
+// compileMemberUpdate reads and then assigns a property, like obj[key] += v
+// The update is compiled to a Go function literal that is called right
+// away, which evaluates the object and the key once, while a computed key is
+// converted to a property key for both reading and assigning. The value is
+// written by update, given the Go expression of the current value.
+func (c *compiler) compileMemberUpdate(me *ast.MemberExpression, node ast.Node, update func(old string)) {
+	obj, key := c.temp("obj"), c.temp("key")
+
+	c.code.WriteLine("func() Object {")
+	c.writeLineDirective(node)
+	get, set, end := fmt.Sprintf("GetElement(%s, %s)", obj, key), fmt.Sprintf("%s(%s, %s, ", c.setter("SetElement"), obj, key), ")"
+	if isSuper(me.Object) {
+		this := c.capture(c.compileThis)
+		get = fmt.Sprintf("SuperGet(%s, %s, %s)", c.fn.home, key, this)
+		set = fmt.Sprintf("SuperSet(%s, %s, ", c.fn.home, key)
+		end = fmt.Sprintf(", %s)", this)
+	} else {
+		c.code.Write(obj + " := ")
 		c.compileExpression(me.Object)
-		c.code.Write(".")
+		c.code.WriteLine("")
+	}
+	c.code.Write(key + " := ")
+	if me.Computed && !isSuper(me.Object) {
 		c.compileExpression(me.Property)
 	} else {
-		c.code.Write(builtInFunc)
+		c.compilePropertyKey(me.Property, me.Computed)
+	}
+	c.code.WriteLine("")
+	c.code.Write("return " + set)
+	update(get)
+	c.code.Write(end + "\n}()")
+}
+
+// isSuper tells whether the object of a member expression is super
+func isSuper(e ast.Expression) bool {
+	_, ok := e.(*ast.Super)
+	return ok
+}
+
+// compileThis compiles this, which is the global object in the program
+// In the constructor of a derived class, this is initialized by super.
+func (c *compiler) compileThis() {
+	switch {
+	case c.fn == nil || c.fn.global:
+		c.code.Write("global")
+	case c.fn.derived:
+		c.code.Write("CheckThis(this)")
+	default:
+		c.code.Write("this")
+	}
+}
+
+// compileMetaProperty compiles new.target, which is undefined in methods
+func (c *compiler) compileMetaProperty(mp *ast.MetaProperty) {
+	if mp.Meta.Name != "new" || mp.Property.Name != "target" {
+		panic("unknown meta property " + mp.String())
+	}
+
+	if c.fn != nil && c.fn.newTarget {
+		c.code.Write("newTarget")
+	} else {
+		c.code.Write("Undefined")
 	}
 }
 
+// compileYieldExpression suspends the generator being run
+func (c *compiler) compileYieldExpression(ye *ast.YieldExpression) {
+	if ye.Delegate {
+		c.code.Write("generator.Delegate(")
+	} else {
+		c.code.Write("generator.Yield(")
+	}
+
+	if ye.Argument == nil {
+		c.code.Write("Undefined")
+	} else {
+		c.compileExpression(ye.Argument)
+	}
+	c.code.Write(")")
+}
+
 func (c *compiler) compileAssignmentExpression(ae *ast.AssignmentExpression) {
-	c.compileExpression(ae.Left)
-	c.code.Write(fmt.Sprintf(" %s ", ae.Operator))
-	c.compileExpression(ae.Right)
+	if me, ok := ae.Left.(*ast.MemberExpression); ok {
+		c.compileMemberAssignment(me, ae)
+		return
+	}
+
+	id, ok := ae.Left.(*ast.Identifier)
+	if !ok {
+		c.compileDestructuringAssignment(ae)
+		return
+	}
+
+	b := c.refs[id]
+	switch {
+	case b == nil:
+		c.code.Write(fmt.Sprintf("%s(global, %q, ", c.setter("SetGlobal"), id.Name))
+	case b.kind == bindingConst:
+		c.code.Write(fmt.Sprintf("AssignConst(%q, ", id.Name))
+	case b.kind == bindingFunctionName && c.strict:
+		c.code.Write(fmt.Sprintf("AssignConst(%q, ", id.Name))
+	case b.kind == bindingFunctionName:
+		// assigning to the name of a function expression has no effect
+		c.compileAssignedValue(ae)
+		return
+	case c.checks[id]:
+		c.code.Write(fmt.Sprintf("AssignChecked(&%s, %q, ", b.goName, id.Name))
+	default:
+		c.code.Write(fmt.Sprintf("Assign(&%s, ", b.goName))
+	}
+	c.compileAssignedValue(ae)
+	c.code.Write(")")
+}
+
+// compileAssignedValue compiles the value that an assignment stores, which
+// for a compound assignment like x += y combines the current value of the
+// target with the right-hand side
+func (c *compiler) compileAssignedValue(ae *ast.AssignmentExpression) {
+	if ae.Operator == "=" {
+		c.compileExpression(ae.Right)
+		return
+	}
+
+	c.compileBinaryOperation(c.assignmentOperator(ae), ae.Left.(ast.Expression), ae.Right)
+}
+
+// assignmentOperator returns the runtime function of the binary operator of
+// a compound assignment
+func (c *compiler) assignmentOperator(ae *ast.AssignmentExpression) string {
+	op := ast.BinaryOperator(strings.TrimSuffix(string(ae.Operator), "="))
+	fn, ok := binaryOperators[op]
+	if !ok {
+		panic("unknown assignment operator " + string(ae.Operator))
+	}
+
+	return fn
+}
+
+// isPlainVar returns true if id refers to a mutable binding that can be
+// assigned without runtime checks
+func (c *compiler) isPlainVar(id *ast.Identifier) bool {
+	b := c.refs[id]
+	return b != nil && !c.checks[id] && b.kind != bindingConst && b.kind != bindingFunctionName
+}
+
+// binaryOperators maps binary operators to the runtime functions that
+// implement them
+var binaryOperators = map[ast.BinaryOperator]string{
+	"+":   "Add",
+	"-":   "Sub",
+	"*":   "Mul",
+	"/":   "Div",
+	"%":   "Mod",
+	"**":  "Exp",
+	"<<":  "LeftShift",
+	">>":  "SignedRightShift",
+	">>>": "UnsignedRightShift",
+	"&":   "BitwiseAnd",
+	"|":   "BitwiseOr",
+	"^":   "BitwiseXor",
+}
+
+// comparisonOperators maps comparison operators to the runtime functions that
+// implement them, which return a Go bool
+// The result of != and !== is negated.
+var comparisonOperators = map[ast.BinaryOperator]string{
+	"==":         "LooseEquals",
+	"!=":         "LooseEquals",
+	"===":        "StrictEquals",
+	"!==":        "StrictEquals",
+	"<":          "Less",
+	">":          "Greater",
+	"<=":         "LessOrEqual",
+	">=":         "GreaterOrEqual",
+	"in":         "In",
+	"instanceof": "InstanceOf",
 }
 
 func (c *compiler) compileBinaryExpression(be *ast.BinaryExpression) {
-	c.compileExpression(be.Left)
-	c.code.Write(fmt.Sprintf(" %s ", be.Operator))
-	c.compileExpression(be.Right)
+	if _, ok := comparisonOperators[be.Operator]; ok {
+		c.code.Write("JSBoolean(")
+		c.compileComparison(be)
+		c.code.Write(")")
+		return
+	}
+
+	fn, ok := binaryOperators[be.Operator]
+	if !ok {
+		panic("unknown binary operator " + string(be.Operator))
+	}
+
+	c.compileBinaryOperation(fn, be.Left, be.Right)
 }
 
-func (c *compiler) compileIdentifier(i *ast.Identifier) {
-	if c.isVarDefined(i.Name) {
-		c.code.Write(i.Name)
-	} else {
-		c.code.Write(fmt.Sprintf(`global.GetProperty("%s")`, i.Name))
+// compileComparison compiles a comparison to a Go bool expression
+func (c *compiler) compileComparison(be *ast.BinaryExpression) {
+	if be.Operator == "!=" || be.Operator == "!==" {
+		c.code.Write("!")
 	}
+
+	c.compileBinaryOperation(comparisonOperators[be.Operator], be.Left, be.Right)
 }
 
-func (c *compiler) compileStringLiteral(s *ast.StringLiteral) {
-	c.code.Write(fmt.Sprintf(`JSString("%s")`, s.Value))
+func (c *compiler) compileBinaryOperation(fn string, left, right ast.Expression) {
+	c.code.Write(fn + "(")
+	c.compileOperands(left, right)
+	c.code.Write(")")
 }
 
-func (c *compiler) compileNumericLiteral(n *ast.NumericLiteral) {
-	c.code.Write(fmt.Sprintf(`JSNumber(%f)`, n.Value))
+// unaryOperators maps unary operators to the runtime functions that
+// implement them
+var unaryOperators = map[ast.UnaryOperator]string{
+	"-":      "Negate",
+	"+":      "ToNumber",
+	"~":      "BitwiseNot",
+	"typeof": "TypeOf",
+	"void":   "Void",
 }
 
-func (c *compiler) writeLineNo(node ast.Node) {
-	c.code.WriteLine(fmt.Sprintf(`// line %d: %s`, node.GetAttr().Loc.Start.Line, node))
+func (c *compiler) compileUnaryExpression(ue *ast.UnaryExpression) {
+	switch ue.Operator {
+	case "!":
+		c.code.Write("JSBoolean(!")
+		c.compileCondition(ue.Argument)
+		c.code.Write(")")
+		return
+	case "delete":
+		c.compileDelete(ue.Argument)
+		return
+	}
+
+	fn, ok := unaryOperators[ue.Operator]
+	if !ok {
+		panic("unknown unary operator " + string(ue.Operator))
+	}
+
+	c.code.Write(fn + "(")
+	// typeof doesn't throw for an identifier that isn't declared
+	if id, ok := ue.Argument.(*ast.Identifier); ok && ue.Operator == "typeof" && c.refs[id] == nil {
+		c.code.Write(fmt.Sprintf("LookupGlobal(global, %q)", id.Name))
+	} else {
+		c.compileExpression(ue.Argument)
+	}
+	c.code.Write(")")
 }
 
-// defineVar defines the var when the compiler sees it
-// This is used for optimizing compiled code for direct reference of var
-// FIXME: defined var is cached in global context for now
-func (c *compiler) defineVar(name string) {
-	// FIXME: Prop value is a dummpy obj for now
-	c.ctx.Global.DefineProperty(name, &runtime.JSObject{})
+// compileMemberUpdateExpression compiles ++ and -- on a property, which is
+// read and assigned by the runtime unless it's a super property
+func (c *compiler) compileMemberUpdateExpression(me *ast.MemberExpression, ue *ast.UpdateExpression, delta int) {
+	if isSuper(me.Object) {
+		key, old := c.temp("key"), c.temp("old")
+		this := c.capture(c.compileThis)
+		c.code.WriteLine("func() Object {")
+		c.writeLineDirective(ue)
+		c.code.Write(key + " := ")
+		c.compilePropertyKey(me.Property, me.Computed)
+		c.code.WriteLine("")
+		c.code.WriteLine(fmt.Sprintf("%s := ToNumber(SuperGet(%s, %s, %s))", old, c.fn.home, key, this))
+		c.code.WriteLine(fmt.Sprintf("SuperSet(%s, %s, %s+%d, %s)", c.fn.home, key, old, delta, this))
+		if ue.Prefix {
+			c.code.Write(fmt.Sprintf("return %s + %d\n}()", old, delta))
+		} else {
+			c.code.Write(fmt.Sprintf("return %s\n}()", old))
+		}
+		return
+	}
+
+	c.code.Write(c.setter("UpdateProperty") + "(")
+	c.compileOperand(me.Object, []ast.Expression{me.Property})
+	c.code.Write(", ")
+	if me.Computed {
+		c.compileExpression(me.Property)
+	} else {
+		c.compilePropertyKey(me.Property, false)
+	}
+	c.code.Write(fmt.Sprintf(", %d, %t)", delta, ue.Prefix))
 }
 
-// FIXME: Using global context for now
-func (c *compiler) isVarDefined(name string) bool {
-	_, err := c.ctx.Global.GetProperty(name)
-	return err == nil
+// compileDelete deletes a property reference
+// Declared bindings can't be deleted, while undeclared identifiers refer to
+// properties of the global object. Deleting any other value has no effect.
+func (c *compiler) compileDelete(e ast.Expression) {
+	switch v := e.(type) {
+	case *ast.Identifier:
+		if c.refs[v] == nil {
+			c.code.Write(fmt.Sprintf("DeleteGlobal(global, %q)", v.Name))
+		} else {
+			c.code.Write("JSBoolean(false)")
+		}
+	case *ast.MemberExpression:
+		c.code.Write(c.setter("Delete") + "(")
+		c.compileOperand(v.Object, []ast.Expression{v.Property})
+		c.code.Write(", ")
+		c.compilePropertyKey(v.Property, v.Computed)
+		c.code.Write(")")
+	default:
+		c.code.Write("Sequence(")
+		c.compileExpression(e)
+		c.code.Write(", JSBoolean(true))")
+	}
 }
 
-func (c *compiler) getBuiltinFunc(objExp, propExp ast.Expression) string {
-	oID, ok := objExp.(*ast.Identifier)
+// compileUpdateExpression compiles ++ and -- on a binding or a property
+func (c *compiler) compileUpdateExpression(ue *ast.UpdateExpression) {
+	delta := 1
+	if ue.Operator == "--" {
+		delta = -1
+	}
+
+	if me, ok := ue.Argument.(*ast.MemberExpression); ok {
+		c.compileMemberUpdateExpression(me, ue, delta)
+		return
+	}
+
+	id, ok := ue.Argument.(*ast.Identifier)
 	if !ok {
-		return ""
+		panic("update of " + utils.TypeOf(ue.Argument) + " is not supported")
 	}
 
-	pID := propExp.(*ast.Identifier)
+	b := c.refs[id]
+	switch {
+	case b == nil:
+		c.code.Write(fmt.Sprintf("%s(global, %q, %d, %t)", c.setter("UpdateGlobal"), id.Name, delta, ue.Prefix))
+	case b.kind == bindingConst, b.kind == bindingFunctionName && c.strict:
+		c.code.Write(fmt.Sprintf("AssignConst(%q, ToNumber(", id.Name))
+		c.compileIdentifier(id)
+		c.code.Write("))")
+	case b.kind == bindingFunctionName:
+		// updating the name of a function expression has no effect
+		c.code.Write("ToNumber(")
+		c.compileIdentifier(id)
+		c.code.Write(")")
+		if ue.Prefix {
+			c.code.Write(fmt.Sprintf(" + %d", delta))
+		}
+	case c.checks[id]:
+		c.code.Write(fmt.Sprintf("Update(CheckRef(&%s, %q), %d, %t)", b.goName, id.Name, delta, ue.Prefix))
+	default:
+		c.code.Write(fmt.Sprintf("Update(&%s, %d, %t)", b.goName, delta, ue.Prefix))
+	}
+}
+
+// logicalOperators maps logical operators to the runtime functions that
+// implement them, which take the right-hand side as a function to evaluate
+// it only when needed
+var logicalOperators = map[ast.LogicalOperator]string{
+	"&&": "LogicalAnd",
+	"||": "LogicalOr",
+}
+
+func (c *compiler) compileLogicalExpression(le *ast.LogicalExpression) {
+	fn, ok := logicalOperators[le.Operator]
 	if !ok {
-		return ""
+		panic("unknown logical operator " + string(le.Operator))
 	}
 
-	obj, err := c.ctx.Global.GetProperty(oID.Name)
-	if err != nil || obj.Type() != runtime.JS_OBJECT_TYPE_OBJECT {
-		return ""
+	c.code.Write(fn + "(")
+	c.compileExpression(le.Left)
+	c.code.Write(", ")
+	c.compileThunk(le.Right)
+	c.code.Write(")")
+}
+
+func (c *compiler) compileConditionalExpression(ce *ast.ConditionalExpression) {
+	c.code.Write("Conditional(")
+	c.compileCondition(ce.Test)
+	c.code.Write(", ")
+	c.compileThunk(ce.Consequent)
+	c.code.Write(", ")
+	c.compileThunk(ce.Alternate)
+	c.code.Write(")")
+}
+
+// compileThunk wraps an expression that may not be evaluated in a function
+func (c *compiler) compileThunk(e ast.Expression) {
+	c.code.Write("func() Object { return ")
+	c.compileExpression(e)
+	c.code.Write(" }")
+}
+
+func (c *compiler) compileSequenceExpression(se *ast.SequenceExpression) {
+	c.code.Write("Sequence(")
+	c.compileOperands(se.Expressions...)
+	c.code.Write(")")
+}
+
+// compileOperands writes expressions separated by commas, to be evaluated
+// left to right
+// A nil expression is an array hole, and a spread element is collected by
+// Spread. Go reads plain variables after the
+// function calls of an expression, so a variable followed by an operand
+// with side effects is read through Load.
+func (c *compiler) compileOperands(es ...ast.Expression) {
+	for i, e := range es {
+		if i > 0 {
+			c.code.Write(", ")
+		}
+
+		if e == nil {
+			c.code.Write("Hole")
+		} else {
+			c.compileOperand(e, es[i+1:])
+		}
 	}
+}
 
-	prop, err := (obj.(*runtime.JSObject)).GetProperty(pID.Name)
-	if err != nil || prop.Type() != runtime.JS_OBJECT_TYPE_FUNCTION {
-		return ""
+// compileOperand compiles an expression that is evaluated before the
+// expressions that follow it
+func (c *compiler) compileOperand(e ast.Expression, following []ast.Expression) {
+	if s, ok := e.(*ast.SpreadElement); ok {
+		c.code.Write("Spread(")
+		c.compileOperand(s.Argument, following)
+		c.code.Write(")")
+	} else if id, ok := e.(*ast.Identifier); ok && c.isPlainVar(id) && hasSideEffects(following) {
+		c.code.Write("Load(")
+		c.compileExpression(e)
+		c.code.Write(")")
+	} else {
+		c.compileExpression(e)
 	}
+}
+
+// hasSideEffects tells whether evaluating any of the expressions may assign
+// a variable
+func hasSideEffects(es []ast.Expression) bool {
+	for _, e := range es {
+		switch e.(type) {
+		case nil, *ast.Identifier, *ast.StringLiteral, *ast.NumericLiteral, *ast.BooleanLiteral, *ast.NullLiteral, *ast.FunctionExpression, *ast.ArrowFunctionExpression, *ast.ThisExpression, *ast.MetaProperty:
+		default:
+			return true
+		}
+	}
+
+	return false
+}
+
+// compileIdentifier references the Go variable of a resolved binding
+// Unresolved identifiers are looked up on the global object
+func (c *compiler) compileIdentifier(i *ast.Identifier) {
+	if b := c.refs[i]; b == nil && i.Name == "undefined" {
+		// the undefined property of the global object is read-only
+		c.code.Write("Undefined")
+	} else if b == nil {
+		c.code.Write(fmt.Sprintf("GetGlobal(global, %q)", i.Name))
+	} else if c.checks[i] {
+		c.code.Write(fmt.Sprintf("CheckInit(%s, %q)", b.goName, i.Name))
+	} else {
+		c.code.Write(b.goName)
+	}
+}
+
+func (c *compiler) compileStringLiteral(s *ast.StringLiteral) {
+	c.code.Write(fmt.Sprintf(`JSString(%q)`, s.Value))
+}
+
+// compileNumericLiteral writes a number as a Go constant with the shortest
+// digits that convert back to the same float64
+func (c *compiler) compileNumericLiteral(n *ast.NumericLiteral) {
+	if math.IsInf(n.Value, 1) {
+		c.code.Write("Infinity")
+		return
+	}
+
+	c.code.Write("JSNumber(" + strconv.FormatFloat(n.Value, 'g', -1, 64) + ")")
+}
+
+// temp allocates a unique Go identifier for a compiler generated variable
+func (c *compiler) temp(name string) string {
+	return uniqueName(c.goNames, name)
+}
+
+// writeLineNo comments the source of a statement and maps the Go lines that
+// follow to the line of the statement, which Go reports in stack traces
+func (c *compiler) writeLineNo(node ast.Node) {
+	line := node.GetAttr().Loc.Start.Line
+	c.code.WriteLine(fmt.Sprintf(`// line %d: %s`, line, commentText(node.String())))
+	c.writeLineDirective(node)
+}
+
+// commentText escapes the characters of source code that can't be in a Go
+// comment, like the line breaks of template literals and the control and
+// format characters of string literals
+func commentText(s string) string {
+	var b bytes.Buffer
+	for i, w := 0, 0; i < len(s); i += w {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		w = size
+		switch {
+		case r == utf8.RuneError && size == 1:
+			b.WriteString(fmt.Sprintf(`\x%02x`, s[i]))
+		case r == '\t':
+			b.WriteRune(r)
+		case unicode.IsControl(r), unicode.Is(unicode.Cf, r):
+			q := strconv.QuoteRune(r)
+			b.WriteString(q[1 : len(q)-1])
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
 
-	return (prop.(*runtime.JSFunction)).FuncName()
+// writeLineDirective maps the Go lines that follow to the line of a node
+func (c *compiler) writeLineDirective(node ast.Node) {
+	c.code.WriteLine(fmt.Sprintf(`//line %s:%d`, c.filename, node.GetAttr().Loc.Start.Line))
 }
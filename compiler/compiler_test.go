@@ -17,8 +17,8 @@ func TestCompile(t *testing.T) {
 		t.Fatalf("error decoding AST JSON: %s", err)
 	}
 
-	code := Compile(f)
-	if !strings.Contains(code.String(), `Console_Log([]Object{JSString("Hello, Godzilla")}`) {
+	code := Compile(f, "hello.js")
+	if !strings.Contains(code.String(), `Invoke(GetGlobal(global, "console"), JSString("log"), []Object{JSString("Hello, Godzilla")})`) {
 		t.Fatalf("compiler has error:\n%s", code)
 	}
 }
@@ -0,0 +1,216 @@
+package compiler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jingweno/godzilla/ast"
+	"github.com/jingweno/godzilla/runtime"
+	"github.com/jingweno/godzilla/source"
+	"github.com/jingweno/godzilla/utils"
+)
+
+// compileBinding initializes the bindings of a declaration, a parameter or
+// a catch clause with value, which is a Go expression
+// The Go statements are wrapped in a block, so that a goto can jump over
+// their temporary variables.
+func (c *compiler) compileBinding(p ast.Pattern, value string) {
+	c.code.WriteLine("{")
+	c.compilePattern(p, value, true)
+	c.code.Write("}")
+}
+
+// declareBindings declares Go variables for the names bound by patterns
+// that aren't declared with their scope, like destructured parameters
+// Parameters that may be referenced early start out uninitialized.
+func (c *compiler) declareBindings(ids []*ast.Identifier) {
+	for _, id := range ids {
+		b := c.refs[id]
+		if b.tdz {
+			c.code.WriteLine(fmt.Sprintf("var %s Object = Uninitialized", b.goName))
+		} else {
+			c.code.WriteLine(fmt.Sprintf("var %s Object", b.goName))
+		}
+		c.code.WriteLine(fmt.Sprintf("_ = %s", b.goName))
+	}
+}
+
+// compileDestructuringAssignment assigns the parts of a value to the targets
+// of a pattern, which is compiled to a Go function literal that is called
+// right away and returns the value
+func (c *compiler) compileDestructuringAssignment(ae *ast.AssignmentExpression) {
+	value := c.temp("value")
+
+	c.code.WriteLine("func() Object {")
+	c.writeLineDirective(ae)
+	c.code.Write(fmt.Sprintf("%s := ", value))
+	c.compileExpression(ae.Right)
+	c.code.WriteLine("")
+	c.compilePattern(ae.Left, value, false)
+	c.code.Write(fmt.Sprintf("return %s\n}()", value))
+}
+
+// compilePattern stores value, a Go expression that is evaluated exactly
+// once, in the target of a binding or of an assignment
+// Destructuring patterns are compiled to Go statements that take the value
+// apart, with a default value evaluated only if its value is undefined.
+// Every statement is mapped to the line of its pattern, which stack traces
+// report.
+func (c *compiler) compilePattern(p ast.Pattern, value string, declaration bool) {
+	c.writeLineDirective(p)
+	switch v := p.(type) {
+	case *ast.Identifier:
+		c.compileIdentifierTarget(v, value, declaration)
+	case *ast.MemberExpression:
+		if isSuper(v.Object) {
+			c.code.Write(fmt.Sprintf("SuperSet(%s, ", c.fn.home))
+			c.compilePropertyKey(v.Property, v.Computed)
+			c.code.Write(fmt.Sprintf(", %s, ", value))
+			c.compileThis()
+			c.code.WriteLine(")")
+			return
+		}
+
+		c.code.Write(c.setter("Set") + "(")
+		c.compileOperand(v.Object, []ast.Expression{v.Property})
+		c.code.Write(", ")
+		c.compilePropertyKey(v.Property, v.Computed)
+		c.code.WriteLine(fmt.Sprintf(", %s)", value))
+	case *ast.ObjectPattern:
+		c.compileObjectPattern(v, value, declaration)
+	case *ast.ArrayPattern:
+		c.compileArrayPattern(v, value, declaration)
+	case *ast.AssignmentPattern:
+		tmp := c.temp("value")
+		c.code.WriteLine(fmt.Sprintf("%s := %s", tmp, value))
+		c.code.WriteLine(fmt.Sprintf("if %s == Undefined {", tmp))
+		c.writeLineDirective(v.Right)
+		c.code.Write(fmt.Sprintf("%s = ", tmp))
+		c.compileExpression(v.Right)
+		c.code.WriteLine("\n}")
+		c.compilePattern(v.Left, tmp, declaration)
+	default:
+		panic("unknown pattern type " + utils.TypeOf(v))
+	}
+}
+
+// compileIdentifierTarget stores a value in the binding of an identifier
+// A declaration initializes its binding, while an assignment checks that
+// the binding can be assigned.
+func (c *compiler) compileIdentifierTarget(id *ast.Identifier, value string, declaration bool) {
+	b := c.refs[id]
+	switch {
+	case declaration:
+		c.code.WriteLine(fmt.Sprintf("%s = %s", b.goName, value))
+	case b == nil:
+		c.code.WriteLine(fmt.Sprintf("%s(global, %q, %s)", c.setter("SetGlobal"), id.Name, value))
+	case b.kind == bindingConst, b.kind == bindingFunctionName && c.strict:
+		c.code.WriteLine(fmt.Sprintf("AssignConst(%q, %s)", id.Name, value))
+	case b.kind == bindingFunctionName:
+		// assigning to the name of a function expression has no effect
+		c.code.WriteLine(fmt.Sprintf("_ = %s", value))
+	case c.checks[id]:
+		c.code.WriteLine(fmt.Sprintf("AssignChecked(&%s, %q, %s)", b.goName, id.Name, value))
+	default:
+		c.code.WriteLine(fmt.Sprintf("%s = %s", b.goName, value))
+	}
+}
+
+// compileObjectPattern gets the properties of an object pattern in order
+// The keys are kept for the rest element, which excludes them.
+func (c *compiler) compileObjectPattern(op *ast.ObjectPattern, value string, declaration bool) {
+	if len(op.Properties) == 0 && op.Rest == nil {
+		c.code.WriteLine(fmt.Sprintf("_ = CheckDestructurable(%s, \"\")", value))
+		return
+	}
+
+	property := ""
+	if len(op.Properties) > 0 && !op.Properties[0].Computed {
+		property = propertyName(op.Properties[0].Key)
+	}
+	obj := c.temp("obj")
+	c.code.WriteLine(fmt.Sprintf("%s := CheckDestructurable(%s, %q)", obj, value, property))
+
+	var keys []string
+	for _, p := range op.Properties {
+		key := c.capture(func() { c.compilePropertyKey(p.Key, p.Computed) })
+		if p.Computed {
+			tmp := c.temp("key")
+			c.writeLineDirective(p.Key)
+			c.code.WriteLine(fmt.Sprintf("%s := %s", tmp, key))
+			key = tmp
+		}
+		keys = append(keys, key)
+
+		c.compilePattern(p.Value, fmt.Sprintf("Get(%s, %s)", obj, key), declaration)
+	}
+
+	if op.Rest != nil {
+		c.compilePattern(op.Rest.Argument, fmt.Sprintf("ObjectRest(%s, []PropertyKey{%s})", obj, strings.Join(keys, ", ")), declaration)
+	}
+}
+
+// propertyName returns the name of a property key that isn't computed
+func propertyName(key ast.Expression) string {
+	switch k := key.(type) {
+	case *ast.Identifier:
+		return k.Name
+	case *ast.StringLiteral:
+		return k.Value
+	case *ast.NumericLiteral:
+		return string(runtime.ToString(runtime.JSNumber(k.Value)))
+	default:
+		return ""
+	}
+}
+
+// compileArrayPattern steps through the iterator of a value for the elements
+// of an array pattern
+// The pattern is compiled to a Go function literal that is called right
+// away, which closes the iterator if an exception is thrown.
+func (c *compiler) compileArrayPattern(ap *ast.ArrayPattern, value string, declaration bool) {
+	iter := c.temp("iter")
+
+	c.code.WriteLine("func() {")
+	c.writeLineDirective(ap)
+	c.code.WriteLine(fmt.Sprintf("%s := GetIterator(%s)", iter, value))
+	c.code.WriteLine(fmt.Sprintf("defer %s.CloseOnThrow()", iter))
+	for _, e := range ap.Elements {
+		switch e := e.(type) {
+		case nil:
+			c.writeLineDirective(ap)
+			c.code.WriteLine(fmt.Sprintf("%s.Step()", iter))
+		case *ast.RestElement:
+			c.compilePattern(e.Argument, fmt.Sprintf("%s.Rest()", iter), declaration)
+		default:
+			c.compilePattern(e, fmt.Sprintf("%s.Next()", iter), declaration)
+		}
+	}
+	c.writeLineDirective(ap)
+	c.code.WriteLine(fmt.Sprintf("%s.Close()", iter))
+	c.code.WriteLine("}()")
+}
+
+// capture compiles to a separate buffer and returns the Go code
+func (c *compiler) capture(compile func()) string {
+	code := c.code
+	c.code = source.NewCode()
+	defer func() { c.code = code }()
+
+	compile()
+
+	return c.code.Body()
+}
+
+// expectedArgumentCount returns the length of a function, which counts the
+// parameters before the first one with a default value or a rest parameter
+func expectedArgumentCount(params []ast.Pattern) int {
+	for i, p := range params {
+		switch p.(type) {
+		case *ast.AssignmentPattern, *ast.RestElement:
+			return i
+		}
+	}
+
+	return len(params)
+}
@@ -0,0 +1,894 @@
+package compiler
+
+import (
+	"fmt"
+	"unicode"
+
+	"github.com/jingweno/godzilla/ast"
+	"github.com/jingweno/godzilla/utils"
+)
+
+type scopeKind int
+
+const (
+	moduleScope scopeKind = iota
+	functionScope
+	// arrowScope is the scope of an arrow function, which shares this with
+	// the enclosing function
+	arrowScope
+	// bodyScope is the scope of the var declarations of a function whose
+	// parameters have expressions, which can't see the var declarations
+	bodyScope
+	blockScope
+)
+
+type bindingKind string
+
+const (
+	bindingVar      bindingKind = "var"
+	bindingLet      bindingKind = "let"
+	bindingConst    bindingKind = "const"
+	bindingParam    bindingKind = "param"
+	bindingFunction bindingKind = "function"
+	// bindingArguments is the arguments object of a function
+	bindingArguments bindingKind = "arguments"
+	// bindingFunctionName is the immutable name of a function expression
+	bindingFunctionName bindingKind = "function name"
+)
+
+// binding is a declared name in a scope
+// Every binding is compiled to a Go variable with a unique name, which Go
+// heap-allocates when a closure captures it
+type binding struct {
+	name   string
+	goName string
+	kind   bindingKind
+	scope  *scope
+	// captured is true if the binding is referenced by a nested function
+	captured bool
+	// declEnd is the end offset of a let or const declaration, and
+	// clauseEnd is the end offset of the switch clause of the declaration,
+	// which later clauses can be jumped to without running
+	declEnd   int
+	clauseEnd int
+	// tdz is true if the binding may be referenced in its temporal dead zone
+	tdz bool
+}
+
+// scope is a lexical scope of a module, a function or a block
+type scope struct {
+	kind     scopeKind
+	parent   *scope
+	names    []string
+	bindings map[string]*binding
+	// strict is true for strict mode code
+	strict bool
+	// usesThis is true if a function references this, including from arrow
+	// functions
+	usesThis bool
+	// arguments is the binding of the arguments object of a function that
+	// references it, including from arrow functions
+	arguments *binding
+}
+
+func newScope(kind scopeKind, parent *scope) *scope {
+	return &scope{
+		kind:     kind,
+		parent:   parent,
+		bindings: make(map[string]*binding),
+		strict:   parent != nil && parent.strict,
+	}
+}
+
+// function returns the nearest function or module scope
+func (s *scope) function() *scope {
+	for ; s.kind == blockScope || s.kind == bodyScope; s = s.parent {
+	}
+
+	return s
+}
+
+// varScope returns the scope of the var declarations of the nearest function
+// or module
+func (s *scope) varScope() *scope {
+	for ; s.kind == blockScope; s = s.parent {
+	}
+
+	return s
+}
+
+// thisScope returns the nearest function or module scope that binds this
+// and arguments
+func (s *scope) thisScope() *scope {
+	for ; s.kind == blockScope || s.kind == bodyScope || s.kind == arrowScope; s = s.parent {
+	}
+
+	return s
+}
+
+func (s *scope) lookup(name string) *binding {
+	for ; s != nil; s = s.parent {
+		if b, ok := s.bindings[name]; ok {
+			return b
+		}
+	}
+
+	return nil
+}
+
+// resolver walks the AST ahead of code generation to build the scope tree
+// and to resolve every identifier to a binding. Identifiers that aren't
+// resolved refer to properties of the global object.
+//
+// A reference to a let or const binding needs a temporal dead zone check
+// when it may run before the declaration: it either precedes the end of the
+// declaration in the source or it is made from a nested function.
+//
+// Labels that are targeted by a break or continue statement get a Go label.
+type resolver struct {
+	scopes     map[interface{}]*scope
+	refs       map[*ast.Identifier]*binding
+	checks     map[*ast.Identifier]bool
+	labelNames map[*ast.LabeledStatement]string
+	goNames    map[string]bool
+	// blockFunctions are the var bindings of the functions declared in
+	// blocks of sloppy mode code
+	blockFunctions map[*ast.FunctionDeclaration]*binding
+	current        *scope
+	labels         []*ast.LabeledStatement
+	// hoisting are the names declared by let, const and class declarations
+	// in the function body and the blocks around the statement being hoisted
+	hoisting []map[string]bool
+}
+
+func newResolver() *resolver {
+	r := &resolver{
+		scopes:         make(map[interface{}]*scope),
+		refs:           make(map[*ast.Identifier]*binding),
+		checks:         make(map[*ast.Identifier]bool),
+		labelNames:     make(map[*ast.LabeledStatement]string),
+		goNames:        make(map[string]bool),
+		blockFunctions: make(map[*ast.FunctionDeclaration]*binding),
+	}
+	for _, name := range reservedGoNames {
+		r.goNames[name] = true
+	}
+
+	return r
+}
+
+// reservedGoNames can't be used for bindings because they are Go keywords,
+// predeclared identifiers or names used by the compiled code
+var reservedGoNames = []string{
+	"break", "case", "chan", "const", "continue", "default", "defer", "else",
+	"fallthrough", "for", "func", "go", "goto", "if", "import", "interface",
+	"map", "package", "range", "return", "select", "struct", "switch", "type",
+	"var", "_",
+	"bool", "byte", "complex64", "complex128", "error", "float32", "float64",
+	"int", "int8", "int16", "int32", "int64", "rune", "string", "uint",
+	"uint8", "uint16", "uint32", "uint64", "uintptr", "true", "false", "iota",
+	"nil", "append", "cap", "close", "complex", "copy", "delete", "imag",
+	"len", "make", "new", "panic", "print", "println", "real", "recover",
+	"main", "global", "this", "args", "newTarget", "generator", "async",
+}
+
+// goName allocates a Go identifier for name that is unique in the compiled file
+// Names that could clash with exported identifiers of the runtime are prefixed
+func (r *resolver) goName(name string) string {
+	var runes []rune
+	for i, ch := range name {
+		if ch == '_' || unicode.IsLetter(ch) || (i > 0 && unicode.IsDigit(ch)) {
+			runes = append(runes, ch)
+		} else {
+			runes = append(runes, '_')
+		}
+	}
+
+	base := string(runes)
+	if !unicode.IsLower(runes[0]) && runes[0] != '_' {
+		base = "js_" + base
+	}
+
+	return uniqueName(r.goNames, base)
+}
+
+// uniqueName returns base, or base with a numeric suffix if it's taken
+func uniqueName(names map[string]bool, base string) string {
+	name := base
+	for i := 1; names[name]; i++ {
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+	names[name] = true
+
+	return name
+}
+
+func (r *resolver) declare(name string, kind bindingKind) *binding {
+	s := r.current
+	if kind == bindingVar {
+		s = s.varScope()
+	}
+
+	if b, ok := s.bindings[name]; ok {
+		return b
+	}
+
+	b := &binding{
+		name:   name,
+		goName: r.goName(name),
+		kind:   kind,
+		scope:  s,
+	}
+	s.names = append(s.names, name)
+	s.bindings[name] = b
+
+	return b
+}
+
+func (r *resolver) enterScope(node interface{}, kind scopeKind) *scope {
+	s := newScope(kind, r.current)
+	r.scopes[node] = s
+	r.current = s
+
+	return s
+}
+
+func (r *resolver) exitScope() {
+	r.current = r.current.parent
+}
+
+func (r *resolver) resolveProgram(p *ast.Program) {
+	s := r.enterScope(p, moduleScope)
+	s.strict = ast.HasUseStrict(p.Directives)
+	r.hoistVarDeclarations(p.Body)
+	r.resolveStatements(p.Body)
+	r.exitScope()
+}
+
+// hoistVarDeclarations declares var bindings found anywhere in a function
+// body, excluding nested functions
+func (r *resolver) hoistVarDeclarations(stmts []ast.Statement) {
+	inBlock := len(r.hoisting) > 0
+	r.hoisting = append(r.hoisting, lexicallyDeclaredNames(stmts))
+	defer func() { r.hoisting = r.hoisting[:len(r.hoisting)-1] }()
+
+	for _, s := range stmts {
+		if fd, ok := s.(*ast.FunctionDeclaration); ok && inBlock {
+			r.hoistBlockFunction(fd)
+		}
+		r.hoistVarDeclaration(s)
+	}
+}
+
+// hoistLoop hoists the var declarations of the head and the body of a for
+// loop, whose let and const declarations are around the body
+func (r *resolver) hoistLoop(head ast.Node, body ast.Statement) {
+	vd, ok := head.(*ast.VariableDeclaration)
+	if !ok {
+		r.hoistVarDeclaration(body)
+		return
+	}
+
+	r.hoistVarDeclaration(vd)
+	r.hoisting = append(r.hoisting, lexicallyDeclaredNames([]ast.Statement{vd}))
+	r.hoistVarDeclaration(body)
+	r.hoisting = r.hoisting[:len(r.hoisting)-1]
+}
+
+// hoistBlockFunction declares a var binding for a function declared in a
+// block of sloppy mode code, which is assigned the function when the
+// declaration is evaluated (Annex B.3.3)
+// There's no var binding if it would conflict with a let, const or class
+// declaration or with a parameter. Like in V8, functions declared in the
+// enclosing blocks don't conflict.
+func (r *resolver) hoistBlockFunction(fd *ast.FunctionDeclaration) {
+	name := fd.ID.Name
+	if r.current.strict || fd.Generator || fd.Async {
+		return
+	}
+	for _, names := range r.hoisting[:len(r.hoisting)-1] {
+		if names[name] {
+			return
+		}
+	}
+	params := r.current
+	if params.kind == bodyScope {
+		params = params.parent
+	}
+	if b := params.bindings[name]; b != nil && b.kind == bindingParam {
+		return
+	}
+
+	r.blockFunctions[fd] = r.declare(name, bindingVar)
+}
+
+// lexicallyDeclaredNames returns the names declared by the let, const and
+// class declarations of a statement list
+func lexicallyDeclaredNames(stmts []ast.Statement) map[string]bool {
+	names := make(map[string]bool)
+	for _, s := range stmts {
+		switch v := s.(type) {
+		case *ast.VariableDeclaration:
+			if v.Kind != "var" {
+				for _, d := range v.Declarations {
+					for _, id := range ast.BoundNames(d.ID) {
+						names[id.Name] = true
+					}
+				}
+			}
+		case *ast.ClassDeclaration:
+			names[v.ID.Name] = true
+		}
+	}
+
+	return names
+}
+
+func (r *resolver) hoistVarDeclaration(s ast.Statement) {
+	switch v := s.(type) {
+	case *ast.VariableDeclaration:
+		if v.Kind == "var" {
+			for _, d := range v.Declarations {
+				for _, id := range ast.BoundNames(d.ID) {
+					r.declare(id.Name, bindingVar)
+				}
+			}
+		}
+	case *ast.BlockStatement:
+		r.hoistVarDeclarations(v.Body)
+	case *ast.IfStatement:
+		r.hoistVarDeclaration(v.Consequent)
+		if v.Alternate != nil {
+			r.hoistVarDeclaration(v.Alternate)
+		}
+	case *ast.ForStatement:
+		r.hoistLoop(v.Init, v.Body)
+	case *ast.ForInStatement:
+		r.hoistLoop(v.Left, v.Body)
+	case *ast.ForOfStatement:
+		r.hoistLoop(v.Left, v.Body)
+	case *ast.WhileStatement:
+		r.hoistVarDeclaration(v.Body)
+	case *ast.DoWhileStatement:
+		r.hoistVarDeclaration(v.Body)
+	case *ast.LabeledStatement:
+		r.hoistVarDeclaration(v.Body)
+	case *ast.SwitchStatement:
+		for _, sc := range v.Cases {
+			r.hoistVarDeclarations(sc.Consequent)
+		}
+	case *ast.TryStatement:
+		r.hoistVarDeclaration(v.Block)
+		if v.Handler != nil {
+			r.hoistVarDeclaration(v.Handler.Body)
+		}
+		if v.Finalizer != nil {
+			r.hoistVarDeclaration(v.Finalizer)
+		}
+	}
+}
+
+// declareLexicalDeclarations declares let, const and function bindings of
+// a statement list in the current scope
+func (r *resolver) declareLexicalDeclarations(stmts []ast.Statement) {
+	for _, s := range stmts {
+		switch v := s.(type) {
+		case *ast.VariableDeclaration:
+			if v.Kind != "var" {
+				for _, d := range v.Declarations {
+					for _, id := range ast.BoundNames(d.ID) {
+						b := r.declare(id.Name, bindingKind(v.Kind))
+						b.declEnd = d.End
+					}
+				}
+			}
+		case *ast.FunctionDeclaration:
+			r.declare(v.ID.Name, bindingFunction)
+		case *ast.ClassDeclaration:
+			// a class declaration binds its name like let
+			b := r.declare(v.ID.Name, bindingLet)
+			b.declEnd = v.End
+		}
+	}
+}
+
+func (r *resolver) resolveStatements(stmts []ast.Statement) {
+	r.declareLexicalDeclarations(stmts)
+	for _, s := range stmts {
+		r.resolveStatement(s)
+	}
+}
+
+func (r *resolver) resolveStatement(s ast.Statement) {
+	switch v := s.(type) {
+	case *ast.ExpressionStatement:
+		r.resolveExpression(v.Expression)
+	case *ast.VariableDeclaration:
+		for _, d := range v.Declarations {
+			r.resolvePattern(d.ID, true)
+			if d.Init != nil {
+				r.resolveExpression(d.Init)
+			}
+		}
+	case *ast.FunctionDeclaration:
+		r.resolveDeclaration(v.ID)
+		r.resolveFunction(v.Function, functionScope)
+	case *ast.ClassDeclaration:
+		r.resolveDeclaration(v.ID)
+		r.resolveClass(v.Class)
+	case *ast.ReturnStatement:
+		if v.Argument != nil {
+			r.resolveExpression(v.Argument)
+		}
+	case *ast.BlockStatement:
+		r.enterScope(v, blockScope)
+		r.resolveStatements(v.Body)
+		r.exitScope()
+	case *ast.EmptyStatement:
+	case *ast.IfStatement:
+		r.resolveExpression(v.Test)
+		r.resolveBody(v.Consequent)
+		if v.Alternate != nil {
+			r.resolveBody(v.Alternate)
+		}
+	case *ast.ForStatement:
+		r.resolveForStatement(v)
+	case *ast.ForInStatement:
+		r.resolveForInOfStatement(v, v.Left, v.Right, v.Body)
+	case *ast.ForOfStatement:
+		r.resolveForInOfStatement(v, v.Left, v.Right, v.Body)
+	case *ast.WhileStatement:
+		r.resolveExpression(v.Test)
+		r.resolveBody(v.Body)
+	case *ast.DoWhileStatement:
+		r.resolveBody(v.Body)
+		r.resolveExpression(v.Test)
+	case *ast.LabeledStatement:
+		r.labels = append(r.labels, v)
+		r.resolveBody(v.Body)
+		r.labels = r.labels[:len(r.labels)-1]
+	case *ast.BreakStatement:
+		r.resolveLabel(v.Label)
+	case *ast.ContinueStatement:
+		r.resolveLabel(v.Label)
+	case *ast.ThrowStatement:
+		r.resolveExpression(v.Argument)
+	case *ast.SwitchStatement:
+		r.resolveSwitchStatement(v)
+	case *ast.TryStatement:
+		r.resolveStatement(v.Block)
+		if v.Handler != nil {
+			r.resolveCatchClause(v.Handler)
+		}
+		if v.Finalizer != nil {
+			r.resolveStatement(v.Finalizer)
+		}
+	default:
+		panic("unknown statement type " + utils.TypeOf(v))
+	}
+}
+
+// resolveBody resolves the body of a control flow statement
+func (r *resolver) resolveBody(s ast.Statement) {
+	r.resolveStatements([]ast.Statement{s})
+}
+
+// resolveCatchClause resolves a catch clause, whose parameter is scoped to
+// the clause
+func (r *resolver) resolveCatchClause(c *ast.CatchClause) {
+	r.enterScope(c, blockScope)
+	defer r.exitScope()
+
+	if c.Param != nil {
+		for _, id := range ast.BoundNames(c.Param) {
+			r.declare(id.Name, bindingParam)
+		}
+		r.resolvePattern(c.Param, true)
+	}
+	r.resolveStatement(c.Body)
+}
+
+// resolveSwitchStatement resolves a switch statement, whose clauses share a
+// block scope
+func (r *resolver) resolveSwitchStatement(s *ast.SwitchStatement) {
+	r.resolveExpression(s.Discriminant)
+	r.enterScope(s, blockScope)
+	defer r.exitScope()
+
+	for _, sc := range s.Cases {
+		r.declareLexicalDeclarations(sc.Consequent)
+		for _, name := range r.current.names {
+			if b := r.current.bindings[name]; b.clauseEnd == 0 {
+				b.clauseEnd = sc.End
+			}
+		}
+	}
+	for _, sc := range s.Cases {
+		if sc.Test != nil {
+			r.resolveExpression(sc.Test)
+		}
+		for _, st := range sc.Consequent {
+			r.resolveStatement(st)
+		}
+	}
+}
+
+// resolveForStatement resolves a for statement whose let or const
+// declarations are scoped to the loop
+func (r *resolver) resolveForStatement(f *ast.ForStatement) {
+	if vd, ok := f.Init.(*ast.VariableDeclaration); ok && vd.Kind != "var" {
+		r.enterScope(f, blockScope)
+		defer r.exitScope()
+		r.declareLexicalDeclarations([]ast.Statement{vd})
+	}
+
+	switch v := f.Init.(type) {
+	case *ast.VariableDeclaration:
+		r.resolveStatement(v)
+	case ast.Expression:
+		r.resolveExpression(v)
+	}
+	if f.Test != nil {
+		r.resolveExpression(f.Test)
+	}
+	if f.Update != nil {
+		r.resolveExpression(f.Update)
+	}
+	r.resolveBody(f.Body)
+}
+
+// resolveForInOfStatement resolves a for-in or for-of statement, whose let
+// or const declaration is scoped to the loop and is bound anew on every
+// iteration
+// The expression is resolved outside of the scope of the loop.
+func (r *resolver) resolveForInOfStatement(s ast.Statement, left ast.Node, right ast.Expression, body ast.Statement) {
+	r.resolveExpression(right)
+
+	vd, ok := left.(*ast.VariableDeclaration)
+	if !ok {
+		r.resolvePattern(left.(ast.Pattern), false)
+		r.resolveBody(body)
+		return
+	}
+
+	if vd.Kind != "var" {
+		r.enterScope(s, blockScope)
+		defer r.exitScope()
+		r.declareLexicalDeclarations([]ast.Statement{vd})
+	}
+	r.resolveStatement(vd)
+	r.resolveBody(body)
+}
+
+// resolveLabel allocates a Go label for the statement a break or continue targets
+func (r *resolver) resolveLabel(label *ast.Identifier) {
+	if label == nil {
+		return
+	}
+
+	for i := len(r.labels) - 1; i >= 0; i-- {
+		ls := r.labels[i]
+		if ls.Label.Name != label.Name {
+			continue
+		}
+
+		if _, ok := r.labelNames[ls]; !ok {
+			r.labelNames[ls] = r.goName(label.Name)
+		}
+		return
+	}
+}
+
+func (r *resolver) resolveFunction(f *ast.Function, kind scopeKind) {
+	// labels aren't visible to nested functions
+	labels := r.labels
+	r.labels = nil
+	defer func() { r.labels = labels }()
+
+	s := r.enterScope(f, kind)
+	defer r.exitScope()
+	if ast.HasUseStrict(f.Body.Directives) {
+		s.strict = true
+	}
+	for _, p := range f.Params {
+		for _, id := range ast.BoundNames(p) {
+			b := r.declare(id.Name, bindingParam)
+			b.declEnd = p.GetAttr().End
+		}
+	}
+	for _, p := range f.Params {
+		r.resolvePattern(p, true)
+	}
+
+	// the var declarations of a function whose parameters have expressions
+	// are in a separate scope, so that closures in the parameters can't see
+	// them
+	if hasParameterExpressions(f.Params) {
+		r.enterScope(f.Body, bodyScope)
+		defer r.exitScope()
+	}
+	r.hoistVarDeclarations(f.Body.Body)
+	r.resolveStatements(f.Body.Body)
+}
+
+// hasParameterExpressions tells whether parameters have default values or
+// computed keys, which are evaluated when the function is called
+func hasParameterExpressions(params []ast.Pattern) bool {
+	for _, p := range params {
+		if hasPatternExpressions(p) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasPatternExpressions(p ast.Pattern) bool {
+	switch v := p.(type) {
+	case *ast.AssignmentPattern:
+		return true
+	case *ast.ObjectPattern:
+		for _, prop := range v.Properties {
+			if prop.Computed || hasPatternExpressions(prop.Value) {
+				return true
+			}
+		}
+		return v.Rest != nil && hasPatternExpressions(v.Rest)
+	case *ast.ArrayPattern:
+		for _, e := range v.Elements {
+			if e != nil && hasPatternExpressions(e) {
+				return true
+			}
+		}
+	case *ast.RestElement:
+		return hasPatternExpressions(v.Argument)
+	}
+
+	return false
+}
+
+// isSimpleParameterList tells whether all parameters are plain identifiers
+func isSimpleParameterList(params []ast.Pattern) bool {
+	for _, p := range params {
+		if _, ok := p.(*ast.Identifier); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// resolveClass resolves a class, whose name is bound in a scope around the
+// class body like a const
+func (r *resolver) resolveClass(c *ast.Class) {
+	s := r.enterScope(c, blockScope)
+	defer r.exitScope()
+
+	// class code is always strict
+	s.strict = true
+	if c.ID != nil {
+		b := r.declare(c.ID.Name, bindingConst)
+		b.declEnd = c.Body.End
+	}
+	if c.SuperClass != nil {
+		r.resolveExpression(c.SuperClass)
+	}
+	for _, m := range c.Body.Body {
+		if m.Computed {
+			r.resolveExpression(m.Key)
+		}
+		r.resolveFunction(m.Function, functionScope)
+	}
+}
+
+func (r *resolver) resolveExpression(e ast.Expression) {
+	switch v := e.(type) {
+	case *ast.FunctionExpression:
+		if v.ID == nil {
+			r.resolveFunction(v.Function, functionScope)
+			return
+		}
+
+		// the name of a function expression is only visible to the function itself
+		r.enterScope(v, blockScope)
+		r.declare(v.ID.Name, bindingFunctionName)
+		r.resolveDeclaration(v.ID)
+		r.resolveFunction(v.Function, functionScope)
+		r.exitScope()
+	case *ast.ArrayExpression:
+		for _, e := range v.Elements {
+			if e != nil {
+				r.resolveExpression(e)
+			}
+		}
+	case *ast.ObjectExpression:
+		for _, p := range v.Properties {
+			r.resolveProperty(p)
+		}
+	case *ast.CallExpression:
+		r.resolveExpression(v.Callee)
+		for _, arg := range v.Arguments {
+			r.resolveExpression(arg)
+		}
+	case *ast.NewExpression:
+		r.resolveExpression(v.Callee)
+		for _, arg := range v.Arguments {
+			r.resolveExpression(arg)
+		}
+	case *ast.ClassExpression:
+		r.resolveClass(v.Class)
+	case *ast.TemplateLiteral:
+		for _, e := range v.Expressions {
+			r.resolveExpression(e)
+		}
+	case *ast.TaggedTemplateExpression:
+		r.resolveExpression(v.Tag)
+		r.resolveExpression(v.Quasi)
+	case *ast.SpreadElement:
+		r.resolveExpression(v.Argument)
+	case *ast.YieldExpression:
+		if v.Argument != nil {
+			r.resolveExpression(v.Argument)
+		}
+	case *ast.AwaitExpression:
+		r.resolveExpression(v.Argument)
+	case *ast.AssignmentExpression:
+		r.resolvePattern(v.Left, false)
+		r.resolveExpression(v.Right)
+	case *ast.BinaryExpression:
+		r.resolveExpression(v.Left)
+		r.resolveExpression(v.Right)
+	case *ast.UnaryExpression:
+		r.resolveExpression(v.Argument)
+	case *ast.UpdateExpression:
+		r.resolveExpression(v.Argument)
+	case *ast.LogicalExpression:
+		r.resolveExpression(v.Left)
+		r.resolveExpression(v.Right)
+	case *ast.ConditionalExpression:
+		r.resolveExpression(v.Test)
+		r.resolveExpression(v.Consequent)
+		r.resolveExpression(v.Alternate)
+	case *ast.SequenceExpression:
+		for _, e := range v.Expressions {
+			r.resolveExpression(e)
+		}
+	case *ast.MemberExpression:
+		r.resolveExpression(v.Object)
+		if v.Computed {
+			r.resolveExpression(v.Property)
+		}
+	case *ast.Identifier:
+		r.resolveIdentifier(v)
+	case *ast.StringLiteral, *ast.NumericLiteral, *ast.BooleanLiteral, *ast.NullLiteral:
+	case *ast.ArrowFunctionExpression:
+		r.resolveFunction(v.Function, arrowScope)
+	case *ast.ThisExpression:
+		r.current.thisScope().usesThis = true
+	case *ast.Super, *ast.MetaProperty:
+	default:
+		panic("unknown expression type " + utils.TypeOf(v))
+	}
+}
+
+func (r *resolver) resolveProperty(p ast.Property) {
+	switch v := p.(type) {
+	case *ast.ObjectProperty:
+		if v.Computed {
+			r.resolveExpression(v.Key)
+		}
+		r.resolveExpression(v.Value)
+	case *ast.ObjectMethod:
+		if v.Computed {
+			r.resolveExpression(v.Key)
+		}
+		r.resolveFunction(v.Function, functionScope)
+	case *ast.SpreadElement:
+		r.resolveExpression(v.Argument)
+	default:
+		panic("unknown property type " + utils.TypeOf(v))
+	}
+}
+
+// resolvePattern resolves the target of a binding or of an assignment, with
+// the computed keys and default values of a destructuring pattern
+func (r *resolver) resolvePattern(p ast.Pattern, declaration bool) {
+	switch v := p.(type) {
+	case *ast.Identifier:
+		if declaration {
+			r.resolveDeclaration(v)
+		} else {
+			r.resolveIdentifier(v)
+		}
+	case *ast.MemberExpression:
+		r.resolveExpression(v)
+	case *ast.ObjectPattern:
+		for _, prop := range v.Properties {
+			if prop.Computed {
+				r.resolveExpression(prop.Key)
+			}
+			r.resolvePattern(prop.Value, declaration)
+		}
+		if v.Rest != nil {
+			r.resolvePattern(v.Rest, declaration)
+		}
+	case *ast.ArrayPattern:
+		for _, e := range v.Elements {
+			if e != nil {
+				r.resolvePattern(e, declaration)
+			}
+		}
+	case *ast.AssignmentPattern:
+		r.resolvePattern(v.Left, declaration)
+		r.resolveExpression(v.Right)
+	case *ast.RestElement:
+		r.resolvePattern(v.Argument, declaration)
+	default:
+		panic("unknown pattern type " + utils.TypeOf(v))
+	}
+}
+
+// resolveDeclaration resolves the identifier that declares a binding
+func (r *resolver) resolveDeclaration(i *ast.Identifier) {
+	r.refs[i] = r.current.lookup(i.Name)
+}
+
+func (r *resolver) resolveIdentifier(i *ast.Identifier) {
+	b := r.current.lookup(i.Name)
+	if i.Name == "arguments" {
+		b = r.resolveArguments(b)
+	}
+	if b == nil {
+		return
+	}
+
+	nested := b.scope.function() != r.current.function()
+	if nested {
+		b.captured = true
+	}
+	// a parameter may be referenced by the default value of a parameter
+	// before it
+	if (b.kind == bindingLet || b.kind == bindingConst) && (nested || i.Start < b.declEnd || b.clauseEnd > 0 && i.Start >= b.clauseEnd) || b.kind == bindingParam && i.Start < b.declEnd {
+		b.tdz = true
+		r.checks[i] = true
+	}
+	r.refs[i] = b
+}
+
+// resolveArguments resolves a reference to arguments, whose binding is
+// created by the nearest function that isn't an arrow function unless it's
+// shadowed by a declaration
+// A var declaration of arguments in the function is initialized with the
+// arguments object.
+func (r *resolver) resolveArguments(b *binding) *binding {
+	fs := r.current.thisScope()
+	if fs.kind == moduleScope {
+		return b
+	}
+	if b != nil && b.scope.thisScope() == fs && b.kind != bindingVar {
+		return b
+	}
+
+	if fs.arguments == nil {
+		if v, ok := fs.bindings["arguments"]; ok && v.kind == bindingVar {
+			// a var declaration without parameter expressions
+			v.kind = bindingArguments
+			fs.arguments = v
+		} else {
+			fs.arguments = &binding{
+				name:   "arguments",
+				goName: r.goName("arguments"),
+				kind:   bindingArguments,
+				scope:  fs,
+			}
+			fs.bindings["arguments"] = fs.arguments
+		}
+	}
+	if b != nil && b.scope.thisScope() == fs {
+		// a var declaration in the body of a function whose parameters have
+		// expressions
+		return b
+	}
+
+	return fs.arguments
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -18,6 +19,8 @@ func TestAll(t *testing.T) {
 		name   string
 		input  string
 		output string
+		// err is true if the program is expected to fail with output
+		err bool
 	}{
 		{
 			name:   "console.log",
@@ -44,6 +47,737 @@ func TestAll(t *testing.T) {
 			input:  "console.log(1 + 1)",
 			output: "2\n",
 		},
+		{
+			name:   "function declaration",
+			input:  "function id(x) { return x }\nconsole.log(id('hello'))",
+			output: "hello\n",
+		},
+		{
+			name:   "function declaration hoisting",
+			input:  "console.log(greet())\nfunction greet() { return 'hi' }",
+			output: "hi\n",
+		},
+		{
+			name:   "function expression",
+			input:  "let f = function(a, b) { return b }\nconsole.log(f('a', 'b'))",
+			output: "b\n",
+		},
+		{
+			name:   "named function expression",
+			input:  "let f = function g(x) { return x }\nconsole.log(f('named'))",
+			output: "named\n",
+		},
+		{
+			name:   "nested function",
+			input:  "function outer() { function inner() { return 'inner' }\nreturn inner() }\nconsole.log(outer())",
+			output: "inner\n",
+		},
+		{
+			name:   "closure",
+			input:  "function getter(v) { return function() { return v } }\nlet a = getter('a')\nlet b = getter('b')\nconsole.log(a(), b())",
+			output: "a b\n",
+		},
+		{
+			name:   "closure assignment",
+			input:  "let v = 'before'\nfunction set() { v = 'after' }\nset()\nconsole.log(v)",
+			output: "after\n",
+		},
+		{
+			name:   "block scope shadowing",
+			input:  "let x = 'outer'\n{ let x = 'inner'\nconsole.log(x) }\nconsole.log(x)",
+			output: "inner\nouter\n",
+		},
+		{
+			name:   "var hoisting out of block",
+			input:  "{ var h = 'hoisted' }\nconsole.log(h)",
+			output: "hoisted\n",
+		},
+		{
+			name:   "Go keyword as identifier",
+			input:  "let type = 'go'\nfunction func(args) { return args }\nconsole.log(func(type))",
+			output: "go\n",
+		},
+		{
+			name:   "implicit global",
+			input:  "function set() { g = 'global' }\nset()\nconsole.log(g)",
+			output: "global\n",
+		},
+		{
+			name:   "var redeclaration",
+			input:  "var a = 'one'\nvar a\nconsole.log(a)",
+			output: "one\n",
+		},
+		{
+			name:   "var hoisting in function",
+			input:  "var v = 'outer'\nfunction f() { v = 'inner'\nvar v\nreturn v }\nconsole.log(f(), v)",
+			output: "inner outer\n",
+		},
+		{
+			name:   "functions declared in blocks",
+			input:  "console.log(typeof helper)\nif (true) {\n  function helper() { return 'helper' }\n}\nconsole.log(helper())\n\nfunction outer() {\n  console.log(typeof inner)\n  {\n    console.log(inner())\n    function inner() { return 'inner' }\n  }\n  return inner()\n}\nconsole.log(outer())\n\nfunction notTaken() {\n  if (false) {\n    function skipped() {}\n  }\n  return typeof skipped\n}\nconsole.log(notTaken())\n\nfunction shadowed() {\n  let value = 'let'\n  {\n    function value() {}\n  }\n  return value\n}\nconsole.log(shadowed())\n\nfunction param(arg) {\n  {\n    function arg() {}\n  }\n  return arg\n}\nconsole.log(param('param'))\n\nfunction later() {\n  const get = () => fn\n  switch (1) {\n    case 1:\n      function fn() { return 'case' }\n  }\n  return get()()\n}\nconsole.log(later())\n\nfunction strictBlock() {\n  'use strict'\n  {\n    function local() {}\n  }\n  return typeof local\n}\nconsole.log(strictBlock())\n\nfunction nested() {\n  {\n    function twice() { return 'outer block' }\n    {\n      function twice() { return 'inner block' }\n    }\n  }\n  return twice()\n}\nconsole.log(nested())\n\nfunction overrides() {\n  function replaced() { return 'top' }\n  {\n    function replaced() { return 'block' }\n  }\n  return replaced()\n}\nconsole.log(overrides())\n\nfunction loopHead() {\n  for (let f of [1]) {\n    function f() {}\n  }\n  return typeof f\n}\nconsole.log(loopHead())",
+			output: "undefined\nhelper\nundefined\ninner\ninner\nundefined\nlet\nparam\ncase\nundefined\ninner block\nblock\nundefined\n",
+		},
+		{
+			name:   "let in nested function",
+			input:  "function f() { return x }\nlet x = 'initialized'\nconsole.log(f())",
+			output: "initialized\n",
+		},
+		{
+			name:   "let temporal dead zone",
+			input:  "console.log(x)\nlet x = 'x'",
+			output: "ReferenceError: Cannot access 'x' before initialization",
+			err:    true,
+		},
+		{
+			name:   "let temporal dead zone in nested function",
+			input:  "function f() { return x }\nf()\nlet x = 'x'",
+			output: "ReferenceError: Cannot access 'x' before initialization",
+			err:    true,
+		},
+		{
+			name:   "let temporal dead zone in initializer",
+			input:  "let x = x",
+			output: "ReferenceError: Cannot access 'x' before initialization",
+			err:    true,
+		},
+		{
+			name:   "const",
+			input:  "const c = 'const'\nconsole.log(c)",
+			output: "const\n",
+		},
+		{
+			name:   "const assignment",
+			input:  "const c = 'const'\nc = 'changed'",
+			output: "TypeError: Assignment to constant variable.",
+			err:    true,
+		},
+		{
+			name:   "const assignment in nested function",
+			input:  "const c = 'const'\nfunction f() { c = 'changed' }\nf()",
+			output: "TypeError: Assignment to constant variable.",
+			err:    true,
+		},
+		{
+			name:   "if",
+			input:  "if ('yes') console.log('then')\nif ('') { console.log('no') }",
+			output: "then\n",
+		},
+		{
+			name:   "if else",
+			input:  "if (0) { console.log('if') } else if ('') { console.log('else if') } else { console.log('else') }",
+			output: "else\n",
+		},
+		{
+			name:   "while",
+			input:  "let s = 'once'\nwhile (s) { console.log(s)\ns = '' }",
+			output: "once\n",
+		},
+		{
+			name:   "do while",
+			input:  "do { console.log('do') } while ('')",
+			output: "do\n",
+		},
+		{
+			name:   "do while continue",
+			input:  "let s = 'again'\ndo { console.log(s)\nif (s) { s = ''\ncontinue }\nconsole.log('skipped') } while (s)",
+			output: "again\n",
+		},
+		{
+			name:   "for",
+			input:  "for (let s = 'for'; s; s = '') console.log(s)",
+			output: "for\n",
+		},
+		{
+			name:   "for break",
+			input:  "for (;;) { console.log('loop')\nbreak }",
+			output: "loop\n",
+		},
+		{
+			name:   "for var",
+			input:  "for (var s = 'var'; s; s = '') { console.log(s) }\nconsole.log(s)",
+			output: "var\n\n",
+		},
+		{
+			name:   "for let per iteration binding",
+			input:  "let next = 'b'\nfunction step() { let r = next\nnext = ''\nreturn r }\nlet f1, f2\nfor (let s = 'a'; s; s = step()) { if (f1) { f2 = function() { return s } } else { f1 = function() { return s } } }\nconsole.log(f1(), f2())",
+			output: "a b\n",
+		},
+		{
+			name:   "labeled break",
+			input:  "outer: while ('a') { while ('b') { break outer }\nconsole.log('inner') }\nconsole.log('done')",
+			output: "done\n",
+		},
+		{
+			name:   "labeled continue",
+			input:  "let s = 'x'\nouter: for (; s; ) { s = ''\nfor (;;) { continue outer }\nconsole.log('inner') }\nconsole.log('done')",
+			output: "done\n",
+		},
+		{
+			name:   "labeled block",
+			input:  "block: { console.log('in')\nif ('yes') break block\nconsole.log('skipped') }\nconsole.log('out')",
+			output: "in\nout\n",
+		},
+		{
+			name:   "duplicate labels",
+			input:  "a: for (;;) { break a }\na: for (;;) { break a }\nconsole.log('done')",
+			output: "done\n",
+		},
+		{
+			name:   "stacked loop labels",
+			input:  "var out = [];\na: b: c: for (var i = 0; i < 4; i++) {\n  for (var j = 0; j < 3; j++) {\n    if (j === 1 && i === 0) continue a;\n    if (j === 1 && i === 1) continue b;\n    if (j === 1 && i === 2) continue c;\n    if (i === 3) break b;\n    out.push(i + ':' + j);\n  }\n}\nconsole.log(out.join(' '));\nout = [];\nx: y: z: while (true) {\n  for (;;) {\n    if (out.length === 0) { out.push('z'); break z; }\n  }\n}\nx: y: z: while (true) {\n  for (;;) {\n    if (out.length === 1) { out.push('y'); break y; }\n  }\n}\nx: y: z: do {\n  for (;;) { out.push('x'); break x; }\n} while (true);\nconsole.log(out.join(' '));\nout = [];\np: q: r: for (var v of [1, 2, 3]) {\n  for (var w of ['a', 'b']) {\n    try {\n      if (v === 1 && w === 'b') continue p;\n      if (v === 2 && w === 'b') continue q;\n      if (w === 'b') continue r;\n      out.push(v + w);\n    } finally {\n      out.push('f');\n    }\n  }\n  if (v === 3) break p;\n}\nconsole.log(out.join(' '));\nout = [];\np: q: r: for (var v of [1, 2, 3, 4]) {\n  if (v === 1) continue p;\n  if (v === 2) continue q;\n  out.push(v);\n  if (v === 3) break r;\n}\ns: t: for (var k in { m: 1, n: 2 }) {\n  try { out.push(k); if (k === 'm') continue s; break t; } finally { out.push('g'); }\n}\nconsole.log(out.join(' '));",
+			output: "0:0 1:0 2:0\nz y x\n1a f f 2a f f 3a f f\n3 m g n g\n",
+		},
+		{
+			name:   "object literal",
+			input:  "var x = 1\nconsole.log({ a: 1, b: 'x', x, c: { d: { e: { f: 1 } } } }, {})",
+			output: "{ a: 1, b: 'x', x: 1, c: { d: { e: [Object] } } } {}\n",
+		},
+		{
+			name:   "object literal key order",
+			input:  "console.log({ b: 1, 2: 2, a: 3, 1: 4, 'a-b': 5 })",
+			output: "{ '1': 4, '2': 2, b: 1, a: 3, 'a-b': 5 }\n",
+		},
+		{
+			name:   "object literal computed keys",
+			input:  "var k = 'dyn'\nconsole.log({ [k]: 1, 1.5: 2, 2.5e-1: 3, a: 4, a: 5 })",
+			output: "{ dyn: 1, '1.5': 2, '0.25': 3, a: 5 }\n",
+		},
+		{
+			name:   "object literal methods",
+			input:  "console.log({ f() {}, g: function () {}, h: function named() {}, get a() { return 1 }, set b(v) {} })",
+			output: "{\n  f: [Function: f],\n  g: [Function: g],\n  h: [Function: named],\n  a: [Getter],\n  b: [Setter]\n}\n",
+		},
+		{
+			name:   "object literal __proto__",
+			input:  "var proto = { greet() {} }\nvar o = { __proto__: proto, y: 1 }\nconsole.log(o, Object.getPrototypeOf(o))",
+			output: "{ y: 1 } { greet: [Function: greet] }\n",
+		},
+		{
+			name:   "Object.create",
+			input:  "var o = Object.create({ p: 1 }, { own: { value: 1, enumerable: 1 }, hidden: { value: 2 } })\nconsole.log(o, Object.getOwnPropertyDescriptor(o, 'hidden'))",
+			output: "{ own: 1 } { value: 2, writable: false, enumerable: false, configurable: false }\n",
+		},
+		{
+			name:   "Object.defineProperty accessor",
+			input:  "var o = {}\nObject.defineProperty(o, 'a', { get: function () { return 'got' }, enumerable: 1 })\nconsole.log(Object.assign({}, o), Object.getOwnPropertyDescriptor(o, 'a'))",
+			output: "{ a: 'got' } {\n  get: [Function: get],\n  set: undefined,\n  enumerable: true,\n  configurable: false\n}\n",
+		},
+		{
+			name:   "Object.defineProperty non-configurable",
+			input:  "var o = {}\nObject.defineProperty(o, 'a', { value: 1 })\nObject.defineProperty(o, 'a', { value: 2 })",
+			output: "TypeError: Cannot redefine property: a",
+			err:    true,
+		},
+		{
+			name:   "Object.freeze",
+			input:  "var o = Object.freeze({ a: 1 })\nconsole.log(Object.isFrozen(o), Object.isSealed(o), Object.isExtensible(o), Object.isFrozen({}))",
+			output: "true true false false\n",
+		},
+		{
+			name:   "array literal",
+			input:  "var x = 'x'\nconsole.log([1, 'a', x, [2, [3, [4, [5]]]]], [])",
+			output: "[ 1, 'a', 'x', [ 2, [ 3, [Array] ] ] ] []\n",
+		},
+		{
+			name:   "array literal holes",
+			input:  "console.log([1, , 3], [, , ], [1, 2, , ])",
+			output: "[ 1, <1 empty item>, 3 ] [ <2 empty items> ] [ 1, 2, <1 empty item> ]\n",
+		},
+		{
+			name:   "long array",
+			input:  "console.log([1, 2, 3, 4, 5, 6, 7])",
+			output: "[\n  1, 2, 3, 4,\n  5, 6, 7\n]\n",
+		},
+		{
+			name:   "Array builtins",
+			input:  "console.log(Array.isArray([]), Array.isArray({}), Array(3), Array(1, 2), Array.of(7), Array.from('abc'), Array.from({ length: 2 }))",
+			output: "true false [ <3 empty items> ] [ 1, 2 ] [ 7 ] [ 'a', 'b', 'c' ] [ undefined, undefined ]\n",
+		},
+		{
+			name:   "Array.from map function",
+			input:  "console.log(Array.from([1, 2, 3], function (v, i) { return i }))",
+			output: "[ 0, 1, 2 ]\n",
+		},
+		{
+			name:   "invalid array length",
+			input:  "Array(1.5)",
+			output: "RangeError: Invalid array length",
+			err:    true,
+		},
+		{
+			name:   "Object.keys",
+			input:  "var o = { b: 1, a: [2] }\nconsole.log(Object.keys(o), Object.values(o), Object.entries(o), Object.getOwnPropertyNames([1]))",
+			output: "[ 'b', 'a' ] [ 1, [ 2 ] ] [ [ 'b', 1 ], [ 'a', [ 2 ] ] ] [ '0', 'length' ]\n",
+		},
+		{
+			name:   "replaced builtin methods",
+			input:  "var max = Math.max\nMath.max = function () { return 'patched max ' + (this === Math) }\nconsole.log(Math.max(1, 2), max(1, 2))\nObject.keys = () => ['patched keys']\nconsole.log(Object.keys({ a: 1 }))\nJSON = { stringify(v) { return 'patched stringify' } }\nconsole.log(JSON.stringify({}))\nvar log = console.log\nconsole.log = function (s) { log('patched log:', s) }\nconsole.log('hi')",
+			output: "patched max true 2\n[ 'patched keys' ]\npatched stringify\npatched log: hi\n",
+		},
+		{
+			name:   "arithmetic operators",
+			input:  "console.log(1 + 2, '1' + 2, [1, 2] + 'x', {} + 1, 5 - '2', '3' * '4', 7 / 2, 1 / 0, (0 - 7) % 3, 2 ** 10, 1 ** (1 / 0), 0.1 + 0.2)",
+			output: "3 12 1,2x [object Object]1 3 12 3.5 Infinity -1 1024 NaN 0.30000000000000004\n",
+		},
+		{
+			name:   "bitwise operators",
+			input:  "console.log(1 << 31, (0 - 1) >> 1, (0 - 1) >>> 0, 5 & 3, 5 | 3, 5 ^ 3, 1 << 33, 2147483648 | 0)",
+			output: "-2147483648 -1 4294967295 1 7 6 2 -2147483648\n",
+		},
+		{
+			name:   "equality operators",
+			input:  "var u\nconsole.log(1 == '1', 0 == '', 1 === '1', {} == {}, [1] == 1, u == 0, 0 != '0', 0 !== '0')",
+			output: "true true false false true false false true\n",
+		},
+		{
+			name:   "relational operators",
+			input:  "var u\nconsole.log(1 < 2, '10' < '9', '10' < 9, 2 >= 2, 'b' > 'a', 1 < u, 1 >= u)",
+			output: "true true false true true false false\n",
+		},
+		{
+			name:   "in and instanceof",
+			input:  "console.log('a' in { a: 1 }, 0 in [], 'length' in [], [] instanceof Array, {} instanceof Array)",
+			output: "true false true true false\n",
+		},
+		{
+			name:   "in non-object",
+			input:  "'a' in 'abc'",
+			output: "TypeError: Cannot use 'in' operator to search for 'a' in abc",
+			err:    true,
+		},
+		{
+			name:   "valueOf and toString conversion",
+			input:  "var o = { valueOf: function () { return 42 }, toString: function () { return 'str' } }\nconsole.log(o + 1, o * 2, o == 42, o > 41)",
+			output: "43 84 true true\n",
+		},
+		{
+			name:   "compound assignment",
+			input:  "var x = 1\nx += 2\nx *= 10\nx %= 7\nx **= 2\nlet s = 'a'\ns += 1\nlet b = 5\nb <<= 2\nb >>>= 1\nb |= 1\nconsole.log(x, s, b, x -= 1)",
+			output: "4 a1 11 3\n",
+		},
+		{
+			name:   "compound assignment to const",
+			input:  "const c = 1\nc += 1",
+			output: "TypeError: Assignment to constant variable.",
+			err:    true,
+		},
+		{
+			name:   "operand evaluation order",
+			input:  "var i = 1\nfunction f() { i = 10\nreturn 2 }\nconsole.log(i + f(), i)",
+			output: "3 10\n",
+		},
+		{
+			name:   "unary operators",
+			input:  "var i = 2\nconsole.log(-i, +'3', ~5, -'x', +'', -0, !0, !'a', void i)",
+			output: "-2 3 -6 NaN 0 -0 true false undefined\n",
+		},
+		{
+			name:   "typeof",
+			input:  "console.log(typeof 1, typeof 'a', typeof {}, typeof [], typeof function () {}, typeof u, typeof undeclared)\nvar u",
+			output: "number string object object function undefined undefined\n",
+		},
+		{
+			name:   "typeof in temporal dead zone",
+			input:  "typeof x\nlet x",
+			output: "ReferenceError: Cannot access 'x' before initialization",
+			err:    true,
+		},
+		{
+			name:   "update expressions",
+			input:  "var i = 0\nlet j = 5\nlet s = 'a'\ns++\nconsole.log(i++, i, ++i, j--, --j, j, s)\nfor (var k = 0; k < 3; k++) {}\nconsole.log(k, i++ + i)",
+			output: "0 1 2 5 3 3 NaN\n3 5\n",
+		},
+		{
+			name:   "update const",
+			input:  "const c = 1\nc++",
+			output: "TypeError: Assignment to constant variable.",
+			err:    true,
+		},
+		{
+			name:   "update undeclared",
+			input:  "x++",
+			output: "ReferenceError: x is not defined",
+			err:    true,
+		},
+		{
+			name:   "logical operators",
+			input:  "function f() { console.log('called')\nreturn 3 }\nconsole.log(0 && f(), 1 && 'b', '' || 'c', 'd' || f())\nif (!(1 < 0) && (0 || f())) { console.log('ok') }",
+			output: "0 b c d\ncalled\nok\n",
+		},
+		{
+			name:   "conditional",
+			input:  "var n = 0\nfunction f() { n++\nreturn n }\nconsole.log(f() ? 'yes' : f(), 0 ? f() : 'no', n)",
+			output: "yes no 1\n",
+		},
+		{
+			name:   "sequence",
+			input:  "var n = 0\nvar s = (n++, n++, n)\nconsole.log(s, (n = 5, n))",
+			output: "2 5\n",
+		},
+		{
+			name:   "delete",
+			input:  "var o = { a: 1, b: 2, c: 3 }\nvar k = 'b'\ng = 1\nvar v = 1\nconsole.log(delete o.a, delete o[k], o, delete g, typeof g, delete v, v, delete 1)",
+			output: "true true { c: 3 } true undefined false 1 true\n",
+		},
+		{
+			name:   "undefined, null and booleans",
+			input:  "var x\nconsole.log(x, undefined, null, true, false, { n: null, t: true })\nconsole.log(typeof null, typeof undefined, typeof true, typeof NaN, typeof Infinity)",
+			output: "undefined undefined null true false { n: null, t: true }\nobject undefined boolean number number\n",
+		},
+		{
+			name:   "null and undefined conversion",
+			input:  "console.log(null == undefined, null === undefined, null == 0, undefined == 0, null == false, true == 1, true === 1)\nconsole.log(null + 1, undefined + 1, true + 1, 'a' + null, 'a' + true, !null, null || 'x')",
+			output: "true false false false false true false\n1 NaN 2 anull atrue true x\n",
+		},
+		{
+			name:   "NaN and Infinity",
+			input:  "console.log(NaN, Infinity, -Infinity, NaN == NaN, 1 / 0, 0 / 0, Infinity - Infinity)",
+			output: "NaN Infinity -Infinity false Infinity NaN NaN\n",
+		},
+		{
+			name:   "read-only globals",
+			input:  "undefined = 1\nNaN = 1\nInfinity = 1\nconsole.log(undefined, NaN, Infinity)",
+			output: "undefined NaN Infinity\n",
+		},
+		{
+			name:   "null prototype",
+			input:  "var o = Object.create(null)\nconsole.log(o, Object.getPrototypeOf(o), { __proto__: null, a: 1 })",
+			output: "[Object: null prototype] {} null [Object: null prototype] { a: 1 }\n",
+		},
+		{
+			name:   "try catch",
+			input:  "try { throw Error('boom') } catch (e) { console.log('caught ' + e) }\ntry { null() } catch (e) { console.log('' + e) }\ntry { x } catch (e) { console.log('' + e) }\ntry { throw { a: 1 } } catch (e) { console.log(e) }",
+			output: "caught Error: boom\nTypeError: null is not a function\nReferenceError: x is not defined\n{ a: 1 }\n",
+		},
+		{
+			name:   "try finally",
+			input:  "function f() { try { console.log('try'); return 1 } finally { console.log('finally') } }\nfunction g() { try { return 1 } finally { return 2 } }\nconsole.log(f(), g())\nfor (var i = 0; i < 3; i++) { try { if (i == 1) continue; if (i == 2) break; console.log('i', i) } finally { console.log('f', i) } }\nouter: for (var j = 0; j < 2; j++) { for (;;) { try { continue outer } finally { console.log('j', j) } } }",
+			output: "try\nfinally\n1 2\ni 0\nf 0\nf 1\nf 2\nj 0\nj 1\n",
+		},
+		{
+			name:   "rethrow",
+			input:  "try { try { throw TypeError('inner') } catch (e) { throw e } finally { console.log('cleanup') } } catch (e) { console.log('' + e) }\nconsole.log('' + RangeError('r'), '' + ReferenceError(), '' + SyntaxError('s'))",
+			output: "cleanup\nTypeError: inner\nRangeError: r ReferenceError SyntaxError: s\n",
+		},
+		{
+			name:   "call stack overflow",
+			input:  "function f() { return f() + 1 }\ntry { f() } catch (e) { console.log(e instanceof RangeError, e.message) }\nclass C { constructor() { new C() } }\ntry { new C() } catch (e) { console.log(e.name, e.message) }\nvar depth = 0\nfunction g() { depth++; g() }\ntry { g() } catch (e) { console.log(depth > 1000) }\nfunction sum(n) { return n === 0 ? 0 : n + sum(n - 1) }\nconsole.log(sum(5000))",
+			output: "true Maximum call stack size exceeded\nRangeError Maximum call stack size exceeded\ntrue\n12502500\n",
+		},
+		{
+			name:   "uncaught error",
+			input:  "function deep() { throw Error('deep') }\nfunction mid() { deep() }\nmid()",
+			output: "Uncaught Error: deep\n    at deep ([stdin]:1)\n    at mid ([stdin]:2)\n    at [stdin]:3\n",
+			err:    true,
+		},
+		{
+			name:   "classes",
+			input:  "class Point {\n  constructor(x, y) { this.x = x; this.y = y }\n  sum() { return this.x + this.y }\n  get label() { return 'point ' + this.sum() }\n  static origin() { return new Point(0, 0) }\n}\nconst p = new Point(1, 2)\nconsole.log(p, p.sum(), p.label, Point.origin(), Point, class {})",
+			output: "Point { x: 1, y: 2 } 3 point 3 Point { x: 0, y: 0 } [class Point] [class (anonymous)]\n",
+		},
+		{
+			name:   "class inheritance",
+			input:  "class Animal {\n  constructor(name) { this.name = name }\n  speak() { return this.name + ' makes a sound' }\n  static create(name) { return new this(name) }\n}\nclass Dog extends Animal {\n  constructor(name) { super(name); this.tricks = 0 }\n  speak() { return super.speak() + ' (woof)' }\n}\nclass Puppy extends Dog {}\nconst p = Puppy.create('rex')\nconsole.log(p, p.speak(), Dog)\nconsole.log(p instanceof Puppy, p instanceof Dog, p instanceof Animal, p instanceof Object, new Animal('cat') instanceof Dog)",
+			output: "Puppy { name: 'rex', tricks: 0 } rex makes a sound (woof) [class Dog extends Animal]\ntrue true true true false\n",
+		},
+		{
+			name:   "super in object literal methods",
+			input:  "const base = {\n  hi() { return 'base hi' },\n  get name() { return 'base ' + this.tag },\n  set name(v) { this.stored = 'base set ' + v },\n  count: 1,\n}\nconst obj = {\n  __proto__: base,\n  tag: 'obj',\n  hi() { return 'obj > ' + super.hi() },\n  get name() { return 'obj > ' + super.name },\n  set name(v) { super.name = v + '!' },\n  arrow() { return (() => super.hi())() },\n  bump() { super.count += 1; return [this.count, base.count] },\n  increment() { super.count++; return this.count },\n  *gen() { yield super.hi() },\n  async later() { return super.hi() },\n}\nconsole.log(obj.hi(), obj.name)\nobj.name = 'x'\nconsole.log(obj.stored, Object.keys(obj).includes('stored'))\nconsole.log(obj.arrow(), obj.bump(), obj.increment(), [...obj.gen()])\nconst plain = { hi() { return super.hi === undefined } }\nconsole.log(plain.hi(), Object.getPrototypeOf(plain) === Object.prototype)\nconst nested = { __proto__: obj, hi() { return 'nested > ' + super.hi() } }\nconsole.log(nested.hi())\nObject.setPrototypeOf(obj, { hi() { return 'swapped' } })\nconsole.log(obj.hi())\nobj.later().then((v) => console.log('async', v))",
+			output: "obj > base hi obj > base obj\nbase set x! true\nbase hi [ 2, 1 ] 2 [ 'base hi' ]\ntrue true\nnested > obj > base hi\nobj > swapped\nasync swapped\n",
+		},
+		{
+			name:   "new and new.target",
+			input:  "function P(x) { this.x = x; console.log(new.target === P) }\nconst o = new P(1)\nconsole.log(o, P.prototype, o instanceof P)\nclass T { constructor() { console.log(new.target === T, new.target === U) } }\nclass U extends T {}\nnew T()\nnew U()\ntry { new 1 } catch (e) { console.log('' + e) }",
+			output: "true\nP { x: 1 } {} true\ntrue false\nfalse true\nTypeError: 1 is not a constructor\n",
+		},
+		{
+			name:   "class errors",
+			input:  "class A {}\ntry { A() } catch (e) { console.log('' + e) }\nclass B extends A { constructor() { this.x = 1 } }\ntry { new B() } catch (e) { console.log('' + e) }\nclass C extends A { constructor() { super(); return 1 } }\ntry { new C() } catch (e) { console.log('' + e) }\ntry { class D extends 3 {} } catch (e) { console.log('' + e) }\nclass N extends null {}\ntry { new N() } catch (e) { console.log('' + e) }",
+			output: "TypeError: Class constructor A cannot be invoked without 'new'\nReferenceError: Must call super constructor in derived class before accessing 'this' or returning from derived constructor\nTypeError: Derived constructors may only return object or undefined\nTypeError: Class extends value 3 is not a constructor or null\nTypeError: Super constructor null of N is not a constructor\n",
+		},
+		{
+			name:   "extending builtins",
+			input:  "class ValidationError extends Error {\n  constructor(message) { super(message); this.name = 'ValidationError' }\n}\ntry { throw new ValidationError('bad input') } catch (e) { console.log('' + e, e instanceof ValidationError, e instanceof Error) }\nclass Stack extends Array {}\nconst s = new Stack()\ns.push(1, 2)\nconsole.log(s, s.length, s instanceof Stack, Array.isArray(s))",
+			output: "ValidationError: bad input true true\nStack(2) [ 1, 2 ] 2 true true\n",
+		},
+		{
+			name:   "arrow functions",
+			input:  "const add = (a, b) => a + b\nconst sq = x => { return x * x }\nconst point = (x, y) => ({ x, y })\nconsole.log(add(1, 2), sq(4), point(1, 2))\nconsole.log((() => {}).prototype)\ntry { new (() => {})() } catch (e) { console.log(e instanceof TypeError) }",
+			output: "3 16 { x: 1, y: 2 }\nundefined\ntrue\n",
+		},
+		{
+			name:   "this binding",
+			input:  "const obj = {\n  name: 'obj',\n  regular: function () { return this.name },\n  nested: function () { const inner = () => this.name; return inner() },\n}\nconsole.log(obj.regular(), obj.nested())\nconst f = obj.regular\nconsole.log(f())\nfunction sloppy() { return typeof this }\nfunction strict() { 'use strict'; return this }\nconsole.log(sloppy(), strict())\nclass Counter {\n  constructor() { this.count = 0; this.inc = () => { this.count = this.count + 1 } }\n  unbound() { return this }\n}\nconst c = new Counter()\nconst inc = c.inc\ninc(); inc()\nconst unbound = c.unbound\nconsole.log(c.count, unbound())",
+			output: "obj obj\nundefined\nobject undefined\n2 undefined\n",
+		},
+		{
+			name:   "arrow functions in classes",
+			input:  "function Target() { return (() => new.target)() }\nconsole.log(new Target() === undefined, Target())\nclass A { hi() { return 'A' } }\nclass B extends A {\n  constructor() { const init = () => super(); init(); this.x = 1 }\n  hi() { return (() => super.hi() + 'B')() }\n}\nconsole.log(new B().hi(), new B().x)",
+			output: "false undefined\nAB 1\n",
+		},
+		{
+			name:   "template literals",
+			input:  "const name = 'world', n = 3\nconst o = { toString() { return 'O' } }\nconsole.log(`hello ${name}!`, `${n + 1}`, `plain`, `${n}${n}`)\nconsole.log(`${null} ${undefined} ${[1, 2]} ${o}`)\nlet i = 0\nconsole.log(`${i} ${i = i + 1} ${i}`)\nconsole.log(`multi\nline`)",
+			output: "hello world! 4 plain 33\nnull undefined 1,2 O\n0 1 1\nmulti\nline\n",
+		},
+		{
+			name:   "tagged templates",
+			input:  "function tag(strings) { return strings }\nconst s = tag`a${1}b\\n${2}`\nconsole.log(s, s.raw, Object.isFrozen(s), Object.isFrozen(s.raw), Object.keys(s))\nfunction site() { return tag`same` }\nconsole.log(site() === site(), tag`same` === tag`same`)\nconst obj = { prefix: '>', fmt(strings, v) { return this.prefix + strings.join(v) } }\nconsole.log(obj.fmt`value ${42}!`)",
+			output: "[ 'a', 'b\\n', '' ] [ 'a', 'b\\\\n', '' ] true true [ '0', '1', '2' ]\ntrue false\n>value 42!\n",
+		},
+		{
+			name:   "control characters in literals",
+			input:  "console.log(\"a\\0b\".length, \"\\ufeff\".length, \"\\u200b\\u2028\".length, `x\n\ty`, \"\\x07\\x1b\".length)",
+			output: "3 1 2 x\n\ty 2\n",
+		},
+		{
+			name:   "destructuring declarations",
+			input:  "const obj = { a: 1, b: [2, 3], c: { d: 4 } }\nconst { a, b: [x, y], c: { d } } = obj\nlet { e = 5, a: renamed = 9 } = obj\nvar [p, , q = 10, ...rest] = [1, 2, undefined, 4, 5]\nconst { a: first, ...others } = obj\nconst key = 'b'\nconst { [key]: computed } = obj\nconst [c1, c2] = 'hé'\nconsole.log(a, x, y, d, e, renamed, p, q, rest)\nconsole.log(first, others, computed, c1, c2)",
+			output: "1 2 3 4 5 1 1 10 [ 4, 5 ]\n1 { b: [ 2, 3 ], c: { d: 4 } } [ 2, 3 ] h é\n",
+		},
+		{
+			name:   "destructuring assignments",
+			input:  "let m = 1, n = 2\n;[m, n] = [n, m]\nconst target = {}\n;({ a: target.first, b: [target.second] } = { a: 1, b: [2] })\nlet calls = 0\nconst def = () => { calls = calls + 1; return 'd' }\nlet u, v\n;({ u = def(), v = def() } = { u: 'given' })\nlet la\nconsole.log(m, n, target, u, v, calls, ({ a: la } = { a: 7 }).a, la)",
+			output: "2 1 { first: 1, second: 2 } given d 1 7 7\n",
+		},
+		{
+			name:   "destructuring parameters",
+			input:  "function f({ x, y = 2 }, [z] = [3]) { return x + y + z }\nconst name = ({ name }) => name\nclass P { constructor({ x = 0, y = 0 } = {}) { this.x = x; this.y = y } }\nconsole.log(f({ x: 1 }), f({ x: 1, y: 1 }, [1]), f.length, name({ name: 'n' }))\nconsole.log(new P({ x: 1 }), new P(), P.length)\ntry { throw { code: 42, msg: 'x' } } catch ({ code, msg }) { console.log(code, msg) }",
+			output: "6 3 1 n\nP { x: 1, y: 0 } P { x: 0, y: 0 } 0\n42 x\n",
+		},
+		{
+			name:   "destructuring errors",
+			input:  "try { const { z } = null } catch (e) { console.log(e.message) }\ntry { const {} = undefined } catch (e) { console.log(e.message) }\ntry { const [z] = {} } catch (e) { console.log(e.message) }\nconst { a } = { a: 1 }\n;({ a } = { a: 2 })",
+			output: "Cannot destructure property 'z' of 'null' as it is null.\nCannot destructure 'undefined' as it is undefined.\n#<Object> is not iterable\nUncaught TypeError: Assignment to constant variable.\n    at [stdin]:5\n",
+			err:    true,
+		},
+		{
+			name:   "spread arguments",
+			input:  "function sum(...nums) { return nums.reduce((t, n) => t + n, 0) }\nconst args = [1, 2, 3]\nconsole.log(sum(...args), sum(0, ...args, 4), sum(...'12'))\nclass P { constructor(x, y) { this.x = x; this.y = y } }\nconsole.log(new P(...[1, 2]))",
+			output: "6 10 012\nP { x: 1, y: 2 }\n",
+		},
+		{
+			name:   "spread elements",
+			input:  "const a = [1, 2]\nconsole.log([0, ...a, ...'hi', 3])\nconsole.log([...[1, , 3]], [, ...a])\nlet x = 1\nconsole.log([x, ...[(x = 10)]], [...[x], (x = 20)])",
+			output: "[ 0, 1, 2, 'h', 'i', 3 ]\n[ 1, undefined, 3 ] [ <1 empty item>, 1, 2 ]\n[ 1, 10 ] [ 10, 20 ]\n",
+		},
+		{
+			name:   "object spread",
+			input:  "const base = { a: 1, b: 2, get c() { return 3 } }\nconsole.log({ ...base, b: 20, ...null, ...undefined, ...5 })\nconsole.log({ b: 0, ...base })",
+			output: "{ a: 1, b: 20, c: 3 }\n{ b: 2, a: 1, c: 3 }\n",
+		},
+		{
+			name:   "rest parameters",
+			input:  "function f(a, ...rest) { return rest }\nfunction g(a, ...[b, c]) { return a + b + c }\nconsole.log(f(1), f(1, 2, 3), g('a', 'b', 'c', 'd'))\nconsole.log(f.length, g.length, ((...r) => r).length, ((...r) => r)(1, 2))",
+			output: "[] [ 2, 3 ] abc\n1 1 0 [ 1, 2 ]\n",
+		},
+		{
+			name:   "default parameters",
+			input:  "function f(a, b = a * 2) { return [a, b] }\nconst x = 'outer'\nfunction g(a = () => x) { var x = 'inner'; return [a(), x] }\nfunction h(a, b = () => a) { var a; const before = a; a = 2; return [before, a, b()] }\nconsole.log(f(1), f(1, 5), g(), h(1), f.length)\nfunction tdz(a = b, b) {}\ntry { tdz() } catch (e) { console.log(e.message) }",
+			output: "[ 1, 2 ] [ 1, 5 ] [ 'outer', 'inner' ] [ 1, 2, 1 ] 1\nCannot access 'b' before initialization\n",
+		},
+		{
+			name:   "arguments object",
+			input:  "function count() { return arguments.length }\nconsole.log(count(), count(1, 2, 3))\nfunction mapped(a, b) { Object.assign(arguments, { 0: 10 }); b = 20; return [a, ...arguments] }\nfunction strict(a) { 'use strict'; Object.assign(arguments, { 0: 10 }); a = 5; return [a, ...arguments] }\nfunction unmapped(a, b = 1) { Object.assign(arguments, { 0: 10 }); return [a, ...arguments] }\nconsole.log(mapped(1, 2), strict(1), unmapped(1))\nfunction outer() { return (() => arguments)('inner') }\nconsole.log(outer('outer'), '' + outer())\nfunction callee() { return arguments.callee === callee }\nfunction strictCallee() { 'use strict'; try { arguments.callee } catch (e) { return e.message } }\nconsole.log(callee(), strictCallee())",
+			output: "0 3\n[ 10, 10, 20 ] [ 5, 10 ] [ 1, 10 ]\n[Arguments] { '0': 'outer' } [object Arguments]\ntrue 'caller', 'callee', and 'arguments' properties may not be accessed on strict mode functions or the arguments objects for calls to them\n",
+		},
+		{
+			name:   "strict mode assignments",
+			input:  "'use strict';\nfunction attempt(name, f) {\n  try {\n    f();\n    console.log(name, 'ok');\n  } catch (e) {\n    console.log(name, e.name + ': ' + e.message);\n  }\n}\nattempt('frozen', () => { Object.freeze({a: 1}).a = 2; });\nattempt('frozen update', () => { const o = Object.freeze({a: 1}); o.a += 1; });\nattempt('frozen increment', () => { const o = Object.freeze({a: 1}); o['a']++; });\nattempt('not extensible', () => { Object.preventExtensions({}).x = 1; });\nattempt('getter only', () => { ({get a() { return 1; }}).a = 2; });\nattempt('primitive', () => { const n = 1; n.x = 2; });\nattempt('string length', () => { const s = 's'; s.length = 2; });\nattempt('undeclared', () => { undeclared = 1; });\nattempt('undeclared increment', () => { counter++; });\nattempt('read only global', () => { undefined = 1; });\nattempt('destructuring', () => { ({a: Object.freeze({b: 1}).b} = {a: 2}); });\nattempt('frozen array', () => { Object.freeze([1])[0] = 2; });",
+			output: "frozen TypeError: Cannot assign to read only property 'a' of object '#<Object>'\nfrozen update TypeError: Cannot assign to read only property 'a' of object '#<Object>'\nfrozen increment TypeError: Cannot assign to read only property 'a' of object '#<Object>'\nnot extensible TypeError: Cannot add property x, object is not extensible\ngetter only TypeError: Cannot set property a of #<Object> which has only a getter\nprimitive TypeError: Cannot create property 'x' on number '1'\nstring length TypeError: Cannot assign to read only property 'length' of string 's'\nundeclared ReferenceError: undeclared is not defined\nundeclared increment ReferenceError: counter is not defined\nread only global TypeError: Cannot assign to read only property 'undefined' of object '#<Object>'\ndestructuring TypeError: Cannot assign to read only property 'b' of object '#<Object>'\nfrozen array TypeError: Cannot assign to read only property '0' of object '[object Array]'\n",
+		},
+		{
+			name:   "strict mode function names and deletes",
+			input:  "function report(f) {\n  try { console.log(f()) } catch (e) { console.log(e.name + ': ' + e.message) }\n}\nreport(function () { return (function f() { 'use strict'; f = 1 })() })\nreport(function () { return (function f() { 'use strict'; f += 1 })() })\nreport(function () { return (function f() { 'use strict'; f++ })() })\nreport(function () { return (function f() { 'use strict'; --f })() })\nreport(function () { return (function f() { 'use strict'; [f] = [1] })() })\nreport(function () { return (function f() { 'use strict'; ({ f } = { f: 1 }) })() })\nreport(function () { return (function f() { f = 1; f++; [f] = [1]; return typeof f })() })\nreport(function () { return new (class { m() { return (function g() { g = 1 })() } })().m() })\nreport(function () { return new (class { m() { return (function g() { g++ })() } })().m() })\nreport(function () { 'use strict'; return delete Object.freeze({ a: 1 }).a })\nreport(function () { 'use strict'; return delete [].length })\nreport(function () { 'use strict'; return delete Math.PI })\nreport(function () { 'use strict'; var k = 'a'; return delete Object.seal({ a: 1 })[k] })\nreport(function () { 'use strict'; return delete { a: 1 }.a })\nreport(function () { 'use strict'; return delete {}.missing })\nreport(function () { return delete Object.freeze({ a: 1 }).a })\nreport(function () { return new (class { m() { return delete [].length } })().m() })",
+			output: "TypeError: Assignment to constant variable.\nTypeError: Assignment to constant variable.\nTypeError: Assignment to constant variable.\nTypeError: Assignment to constant variable.\nTypeError: Assignment to constant variable.\nTypeError: Assignment to constant variable.\nfunction\nTypeError: Assignment to constant variable.\nTypeError: Assignment to constant variable.\nTypeError: Cannot delete property 'a' of #<Object>\nTypeError: Cannot delete property 'length' of [object Array]\nTypeError: Cannot delete property 'PI' of #<Object>\nTypeError: Cannot delete property 'a' of #<Object>\ntrue\ntrue\nfalse\nTypeError: Cannot delete property 'length' of [object Array]\n",
+		},
+		{
+			name:   "assignments in classes",
+			input:  "function attempt(name, f) {\n  try {\n    f();\n    console.log(name, 'ok');\n  } catch (e) {\n    console.log(name, e.name + ': ' + e.message);\n  }\n}\nclass Point {\n  constructor() {\n    this.x = 1;\n    Object.freeze(this);\n  }\n  move() {\n    this.x = 2;\n  }\n  static create() {\n    created = true;\n  }\n}\nattempt('class method', () => new Point().move());\nattempt('class undeclared', () => Point.create());\nattempt('sloppy frozen', () => { Object.freeze({a: 1}).a = 2; });\nattempt('sloppy undeclared', () => { sloppyGlobal = 1; });\nconsole.log(sloppyGlobal);\nfunction strictFunction() {\n  'use strict';\n  Object.freeze({a: 1}).a = 2;\n}\nattempt('strict function', strictFunction);",
+			output: "class method TypeError: Cannot assign to read only property 'x' of object '#<Point>'\nclass undeclared ReferenceError: created is not defined\nsloppy frozen ok\nsloppy undeclared ok\n1\nstrict function TypeError: Cannot assign to read only property 'a' of object '#<Object>'\n",
+		},
+		{
+			name:   "computed member access",
+			input:  "const o = { a: 1, 3: 'three' }\nconst k = 'a'\no[k + 'b'] = 2\nconsole.log(o[k], o[1 + 2], o.ab, o['missing'])\nconst arr = [1, 2, 3]\narr[4] = 5\nconsole.log(arr[0], arr['1'], arr[-1], arr, arr.length)\nconst m = { greet(name) { return name + this.id }, id: '!' }\nconsole.log(m['gr' + 'eet']('hi'))\ndelete o[k]\nconsole.log(o)\ntry { null[k] } catch (e) { console.log(e.message) }",
+			output: "1 three 2 undefined\n1 2 undefined [ 1, 2, 3, <1 empty item>, 5 ] 5\nhi!\n{ '3': 'three', ab: 2 }\nCannot read properties of null (reading 'a')\n",
+		},
+		{
+			name:   "string indexing",
+			input:  "const s = 'héllo'\nconsole.log(s[0], s[1], s[9], s.length, 'abc'['length'], '😀'.length)\nconsole.log(new Object('ab'), Object.keys('xyz'), { ...'hi' })",
+			output: "h é undefined 5 3 2\n[String: 'ab'] [ '0', '1', '2' ] { '0': 'h', '1': 'i' }\n",
+		},
+		{
+			name:   "member updates",
+			input:  "const o = { n: 1, list: [10] }\no.n += 5\no['n'] *= 2\no.list[0]++\nconsole.log(o.n++, ++o.n, o.n--, o.list[0])\nconst calls = []\nconst key = { toString() { calls.push('key'); return 'n' } }\nfunction obj() { calls.push('obj'); return o }\nobj()[key] += 1\nconsole.log(calls.join(' '), o.n)\nclass A { get v() { return this._v || 1 } set v(x) { this._v = x } }\nclass B extends A { bump() { super.v += 10; super['v']++; return super.v } }\nconsole.log(new B().bump())",
+			output: "12 14 14 11\nobj key key 14\n12\n",
+		},
+		{
+			name:   "for-of loops",
+			input:  "for (const x of [1, 2, 3]) console.log(x);\nfor (const c of 'a😀b') console.log(c);\nconst fns = [];\nfor (let i of [1, 2]) fns.push(() => i);\nconsole.log(fns.map(f => f()));\nvar v;\nfor (v of [4, 5]) {}\nconsole.log(v);\nfor (const [k, val] of [['a', 1], ['b', 2]]) console.log(k, val);\nloop: for (const x of [1, 2, 3]) {\n  for (let j = 0; j < 3; j++) {\n    if (j === 1) continue loop;\n    if (x === 3) break loop;\n    console.log(x, j);\n  }\n}\nconsole.log([...[1, 2].entries()], [...[1, 2].keys()]);\nfunction f() {\n  for (const a of arguments) if (a > 1) return a;\n}\nconsole.log(f(1, 2, 3));",
+			output: "1\n2\n3\na\n😀\nb\n[ 1, 2 ]\n5\na 1\nb 2\n1 0\n2 0\n[ [ 0, 1 ], [ 1, 2 ] ] [ 0, 1 ]\n2\n",
+		},
+		{
+			name:   "iterator closing",
+			input:  "function iterable(n, log) {\n  return {\n    [Symbol.iterator]() {\n      let i = 0;\n      return {\n        next() { log.push('next'); return { value: i++, done: i > n }; },\n        return() { log.push('return'); return {}; },\n      };\n    },\n  };\n}\nlet log = [];\nfor (const x of iterable(5, log)) { if (x === 1) break; }\nconsole.log(log);\nlog = [];\nouter: for (const a of [1, 2]) {\n  for (const x of iterable(5, log)) { if (x === 1) continue outer; }\n}\nconsole.log(log);\nlog = [];\nfunction find() {\n  for (const x of iterable(5, log)) { if (x === 2) return x * 10; }\n}\nconsole.log(find(), log);\nlog = [];\ntry {\n  for (const x of iterable(5, log)) { throw new Error('boom ' + x); }\n} catch (e) {\n  console.log(e.message, log);\n}\nlog = [];\nfor (const x of iterable(2, log)) {}\nconsole.log(log);",
+			output: "[ 'next', 'next', 'return' ]\n[ 'next', 'next', 'return', 'next', 'next', 'return' ]\n20 [ 'next', 'next', 'next', 'return' ]\nboom 0 [ 'next', 'return' ]\n[ 'next', 'next', 'next' ]\n",
+		},
+		{
+			name:   "for-in loops",
+			input:  "const proto = { a: 1, b: 2, shadowed: 3 };\nconst obj = Object.create(proto);\nobj[2] = 'two';\nobj.z = 'z';\nobj[1] = 'one';\nObject.defineProperty(obj, 'shadowed', { value: 4, enumerable: false });\nobj[Symbol('s')] = 1;\nfor (const k in obj) console.log(k);\nconst del = { x: 1, y: 2, z: 3 };\nfor (const k in del) { console.log(k); delete del.y; }\nfor (var k in [5, 6]) console.log(typeof k, k);\nfor (const k in 'ab') console.log(k);\nfor (const k in null) console.log('never');",
+			output: "1\n2\nz\na\nb\nx\nz\nstring 0\nstring 1\n0\n1\n",
+		},
+		{
+			name:   "symbols",
+			input:  "const s = Symbol('foo');\nconsole.log(typeof s, s.toString(), s.description, s === Symbol('foo'));\nconsole.log(Symbol.for('k') === Symbol.for('k'), Symbol.keyFor(Symbol.for('k')), Symbol.keyFor(s));\nconst o = { [s]: 1, a: 2 };\nconsole.log(o, o[s], Object.keys(o), Object.getOwnPropertySymbols(o));\ntry { '' + s; } catch (e) { console.log(e.constructor.name, e.message); }\nconst money = { [Symbol.toPrimitive](hint) { return hint === 'number' ? 42 : 'forty-two ' + hint; } };\nconsole.log(+money, `${money}`, money + '');\nclass Even { static [Symbol.hasInstance](n) { return n % 2 === 0; } }\nconsole.log(2 instanceof Even, 3 instanceof Even);\nclass Tagged { get [Symbol.toStringTag]() { return 'Tagged'; } }\nconsole.log(new Tagged().toString(), [].values().toString());\nconst it = [1, 2][Symbol.iterator]();\nconsole.log(it.next(), it.next(), it.next(), it);\nconsole.log(Array.from({ [Symbol.iterator]() { let i = 0; return { next: () => ({ value: i, done: i++ > 2 }) }; } }));",
+			output: "symbol Symbol(foo) foo false\ntrue k undefined\n{ a: 2, [Symbol(foo)]: 1 } 1 [ 'a' ] [ Symbol(foo) ]\nTypeError Cannot convert a Symbol value to a string\n42 forty-two string forty-two default\ntrue false\n[object Tagged] [object Array Iterator]\n{ value: 1, done: false } { value: 2, done: false } { value: undefined, done: true } Object [Array Iterator] {}\n[ 0, 1, 2 ]\n",
+		},
+		{
+			name:   "generators",
+			input:  "function* count(n) {\n  for (let i = 0; i < n; i++) yield i;\n  return 'done';\n}\nconsole.log([...count(3)]);\nconst it = count(2);\nconsole.log(it.next(), it.next(), it.next(), it.next());\nfunction* echo() {\n  let received = [];\n  while (true) {\n    const x = yield received.length;\n    if (x === undefined) return received;\n    received.push(x);\n  }\n}\nconst e = echo();\nconsole.log(e.next('ignored'), e.next('a'), e.next('b'), e.next());\nconst obj = { *gen() { yield this.v; }, v: 7 };\nconsole.log([...obj.gen()]);\nclass K { static *range(a, b) { while (a < b) yield a++; } *[Symbol.iterator]() { yield* K.range(0, 3); } }\nconsole.log([...new K()], Array.from(K.range(1, 3)));\nconst [first, , third] = count(5);\nconsole.log(first, third);\nfunction* fib() { let [a, b] = [0, 1]; for (;;) { yield a; [a, b] = [b, a + b]; } }\nconst f = [];\nfor (const n of fib()) { if (n > 50) break; f.push(n); }\nconsole.log(f);\nfunction* args() { yield arguments.length; yield* arguments; }\nconsole.log([...args(4, 5)]);\nfunction* lazyDefault(x = console.log('params now')) { console.log('body now'); }\nconst l = lazyDefault();\nconsole.log('created');\nl.next();\nfunction* self() { try { me.next(); } catch (err) { console.log(err.constructor.name, err.message); } }\nconst me = self();\nme.next();\nconsole.log(count, count(1), { m: function* () {} }, Object.getPrototypeOf(count(1)) === count.prototype);\ntry { new count(); } catch (err) { console.log(err.constructor.name); }",
+			output: "[ 0, 1, 2 ]\n{ value: 0, done: false } { value: 1, done: false } { value: 'done', done: true } { value: undefined, done: true }\n{ value: 0, done: false } { value: 1, done: false } { value: 2, done: false } { value: [ 'a', 'b' ], done: true }\n[ 7 ]\n[ 0, 1, 2 ] [ 1, 2 ]\n0 2\n[\n  0, 1,  1,  2,  3,\n  5, 8, 13, 21, 34\n]\n[ 2, 4, 5 ]\nparams now\ncreated\nbody now\nTypeError Generator is already running\n[GeneratorFunction: count] Object [Generator] {} { m: [GeneratorFunction: m] } true\nTypeError\n",
+		},
+		{
+			name:   "generator return and throw",
+			input:  "function* guarded() {\n  try {\n    yield 1;\n    yield 2;\n  } finally {\n    console.log('cleanup');\n  }\n}\nconst g = guarded();\nconsole.log(g.next(), g.return(42), g.next());\nfor (const x of guarded()) { console.log(x); break; }\nconsole.log(guarded().return('unstarted'));\nfunction* catcher() {\n  while (true) {\n    try {\n      yield 'waiting';\n    } catch (err) {\n      console.log('caught', err);\n    }\n  }\n}\nconst c = catcher();\nc.next();\nconsole.log(c.throw('oops'));\ntry { guarded().throw(new Error('early')); } catch (err) { console.log(err.message); }\nfunction* overriding() {\n  try { yield 1; } finally { return 'finally wins'; }\n}\nconst o = overriding();\no.next();\nconsole.log(o.return('ignored'));\nfunction* yieldInFinally() {\n  try { yield 1; } finally { yield 'cleanup'; }\n}\nconst y = yieldInFinally();\nconsole.log(y.next(), y.return('r'), y.next());\nfunction* thrower() { yield 1; throw new Error('from gen'); }\nconst t = thrower();\nt.next();\ntry { t.next(); } catch (err) { console.log(err.message, t.next()); }",
+			output: "cleanup\n{ value: 1, done: false } { value: 42, done: true } { value: undefined, done: true }\n1\ncleanup\n{ value: 'unstarted', done: true }\ncaught oops\n{ value: 'waiting', done: false }\nearly\n{ value: 'finally wins', done: true }\n{ value: 1, done: false } { value: 'cleanup', done: false } { value: 'r', done: true }\nfrom gen { value: undefined, done: true }\n",
+		},
+		{
+			name:   "yield delegation",
+			input:  "function* inner() { const x = yield 'a'; console.log('inner got', x); return 'inner result'; }\nfunction* outer() { const r = yield* inner(); console.log('r', r); yield* [1, 2]; yield* 'hi'; }\nconst d = outer();\nconsole.log(d.next(), d.next('X'), d.next(), d.next(), d.next(), d.next(), d.next());\nfunction* guarded() { try { yield 1; } finally { console.log('inner cleanup'); } }\nfunction* delegateReturn() { try { yield* guarded(); } finally { console.log('outer cleanup'); } }\nconst dr = delegateReturn();\nconsole.log(dr.next(), dr.return('early'));\nconst noThrow = { [Symbol.iterator]() { return { next() { return { value: 1, done: false }; }, return() { console.log('closed'); return {}; } }; } };\nfunction* wrap() { yield* noThrow; }\nconst w = wrap();\nw.next();\ntry { w.throw(new Error('x')); } catch (err) { console.log(err.constructor.name, err.message); }\nfunction* catching() { try { yield* inner(); } catch (err) { console.log('caught', err); } }\nconst ct = catching();\nct.next();\nconsole.log(ct.throw('boom'));",
+			output: "inner got X\nr inner result\n{ value: 'a', done: false } { value: 1, done: false } { value: 2, done: false } { value: 'h', done: false } { value: 'i', done: false } { value: undefined, done: true } { value: undefined, done: true }\ninner cleanup\nouter cleanup\n{ value: 1, done: false } { value: 'early', done: true }\nclosed\nTypeError The iterator does not provide a 'throw' method.\ncaught boom\n{ value: undefined, done: true }\n",
+		},
+		{
+			name:   "promises",
+			input:  "console.log('start');\nconst p = new Promise((resolve) => { console.log('executor'); resolve(1); });\np.then((v) => { console.log('then', v); return v + 1; }).then((v) => console.log('chained', v));\nPromise.resolve().then(() => console.log('tick 1')).then(() => console.log('tick 2'));\nPromise.reject(new Error('boom')).catch((e) => console.log('caught', e.message));\nPromise.resolve(5).finally(() => console.log('finally')).then((v) => console.log('after finally', v));\nPromise.all([1, Promise.resolve(2), new Promise((r) => r(3))]).then((vs) => console.log('all', vs));\nPromise.all([1, Promise.reject('nope')]).catch((e) => console.log('all rejected', e));\nPromise.race([new Promise(() => {}), Promise.resolve('fast')]).then((v) => console.log('race', v));\nnew Promise((resolve) => resolve(Promise.resolve('nested'))).then((v) => console.log(v));\nconst cyc = Promise.resolve().then(() => cyc);\ncyc.catch((e) => console.log(e.constructor.name, e.message));\nnew Promise((_, reject) => { reject('first'); throw new Error('ignored'); }).catch((e) => console.log('reject once', e));\ntry { Promise(); } catch (e) { console.log(e.message); }\ntry { new Promise(1); } catch (e) { console.log(e.message); }\nclass MyPromise extends Promise {}\nconst mp = MyPromise.resolve(1);\nconsole.log(mp instanceof MyPromise, mp.then(() => {}) instanceof MyPromise, mp);\nconsole.log('sync end', p, new Promise(() => {}), Promise.resolve(p) === p);",
+			output: "start\nexecutor\nPromise constructor cannot be invoked without 'new'\nPromise resolver 1 is not a function\ntrue true MyPromise [Promise] { 1 }\nsync end Promise { 1 } Promise { <pending> } true\nthen 1\ntick 1\ncaught boom\nfinally\nreject once first\nchained 2\ntick 2\nall [ 1, 2, 3 ]\nall rejected nope\nrace fast\nTypeError Chaining cycle detected for promise #<Promise>\nnested\nafter finally 5\n",
+		},
+		{
+			name:   "async functions",
+			input:  "async function add(a, b) { return a + b; }\nasync function main() {\n  console.log('main start');\n  const x = await add(1, 2);\n  console.log('x', x);\n  try { await Promise.reject(new TypeError('bad')); } catch (e) { console.log('caught', e.name, e.message); }\n  console.log(await { then(resolve) { resolve('thenable'); } });\n  for (const v of [1, 2]) { console.log('loop', await v); }\n  return 'main done';\n}\nmain().then((v) => console.log(v));\nconsole.log('sync end');\nconst obj = { async m() { return this.v; }, v: 'method' };\nobj.m().then(console.log);\nclass C { static async s() { throw new Error('static'); } }\nC.s().catch((e) => console.log('static caught', e.message));\nasync function order(name) { console.log(name, 1); await null; console.log(name, 2); await null; console.log(name, 3); }\norder('a'); order('b');\nasync function params(x = (() => { throw new Error('param'); })()) {}\nparams().catch((e) => console.log('param rejected', e.message));\nasync function withFinally() {\n  try { await Promise.reject(new Error('inner')); } finally { await null; console.log('finally'); }\n}\nwithFinally().catch((e) => console.log('rejected with', e.message));\nasync function fact(n) { return n <= 1 ? 1 : n * await fact(n - 1); }\nfact(5).then((v) => console.log('fact', v));\n(async () => console.log('arrow', await Promise.all([1, 2].map(async (x) => x * 10))))();\nconsole.log(add, typeof add.prototype);",
+			output: "main start\nsync end\na 1\nb 1\n[AsyncFunction: add] undefined\nx 3\nmethod\nstatic caught static\na 2\nb 2\nparam rejected param\ncaught TypeError bad\na 3\nb 3\nfinally\narrow [ 10, 20 ]\nrejected with inner\nthenable\nloop 1\nfact 120\nloop 2\nmain done\n",
+		},
+		{
+			name:   "unhandled rejection",
+			input:  "async function f() { throw new TypeError('lost') }\nf()\nconsole.log('after')",
+			output: "after\nUncaught TypeError: lost",
+			err:    true,
+		},
+		{
+			name:   "unhandled rejection of a value",
+			input:  "Promise.reject(42)",
+			output: "The promise rejected with the reason \"42\".",
+			err:    true,
+		},
+		{
+			name:   "rejection handled by a later job",
+			input:  "const p = Promise.reject(1)\nPromise.resolve().then(() => p.catch((e) => console.log('handled', e)))",
+			output: "handled 1\n",
+		},
+		{
+			name:   "switch fall-through",
+			input:  "function describe(x) {\n  const out = [];\n  switch (x) {\n    case 1:\n      out.push('one');\n    case 2:\n      out.push('two');\n      break;\n    case 3:\n    case 4:\n      out.push('three or four');\n  }\n  return out.join(',');\n}\nconsole.log(describe(1), describe(2), describe(3), describe(4), describe('1'));\nswitch (NaN) { case NaN: console.log('NaN matched'); break; default: console.log('NaN never equals'); }\nswitch (0) { case -0: console.log('zero equals negative zero'); }\nconst obj = {};\nswitch (obj) { case {}: console.log('never'); break; case obj: console.log('same object'); }\nfunction ret(x) { switch (x) { case 'a': return 'A'; default: return 'other'; } }\nconsole.log(ret('a'), ret('b'));",
+			output: "one,two two three or four three or four \nNaN never equals\nzero equals negative zero\nsame object\nA other\n",
+		},
+		{
+			name:   "switch default in the middle",
+			input:  "function tested(x) {\n  const log = [];\n  const t = (v) => { log.push('test ' + v); return v; };\n  switch (x) {\n    case t(1): log.push('body 1');\n    default: log.push('body default');\n    case t(2): log.push('body 2'); break;\n    case t(3): log.push('body 3');\n  }\n  return log.join(', ');\n}\nconsole.log(tested(1));\nconsole.log(tested(2));\nconsole.log(tested(3));\nconsole.log(tested(9));\nswitch ('a') { default: console.log('only default'); }\nswitch (2) { case 1: let z = 1; break; case 2: try { z; } catch (e) { console.log(e.constructor.name, e.message); } }\nswitch (3) { case 3: console.log(hoisted()); break; case 4: function hoisted() { return 'hoisted fn'; } }",
+			output: "test 1, body 1, body default, body 2\ntest 1, test 2, body 2\ntest 1, test 2, test 3, body 3\ntest 1, test 2, test 3, body default, body 2\nonly default\nReferenceError Cannot access 'z' before initialization\nhoisted fn\n",
+		},
+		{
+			name:   "switch break and continue",
+			input:  "outer: for (let i = 0; i < 4; i++) {\n  switch (i) {\n    case 0: continue;\n    case 1: console.log('one'); break;\n    case 2: continue outer;\n    case 3: break outer;\n  }\n  console.log('after switch', i);\n}\nlabel: switch (1) { case 1: console.log('labeled'); break label; case 2: console.log('never'); }\nfor (const v of [1, 2, 3]) {\n  switch (v) { case 2: continue; default: break; }\n  console.log('of', v);\n}\nfor (let i = 0; i < 3; i++) {\n  try {\n    switch (i) { case 0: continue; case 1: break; default: console.log('try default'); }\n    console.log('in try', i);\n  } finally {\n    console.log('finally', i);\n  }\n}",
+			output: "one\nafter switch 1\nlabeled\nof 1\nof 3\nfinally 0\nin try 1\nfinally 1\ntry default\nin try 2\nfinally 2\n",
+		},
+		{
+			name:   "maps",
+			input:  "const m = new Map([['a', 1]]);\nm.set(NaN, 'nan').set(-0, 'zero').set('0', 'string zero');\nconsole.log(m.get(NaN), m.get(0), m.has(+0), m.get('0'), m.size, Object.is([...m.keys()][2], -0));\nconst o = {};\nm.set(o, 'obj');\nconsole.log(m.get({}), m.get(o), m.delete(o), m.delete(o), m.size);\nfor (const [k, v] of m) console.log(k, v);\nm.forEach(function (v, k, map) { console.log(k, v, map === m, this.tag); }, { tag: 'this' });\nclass LoggingMap extends Map {\n  set(k, v) { console.log('set', k, v); return super.set(k, v * 10); }\n}\nconst lm = new LoggingMap([['x', 1], ['y', 2]]);\nconsole.log(lm.get('y'), lm instanceof Map, `${lm}`);\ntry { Map(); } catch (e) { console.log(e.message); }\ntry { new Map([1]); } catch (e) { console.log(e.message); }\ntry { ({ get: Map.prototype.get }).get(1); } catch (e) { console.log(e.message); }\ntry { m.forEach('x'); } catch (e) { console.log(e.message); }",
+			output: "nan zero true string zero 4 false\nundefined obj true false 4\na 1\nNaN nan\n0 zero\n0 string zero\na 1 true this\nNaN nan true this\n0 zero true this\n0 string zero true this\nset x 1\nset y 2\n20 true [object Map]\nConstructor Map requires 'new'\nIterator value 1 is not an entry object\nMethod Map.prototype.get called on incompatible receiver #<Object>\nstring \"x\" is not a function\n",
+		},
+		{
+			name:   "sets",
+			input:  "const s = new Set([1, 2, 2, 3, 1, NaN, NaN, -0, 0]);\nconsole.log([...s], s.has(0), s.size, Array.from(new Set('hello')).join(''));\nconsole.log(s.delete(2), s.delete(2), s.add(4) === s, [...s.entries()][0]);\ns.clear();\nconsole.log(s.size, Set.prototype.keys === Set.prototype.values, Set.prototype[Symbol.iterator] === Set.prototype.values);\nfunction* gen() { try { yield 1; yield 2; } finally { console.log('closed'); } }\nclass ThrowingSet extends Set { add(v) { if (v === 2) throw new Error('no 2'); return super.add(v); } }\ntry { new ThrowingSet(gen()); } catch (e) { console.log(e.message); }",
+			output: "[ 1, 2, 3, NaN, 0 ] true 5 helo\ntrue false true [ 1, 1 ]\n0 true true\nclosed\nno 2\n",
+		},
+		{
+			name:   "collections modified during iteration",
+			input:  "const d = new Set();\nfor (let i = 0; i < 20; i++) d.add(i);\nconst seen = [];\nfor (const v of d) {\n  seen.push(v);\n  if (v % 2 === 0) for (let j = 0; j < 20; j++) if (j !== v && j % 3 === 0) d.delete(j);\n  if (v === 10) d.add(100);\n}\nconsole.log(seen.join(','), d.size);\nconst m = new Map([['a', 1], ['b', 2]]);\nm.forEach((v, k, map) => {\n  console.log(k, v);\n  if (k === 'a') map.set('c', 3);\n  if (k === 'c') { map.clear(); map.set('d', 4); }\n});\nconst it = m.entries();\nm.set('e', 5);\nconsole.log(it.next(), it.next(), it.next());\nm.set('f', 6);\nconsole.log(it.next());\nconst big = new Map();\nfor (let i = 0; i < 50; i++) big.set(i, i);\nconst keys = big.keys();\nfor (let i = 0; i < 10; i++) keys.next();\nfor (let i = 0; i < 45; i++) if (i !== 10 && i !== 30) big.delete(i);\nconsole.log(keys.next().value, keys.next().value, keys.next().value, big.size);",
+			output: "0,1,2,4,5,7,8,10,11,13,14,16,17,19,100 14\na 1\nb 2\nc 3\nd 4\n{ value: [ 'd', 4 ], done: false } { value: [ 'e', 5 ], done: false } { value: undefined, done: true }\n{ value: undefined, done: true }\n10 30 45 7\n",
+		},
+		{
+			name:   "weak collections",
+			input:  "const key = {};\nconst ws = new WeakSet([key]);\nconsole.log(ws.has(key), ws.has({}), ws.delete(key), ws.has(key));\nconst f = function () {};\nconst wm = new WeakMap([[key, 'object'], [f, 'function']]);\nconst sym = Symbol('s');\nwm.set(sym, 'symbol');\nconsole.log(wm.get(key), wm.get(f), wm.get(sym), wm.has([]), wm.get(1));\nfor (let i = 0; i < 1000; i++) wm.set({ i }, i);\nconsole.log(wm.get(key), wm.delete(key), wm.has(key));\ntry { wm.set(1, 1); } catch (e) { console.log(e.message); }\ntry { wm.set(Symbol.for('registered'), 1); } catch (e) { console.log(e.message); }\ntry { ws.add('x'); } catch (e) { console.log(e.message); }\ntry { WeakSet(); } catch (e) { console.log(e.message); }",
+			output: "true false true false\nobject function symbol false undefined\nobject true false\nInvalid value used as weak map key\nInvalid value used as weak map key\nInvalid value used in weak set\nConstructor WeakSet requires 'new'\n",
+		},
+		{
+			name:   "inspect collections",
+			input:  "const m = new Map([['a', 1], [{ x: 1 }, [1, 2]], [NaN, 'nan']]);\nconsole.log(m, new Set([1, 'two', { three: 3 }]), new Map(), new Set());\nconsole.log(new WeakMap(), new WeakSet(), { m: new Map([[1, { a: [1, 2] }]]) });\nconsole.log(m.entries(), m.keys(), new Set([1]).values(), new Set([1]).entries());\nconst it = m.values(); it.next(); it.next(); it.next();\nconsole.log(it, [1].values());\nclass M extends Map {}\nconst x = new M([[1, 2]]); x.foo = 1;\nconsole.log(x);\nconst big = new Set(); for (let i = 0; i < 102; i++) big.add(i);\nconsole.log(big);",
+			output: "Map(3) { 'a' => 1, { x: 1 } => [ 1, 2 ], NaN => 'nan' } Set(3) { 1, 'two', { three: 3 } } Map(0) {} Set(0) {}\nWeakMap { <items unknown> } WeakSet { <items unknown> } { m: Map(1) { 1 => { a: [Array] } } }\n[Map Entries] { [ 'a', 1 ], [ { x: 1 }, [ 1, 2 ] ], [ NaN, 'nan' ] } [Map Iterator] { 'a', { x: 1 }, NaN } [Set Iterator] { 1 } [Set Entries] { [ 1, 1 ] }\n[Map Iterator] {  } Object [Array Iterator] {}\nM(1) [Map] { 1 => 2, foo: 1 }\nSet(102) {\n  0,\n  1,\n  2,\n  3,\n  4,\n  5,\n  6,\n  7,\n  8,\n  9,\n  10,\n  11,\n  12,\n  13,\n  14,\n  15,\n  16,\n  17,\n  18,\n  19,\n  20,\n  21,\n  22,\n  23,\n  24,\n  25,\n  26,\n  27,\n  28,\n  29,\n  30,\n  31,\n  32,\n  33,\n  34,\n  35,\n  36,\n  37,\n  38,\n  39,\n  40,\n  41,\n  42,\n  43,\n  44,\n  45,\n  46,\n  47,\n  48,\n  49,\n  50,\n  51,\n  52,\n  53,\n  54,\n  55,\n  56,\n  57,\n  58,\n  59,\n  60,\n  61,\n  62,\n  63,\n  64,\n  65,\n  66,\n  67,\n  68,\n  69,\n  70,\n  71,\n  72,\n  73,\n  74,\n  75,\n  76,\n  77,\n  78,\n  79,\n  80,\n  81,\n  82,\n  83,\n  84,\n  85,\n  86,\n  87,\n  88,\n  89,\n  90,\n  91,\n  92,\n  93,\n  94,\n  95,\n  96,\n  97,\n  98,\n  99,\n  ... 2 more items\n}\n",
+		},
+		{
+			name:   "json parse with a reviver",
+			input:  "const text = '{\"a\":1,\"b\":[1,2,{\"c\":3}],\"d\":{\"e\":\"f\"}}';\nconsole.log(JSON.parse(text, function (k, v) {\n  console.log(JSON.stringify(k), JSON.stringify(v), Array.isArray(this));\n  if (k === 'a') return undefined;\n  return typeof v === 'number' ? v + 1 : v;\n}));\nconsole.log(JSON.parse('[1,2,3]', (k, v) => (k === '1' ? undefined : v)));\nconsole.log(JSON.parse('{\"__proto__\":{\"x\":1}}').__proto__, JSON.parse(' \"str\" '), JSON.parse('true'), JSON.parse('null'), JSON.parse(1), JSON.parse('{\"a\":1}', null));\nconst date = JSON.parse('{\"when\":\"2020-01-01\"}', (k, v) => (k === 'when' ? { revived: v } : v));\nconsole.log(date);\ntry { JSON.parse('{\"a\":'); } catch (e) { console.log(e instanceof SyntaxError, e.message); }",
+			output: "\"a\" 1 false\n\"0\" 1 true\n\"1\" 2 true\n\"c\" 3 false\n\"2\" {\"c\":4} true\n\"b\" [2,3,{\"c\":4}] false\n\"e\" \"f\" false\n\"d\" {\"e\":\"f\"} false\n\"\" {\"b\":[2,3,{\"c\":4}],\"d\":{\"e\":\"f\"}} false\n{ b: [ 2, 3, { c: 4 } ], d: { e: 'f' } }\n[ 1, <1 empty item>, 3 ]\n{ x: 1 } str true null 1 { a: 1 }\n{ when: { revived: '2020-01-01' } }\ntrue Unexpected end of JSON input\n",
+		},
+		{
+			name:   "json parse errors",
+			input:  "for (const s of ['', ' ', '{', '{\"a\"', '{\"a\":', '{\"a\":1', '{\"a\":1,', '{a:1}', '[1,]', '[1', '[1 2]', '{\"a\" 1}', '{\"a\":1 \"b\":2}', 'xyz', 'tru', 'nul', '1 2', '-', '1.', '1e', '1e+', '01', '\"abc', '\"a\\tb\"', '\"\\\\x\"', '\"\\\\u12g4\"', '[1,2,3] x', '{\"a\":1}}', \"'a'\", 'undefined', '{\"a\":1,}', 'NaN', '\"\\u2028\"', '[-]', '.5', '+1', '{\"a\":[1,{\"b\":}]}', 'a very long invalid json text that goes on and on and on']) {\n  try { console.log(JSON.stringify(JSON.parse(s))); } catch (e) { console.log(e.name + ': ' + e.message); }\n}",
+			output: "SyntaxError: Unexpected end of JSON input\nSyntaxError: Unexpected end of JSON input\nSyntaxError: Expected property name or '}' in JSON at position 1\nSyntaxError: Expected ':' after property name in JSON at position 4\nSyntaxError: Unexpected end of JSON input\nSyntaxError: Expected ',' or '}' after property value in JSON at position 6\nSyntaxError: Expected double-quoted property name in JSON at position 7\nSyntaxError: Expected property name or '}' in JSON at position 1\nSyntaxError: Unexpected token ']', \"[1,]\" is not valid JSON\nSyntaxError: Expected ',' or ']' after array element in JSON at position 2\nSyntaxError: Expected ',' or ']' after array element in JSON at position 3\nSyntaxError: Expected ':' after property name in JSON at position 5\nSyntaxError: Expected ',' or '}' after property value in JSON at position 7\nSyntaxError: Unexpected token 'x', \"xyz\" is not valid JSON\nSyntaxError: Unexpected end of JSON input\nSyntaxError: Unexpected end of JSON input\nSyntaxError: Unexpected non-whitespace character after JSON at position 2\nSyntaxError: No number after minus sign in JSON at position 1\nSyntaxError: Unterminated fractional number in JSON at position 2\nSyntaxError: Exponent part is missing a number in JSON at position 2\nSyntaxError: Exponent part is missing a number in JSON at position 3\nSyntaxError: Unexpected number in JSON at position 1\nSyntaxError: Unterminated string in JSON at position 4\nSyntaxError: Bad control character in string literal in JSON at position 2\nSyntaxError: Bad escaped character in JSON at position 2\nSyntaxError: Bad Unicode escape in JSON at position 5\nSyntaxError: Unexpected non-whitespace character after JSON at position 8\nSyntaxError: Unexpected non-whitespace character after JSON at position 7\nSyntaxError: Unexpected token ''', \"'a'\" is not valid JSON\nSyntaxError: \"undefined\" is not valid JSON\nSyntaxError: Expected double-quoted property name in JSON at position 7\nSyntaxError: \"NaN\" is not valid JSON\n\" \"\nSyntaxError: No number after minus sign in JSON at position 2\nSyntaxError: Unexpected token '.', \".5\" is not valid JSON\nSyntaxError: Unexpected token '+', \"+1\" is not valid JSON\nSyntaxError: Unexpected token '}', \"{\"a\":[1,{\"b\":}]}\" is not valid JSON\nSyntaxError: Unexpected token 'a', \"a very lon\"... is not valid JSON\n",
+		},
+		{
+			name:   "json parse error context",
+			input:  "const x20 = 'xxxxxxxxxxxxxxxxxxxx';\nfor (const s of [x20, x20 + 'x', x20 + 'xx', '[[[[[[[[[[[[[[[x]]]]]]]]]]]]]]]', '[1,1,1,1,1,1,1,1,1,1,1,1,x]', '\"aaaaaaaaaaaaaaaaaaaaaaaaa\"x', 'tr\"', 'tr ue', '\"\\\\', '\"\\\\u12', '[1,\\n2,\\n x]', '\"😀\"x', '[1,\"😀\",x]', '{\"a\":1,\"a\":2,\"__proto__\":3}', '  [ 1 , { \"a\" : [ ] } ]  ', '1e5', '-0', '1E-2', '1234567890123456789', '\"\\\\ud83d\\\\ude00\\\\n\\\\u00e9\\\\/\"', '{}x', '[]]']) {\n  try { const v = JSON.parse(s); console.log(JSON.stringify(v), Object.is(v, -0)); } catch (e) { console.log(e.name + ': ' + e.message); }\n}",
+			output: "SyntaxError: Unexpected token 'x', \"xxxxxxxxxxxxxxxxxxxx\" is not valid JSON\nSyntaxError: Unexpected token 'x', \"xxxxxxxxxx\"... is not valid JSON\nSyntaxError: Unexpected token 'x', \"xxxxxxxxxx\"... is not valid JSON\nSyntaxError: Unexpected token 'x', ...\"[[[[[[[[[[x]]]]]]]]]\"... is not valid JSON\nSyntaxError: Unexpected token 'x', ...\"1,1,1,1,1,x]\" is not valid JSON\nSyntaxError: Unexpected non-whitespace character after JSON at position 27\nSyntaxError: Unexpected string in JSON at position 2\nSyntaxError: Unexpected token ' ', \"tr ue\" is not valid JSON\nSyntaxError: Unexpected end of JSON input\nSyntaxError: Bad Unicode escape in JSON at position 5\nSyntaxError: Unexpected token 'x', \"[1,\n2,\n x]\" is not valid JSON\nSyntaxError: Unexpected non-whitespace character after JSON at position 4\nSyntaxError: Unexpected token 'x', \"[1,\"😀\",x]\" is not valid JSON\n{\"a\":2,\"__proto__\":3} false\n[1,{\"a\":[]}] false\n100000 false\n0 true\n0.01 false\n1234567890123456800 false\n\"😀\\né/\" false\nSyntaxError: Unexpected non-whitespace character after JSON at position 2\nSyntaxError: Unexpected non-whitespace character after JSON at position 2\n",
+		},
+		{
+			name:   "json stringify",
+			input:  "console.log(JSON.stringify({ a: 1, b: 'two', c: [1, null, undefined, function () {}, Symbol('s')], d: undefined, e: () => 1, f: true, g: NaN, h: -Infinity, i: -0, j: 1e20, k: 0.1 }));\nconsole.log(JSON.stringify('q\"\\\\\\b\\f\\n\\r\\t\\u0001\\u001f\\u007f é 😀 \\u2028'));\nconsole.log(JSON.stringify(undefined), JSON.stringify(function () {}), JSON.stringify(Symbol('x')), JSON.stringify(null), JSON.stringify([]), JSON.stringify({}));\nconsole.log(JSON.stringify({ a: [1, { b: 2, c: [] }, {}], d: 'x' }, null, 2));\nconsole.log(JSON.stringify([1, [2, [3]]], null, '--'));\nconsole.log(JSON.stringify({ a: 1 }, null, 'abcdefghijklmnop'), JSON.stringify({ a: 1 }, null, 20) === JSON.stringify({ a: 1 }, null, 10), JSON.stringify({ a: 1 }, null, 0), JSON.stringify([1], null, -3), JSON.stringify({ a: 1 }, null, 3.7));\nconsole.log(JSON.stringify({ a: 1, b: 2, c: { a: 3, d: 4 }, 1: 'one' }, ['a', 'c', 1, 'a']));\nconsole.log(JSON.stringify({ a: 1, b: 'x', c: { d: 2 } }, function (k, v) {\n  console.log('replacer', JSON.stringify(k), typeof v, this === undefined);\n  return typeof v === 'number' ? v * 10 : v;\n}));\nconsole.log(JSON.stringify({ date: { toJSON(key) { return 'toJSON:' + key; } }, arr: [{ toJSON(k) { return k; } }] }));\nclass Point { constructor(x, y) { this.x = x; this.y = y; } toJSON() { return [this.x, this.y]; } }\nconsole.log(JSON.stringify({ p: new Point(1, 2) }), JSON.stringify(new Point(3, 4)));\nconsole.log(JSON.stringify(Object(1)), JSON.stringify(Object('s')), JSON.stringify(Object(false)), JSON.stringify([Object(2)], null, Object(1)));\nconst o = Object.create({ inherited: 1 }); o.own = 2; Object.defineProperty(o, 'hidden', { value: 3 }); o[Symbol('s')] = 4;\nconsole.log(JSON.stringify(o), JSON.stringify(new Map([[1, 2]])), JSON.stringify(new Set([1])));\nconsole.log(JSON.stringify({ a: 1 }, () => undefined), JSON.stringify({ a: { b: 1 } }, (k, v) => k === 'b' ? undefined : v));\nconst shared = { v: 1 }; console.log(JSON.stringify([shared, shared, { shared }]));\nconsole.log(JSON.stringify({ '': 1, 'a\"b': 2 }), JSON);",
+			output: "{\"a\":1,\"b\":\"two\",\"c\":[1,null,null,null,null],\"f\":true,\"g\":null,\"h\":null,\"i\":0,\"j\":100000000000000000000,\"k\":0.1}\n\"q\\\"\\\\\\b\\f\\n\\r\\t\\u0001\\u001f é 😀  \"\nundefined undefined undefined null [] {}\n{\n  \"a\": [\n    1,\n    {\n      \"b\": 2,\n      \"c\": []\n    },\n    {}\n  ],\n  \"d\": \"x\"\n}\n[\n--1,\n--[\n----2,\n----[\n------3\n----]\n--]\n]\n{\nabcdefghij\"a\": 1\n} true {\"a\":1} [1] {\n   \"a\": 1\n}\n{\"a\":1,\"c\":{\"a\":3},\"1\":\"one\"}\nreplacer \"\" object false\nreplacer \"a\" number false\nreplacer \"b\" string false\nreplacer \"c\" object false\nreplacer \"d\" number false\n{\"a\":10,\"b\":\"x\",\"c\":{\"d\":20}}\n{\"date\":\"toJSON:date\",\"arr\":[\"0\"]}\n{\"p\":[1,2]} [3,4]\n1 \"s\" false [\n 2\n]\n{\"own\":2} {} {}\nundefined {\"a\":{}}\n[{\"v\":1},{\"v\":1},{\"shared\":{\"v\":1}}]\n{\"\":1,\"a\\\"b\":2} Object [JSON] {}\n",
+		},
+		{
+			name:   "json circular structure",
+			input:  "const a = { x: 1 }; a.self = a;\ntry { JSON.stringify(a); } catch (e) { console.log(e.name + ': ' + e.message); }\nconst b = { list: [1, { inner: {} }] }; b.list[1].inner.back = b.list;\ntry { JSON.stringify(b); } catch (e) { console.log(e.name + ': ' + e.message); }\nclass Foo { constructor() { this.me = this; } }\ntry { JSON.stringify([new Foo()]); } catch (e) { console.log(e.name + ': ' + e.message); }\nconst n = Object.create(null); n.a = { b: n };\ntry { JSON.stringify(n); } catch (e) { console.log(e.name + ': ' + e.message); }\nconst arr = []; arr.push(arr);\ntry { JSON.stringify(arr); } catch (e) { console.log(e.name + ': ' + e.message); }\nconst deep = { a: { b: { c: { d: {} } } } }; deep.a.b.c.d.e = deep.a;\ntry { JSON.stringify(deep); } catch (e) { console.log(e.name + ': ' + e.message); }\nconst l = [[[[[]]]]]; l[0][0][0][0].push(l);\ntry { JSON.stringify(l); } catch (e) { console.log(e.name + ': ' + e.message); }\nconst sym = {}; sym[Symbol('x')] = sym; sym.y = { z: sym };\ntry { JSON.stringify(sym); } catch (e) { console.log(e.name + ': ' + e.message); }",
+			output: "TypeError: Converting circular structure to JSON\n    --> starting at object with constructor 'Object'\n    --- property 'self' closes the circle\nTypeError: Converting circular structure to JSON\n    --> starting at object with constructor 'Array'\n    |     index 1 -> object with constructor 'Object'\n    |     property 'inner' -> object with constructor 'Object'\n    --- property 'back' closes the circle\nTypeError: Converting circular structure to JSON\n    --> starting at object with constructor 'Foo'\n    --- property 'me' closes the circle\nTypeError: Converting circular structure to JSON\n    --> starting at object with constructor 'Object'\n    |     property 'a' -> object with constructor 'Object'\n    --- property 'b' closes the circle\nTypeError: Converting circular structure to JSON\n    --> starting at object with constructor 'Array'\n    --- index 0 closes the circle\nTypeError: Converting circular structure to JSON\n    --> starting at object with constructor 'Object'\n    |     property 'b' -> object with constructor 'Object'\n    |     property 'c' -> object with constructor 'Object'\n    |     property 'd' -> object with constructor 'Object'\n    --- property 'e' closes the circle\nTypeError: Converting circular structure to JSON\n    --> starting at object with constructor 'Array'\n    |     index 0 -> object with constructor 'Array'\n    |     index 0 -> object with constructor 'Array'\n    |     ...\n    |     index 0 -> object with constructor 'Array'\n    --- index 0 closes the circle\nTypeError: Converting circular structure to JSON\n    --> starting at object with constructor 'Object'\n    |     property 'y' -> object with constructor 'Object'\n    --- property 'z' closes the circle\n",
+		},
+		{
+			name:   "math rounding and extremes",
+			input:  "console.log(Math.round(-0.5), Math.round(2.5), Math.round(-2.5), Math.round(-0))\nconsole.log(Math.max(), Math.min(), Math.max(-0, 0), Math.min(0, -0), Math.max(1, NaN, 3), Math.min('2', 1))\nconsole.log(Math.sign(-3), Math.sign(-0), Math.sign(NaN), Math.trunc(-4.7), Math.ceil(-0.5), Math.floor(-0.5))\nconsole.log(Math.hypot(), Math.hypot(3, 4), Math.hypot(NaN, Infinity), Math.hypot(1e200, 1e200) / 1e200, Math.hypot(-0))\nconsole.log(Math.clz32(1), Math.clz32(0), Math.imul(0xffffffff, 5), Math.imul(3, 4), Math.fround(5.5), Math.fround(5.05))",
+			output: "-0 3 -2 -0\n-Infinity Infinity 0 -0 NaN 1\n-1 -0 NaN -4 -0 -1\n0 5 Infinity 1.4142135623730951 0\n31 32 -5 12 5.5 5.050000190734863\n",
+		},
+		{
+			name:   "math functions and constants",
+			input:  "console.log(Math.sin(1), Math.cos(1e10), Math.tan(-2), Math.atan2(1, -1), Math.exp(1), Math.log(10), Math.log2(8), Math.log10(1000))\nconsole.log(Math.cbrt(27), Math.sinh(1), Math.cosh(1), Math.tanh(0.5), Math.asinh(1), Math.acosh(2), Math.atanh(0.5), Math.expm1(1), Math.log1p(1))\nconsole.log(Math.pow(2, 0.5), 2 ** 10, Math.pow(1, NaN), Math.sqrt(2), Math.abs(-7), Math.asin(1), Math.acos(-1), Math.atan(1))\nconsole.log(Math.PI, Math.E, Math.LN2, Math.LN10, Math.LOG2E, Math.LOG10E, Math.SQRT2, Math.SQRT1_2)\nvar r = Math.random()\nconsole.log(r >= 0 && r < 1, Math.max.length, Math.random.length, Math.hypot.length, Math[Symbol.toStringTag])",
+			output: "0.8414709848078965 0.873119622676856 2.185039863261519 2.356194490192345 2.718281828459045 2.302585092994046 3 3\n3 1.1752011936438014 1.5430806348152437 0.46211715726000974 0.881373587019543 1.3169578969248166 0.5493061443340548 1.718281828459045 0.6931471805599453\n1.4142135623730951 1024 NaN 1.4142135623730951 7 1.5707963267948966 3.141592653589793 0.7853981633974483\n3.141592653589793 2.718281828459045 0.6931471805599453 2.302585092994046 1.4426950408889634 0.4342944819032518 1.4142135623730951 0.7071067811865476\ntrue 2 0 2 Math\n",
+		},
+		{
+			name:   "number to string",
+			input:  "console.log(1e21, -0, NaN, 0.000001, 1e-7, 123456789012345680000, 0.1 + 0.2, 5e-324, 1.7976931348623157e308, 1e400, -1e400)\nconsole.log([1e21, -0, 0.1, 100, 2 ** 53].join(), `${1e-7}`, 1e21 + '', -0 + '')\nconsole.log(Math.round(0.49999999999999994), Math.expm1(1e-10), Math.log1p(1e-10), 0.1, 1e-7, 5e-324, 0x10, 1e21, 1e400, 2e-7 * 3)\nvar o = { 1e21: 'a', 0.000001: 'b', 1.5: 'c' }\nconsole.log(Object.keys(o))\nconsole.log(JSON.stringify({ j: 1e21, k: 1e-7 }), JSON.parse('123456789012345678901234567890'), Math.hypot(1e200, 1e200))",
+			output: "1e+21 -0 NaN 0.000001 1e-7 123456789012345680000 0.30000000000000004 5e-324 1.7976931348623157e+308 Infinity -Infinity\n1e+21,0,0.1,100,9007199254740992 1e-7 1e+21 0\n0 1.00000000005e-10 9.999999999500001e-11 0.1 1e-7 5e-324 16 1e+21 Infinity 6e-7\n[ '1e+21', '0.000001', '1.5' ]\n{\"j\":1e+21,\"k\":1e-7} 1.2345678901234568e+29 1.414213562373095e+200\n",
+		},
+		{
+			name:   "number to string with a radix",
+			input:  "console.log((255).toString(16), (255).toString(2), (-255).toString(36), (0.5).toString(2), (0.1).toString(3), (3.14159).toString(16), Math.PI.toString(7))\nconsole.log((1e21).toString(16), (2 ** 60).toString(2), (-0).toString(2), NaN.toString(16), (-Infinity).toString(8), (1e-7).toString(36), (123.456).toString(36))",
+			output: "ff 11111111 -73 0.1 0.0022002200220022002200220022002201 3.243f3e0370cdc 3.066365143203613411\n3635c9adc5dea00000 1000000000000000000000000000000000000000000000000000000000000 0 NaN -Infinity 0.000061oezo085tl 3f.gez4w97ry\n",
+		},
+		{
+			name:   "number formatting",
+			input:  "console.log((1.005).toFixed(2), (1.25).toFixed(1), (2.5).toFixed(0), (-2.5).toFixed(0), (0.5).toFixed(0), (-0.0001).toFixed(2), (0).toFixed(3), (1e21).toFixed(2), (123.456).toFixed(10), (0.000001).toFixed(7))\nconsole.log((1234.5678).toFixed(), (1e20).toFixed(2), (-1.5e-10).toFixed(20), (0.1).toFixed(20), (5e-324).toFixed(100).length)\nconsole.log((123.456).toExponential(), (123.456).toExponential(2), (0).toExponential(), (0).toExponential(2), (-1e-7).toExponential(3), (1.5).toExponential(0), (2.5).toExponential(0), (9.99).toExponential(1))\nconsole.log((123.456).toPrecision(4), (0.000123).toPrecision(2), (0.00000123).toPrecision(2), (123456).toPrecision(2), (1.25).toPrecision(2), (0).toPrecision(3), (99.99).toPrecision(3), (1e21).toPrecision(3), (123).toPrecision(3), (5).toPrecision())",
+			output: "1.00 1.3 3 -3 1 -0.00 0.000 1e+21 123.4560000000 0.0000010\n1235 100000000000000000000.00 -0.00000000015000000000 0.10000000000000000555 102\n1.23456e+2 1.23e+2 0e+0 0.00e+0 -1.000e-7 2e+0 3e+0 1.0e+1\n123.5 0.00012 0.0000012 1.2e+5 1.3 0.00 100 1.00e+21 123 5\n",
+		},
+		{
+			name:   "number constructor and statics",
+			input:  "console.log(Number('12'), Number(''), Number(' 0x1F '), Number('0b101'), Number('abc'), Number(), Number(null), Number(true), Number('1e3'), Number('-Infinity'))\nconsole.log(new Number(5), typeof new Number(5), new Number(5) + 1, new Number(7).toFixed(1))\nconsole.log(Number.EPSILON, Number.MAX_SAFE_INTEGER, Number.MIN_SAFE_INTEGER, Number.MAX_VALUE, Number.MIN_VALUE, Number.NaN, Number.POSITIVE_INFINITY, Number.NEGATIVE_INFINITY)\nconsole.log(Number.isInteger(5), Number.isInteger(5.5), Number.isInteger('5'), Number.isInteger(Infinity), Number.isSafeInteger(2 ** 53), Number.isSafeInteger(2 ** 53 - 1), Number.isFinite('1'), Number.isNaN('x'), Number.isNaN(NaN))\nconsole.log(Number.prototype.toFixed.length, Number.parseInt.length, Number.length, Number.name, (5).constructor === Number, Object.getPrototypeOf(5) === Number.prototype)\nclass N extends Number { double() { return this * 2 } }\nvar n = new N(21)\nconsole.log(n.double(), n instanceof Number, n.toFixed(2), JSON.stringify({ a: new Number(3), b: 1e21, c: -0 }))",
+			output: "12 0 31 5 NaN 0 0 1 1000 -Infinity\n[Number: 5] object 6 7.0\n2.220446049250313e-16 9007199254740991 -9007199254740991 1.7976931348623157e+308 5e-324 NaN Infinity -Infinity\ntrue false false false false true false false true\n1 2 1 Number true true\n42 true 21.00 {\"a\":3,\"b\":1e+21,\"c\":0}\n",
+		},
+		{
+			name:   "parse float and int",
+			input:  "console.log(Number.parseFloat === parseFloat, Number.parseInt === parseInt, parseFloat('  3.14abc'), parseFloat('.5'), parseFloat('-.5e2x'), parseFloat('1.'), parseFloat('e5'), parseFloat('-Infinityx'), parseFloat('1e'), parseFloat('-0'), parseFloat('1e400'))\nconsole.log(parseInt('  42px'), parseInt('-0x1F'), parseInt('0x'), parseInt('ff', 16), parseInt('0x10', 16), parseInt('0x10', 10), parseInt('z', 36), parseInt('12', 1), parseInt('12', 37), parseInt('-0'), parseInt('123456789012345678901234567890'), parseInt('zzzzzzzzzzzzzzzzz', 36), parseInt('1111111111111111111111111111111111111111111111111111111', 2))",
+			output: "true true 3.14 0.5 -50 1 NaN -Infinity 1 -0 Infinity\n42 -31 NaN 255 16 0 35 NaN NaN -0 1.2345678901234568e+29 2.865117999580704e+26 36028797018963970\n",
+		},
+		{
+			name:   "number method errors",
+			input:  "console.log(parseInt(''), parseInt('  -  1'), parseInt(15.99), parseInt('0.0000005'), parseInt(0.0000005), parseInt(null, 36), Number('0x1fffffffffffff1'), Number('0o777'), Number('1_0'))\ntry { (1).toString(1) } catch (e) { console.log(e.name, e.message) }\ntry { (1).toFixed(101) } catch (e) { console.log(e.name, e.message) }\ntry { (1).toPrecision(0) } catch (e) { console.log(e.name, e.message) }\ntry { (1).toExponential(-1) } catch (e) { console.log(e.name, e.message) }\ntry { var f = Number.prototype.toString; var o = { f: f }; o.f() } catch (e) { console.log(e.name, e.message) }",
+			output: "NaN NaN 15 0 5 1112745 144115188075855860 511 NaN\nRangeError toString() radix argument must be between 2 and 36\nRangeError toFixed() digits argument must be between 0 and 100\nRangeError toPrecision() argument must be between 1 and 100\nRangeError toExponential() argument must be between 0 and 100\nTypeError Number.prototype.toString requires that 'this' be a Number\n",
+		},
 	}
 
 	bin := filepath.Join(pwd, "..", "bin", "godzilla")
@@ -56,8 +790,21 @@ func TestAll(t *testing.T) {
 			cmd.Stdin = bytes.NewBufferString(test.input)
 			cmd.Stdout = &out
 			cmd.Stderr = &out
-			if err := cmd.Run(); err != nil {
-				t.Fatalf("error running test case %s error=%s stderr=%s", test.name, err, out)
+			err := cmd.Run()
+			if test.err {
+				if err == nil {
+					t.Fatalf("expected test case %s to fail: out=%s", test.name, out.String())
+				}
+
+				if want, got := test.output, out.String(); !strings.Contains(got, want) {
+					t.Fatalf("error doesn't match for test %s: want=%q got=%q", test.name, want, got)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("error running test case %s error=%s stderr=%s", test.name, err, out.String())
 			}
 
 			if want, got := test.output, out.String(); want != got {
@@ -4,5 +4,5 @@ let code = ""
 process.stdin.on("data", (d) => {
   code += d
 }).on("end", () => {
-  console.log(JSON.stringify(parse(code)))
+  console.log(JSON.stringify(parse(code, { plugins: ["objectRestSpread"] })))
 }).setEncoding("utf8")
@@ -0,0 +1,123 @@
+package runtime
+
+// JSArguments is the arguments object of a function call
+// The arguments object of a sloppy mode function with simple parameters is
+// mapped: its elements share the variables of the parameters until they are
+// deleted or redefined.
+type JSArguments struct {
+	JSObject
+	// mapped holds the variables of the parameters that the elements are
+	// mapped to, with nil for the elements that aren't mapped
+	mapped []*Object
+}
+
+// throwTypeError is the getter and setter of callee on the arguments object
+// of a strict mode function
+var throwTypeError = NewFunction("", 0, func(this Object, args []Object) Object {
+	panic(newTypeError("'caller', 'callee', and 'arguments' properties may not be accessed on strict mode functions or the arguments objects for calls to them"))
+})
+
+// NewArguments creates the unmapped arguments object of a strict mode
+// function or of a function whose parameters aren't simple
+func NewArguments(args []Object) *JSArguments {
+	a := newArguments(args)
+	a.setOwn(JSString("callee"), &Property{accessor: true, getter: throwTypeError, setter: throwTypeError})
+
+	return a
+}
+
+// NewMappedArguments creates the mapped arguments object of a sloppy mode
+// function, whose elements are mapped to the variables of the parameters
+// It's created on entry to the function, which is the innermost call.
+func NewMappedArguments(args []Object, params ...*Object) *JSArguments {
+	a := newArguments(args)
+	callee := callStack[len(callStack)-1].function
+	a.setOwn(JSString("callee"), &Property{value: callee, writable: true, configurable: true})
+	for i := 0; i < len(params) && i < len(args); i++ {
+		if a.mapped == nil {
+			a.mapped = make([]*Object, len(args))
+		}
+		a.mapped[i] = params[i]
+	}
+
+	return a
+}
+
+func newArguments(args []Object) *JSArguments {
+	a := &JSArguments{JSObject: JSObject{class: "Arguments", proto: objectPrototype}}
+	for i, v := range args {
+		a.setOwn(indexKey(int64(i)), &Property{value: v, writable: true, enumerable: true, configurable: true})
+	}
+	a.setOwn(JSString("length"), &Property{value: JSNumber(len(args)), writable: true, configurable: true})
+	a.setOwn(SymbolIterator, &Property{value: arrayPrototypeValues, writable: true, configurable: true})
+
+	return a
+}
+
+// mapping returns the index of a mapped element
+func (self *JSArguments) mapping(key PropertyKey) (int, bool) {
+	i, ok := arrayIndex(key)
+	if !ok || int64(i) >= int64(len(self.mapped)) || self.mapped[i] == nil {
+		return 0, false
+	}
+
+	return int(i), true
+}
+
+func (self *JSArguments) GetProperty(prop string) (Object, bool) {
+	return lookupProperty(self, prop)
+}
+
+func (self *JSArguments) getOwnProperty(key PropertyKey) *Property {
+	p := self.JSObject.getOwnProperty(key)
+	if i, ok := self.mapping(key); ok {
+		p.value = *self.mapped[i]
+	}
+
+	return p
+}
+
+func (self *JSArguments) defineOwnProperty(key PropertyKey, desc *PropertyDescriptor) bool {
+	i, mapped := self.mapping(key)
+	if mapped {
+		self.getOwnProperty(key)
+		if desc.isData() && !desc.HasValue && desc.HasWritable && !desc.Writable {
+			// the element keeps the current value of the parameter
+			d := *desc
+			d.Value, d.HasValue = *self.mapped[i], true
+			desc = &d
+		}
+	}
+
+	if !self.JSObject.defineOwnProperty(key, desc) {
+		return false
+	}
+
+	if mapped {
+		if desc.isAccessor() {
+			self.mapped[i] = nil
+		} else {
+			if desc.HasValue {
+				*self.mapped[i] = desc.Value
+			}
+			if desc.HasWritable && !desc.Writable {
+				self.mapped[i] = nil
+			}
+		}
+	}
+
+	return true
+}
+
+func (self *JSArguments) deleteProperty(key PropertyKey) bool {
+	i, mapped := self.mapping(key)
+	if !self.JSObject.deleteProperty(key) {
+		return false
+	}
+
+	if mapped {
+		self.mapped[i] = nil
+	}
+
+	return true
+}
@@ -0,0 +1,34 @@
+package runtime
+
+import "testing"
+
+func TestMappedArguments(t *testing.T) {
+	callStack = append(callStack, callFrame{function: NewFunction("f", 2, nil)})
+	defer func() { callStack = callStack[:len(callStack)-1] }()
+
+	var a, b Object = JSNumber(1), Undefined
+	args := NewMappedArguments([]Object{JSNumber(1)}, &a, &b)
+
+	a = JSNumber(2)
+	if v := getIndex(args, 0); v != JSNumber(2) {
+		t.Fatalf("a mapped element should read the parameter: v=%v", v)
+	}
+
+	setIndex(args, 0, JSNumber(3))
+	if a != JSNumber(3) {
+		t.Fatalf("a mapped element should write the parameter: a=%v", a)
+	}
+
+	setIndex(args, 1, JSNumber(4))
+	if b != Undefined {
+		t.Fatalf("an element without an argument shouldn't be mapped: b=%v", b)
+	}
+
+	if !args.deleteProperty(JSString("0")) {
+		t.Fatal("an element should be deletable")
+	}
+	setIndex(args, 0, JSNumber(5))
+	if a != JSNumber(3) {
+		t.Fatalf("a deleted element should be unmapped: a=%v", a)
+	}
+}
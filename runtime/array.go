@@ -0,0 +1,346 @@
+package runtime
+
+import (
+	"math"
+	"sort"
+	"strconv"
+)
+
+// JSArray is an array exotic object
+// Arrays without holes whose elements are all writable, enumerable and
+// configurable keep their elements in a slice. Any other array falls back to
+// sparse storage, where the elements are ordinary properties.
+type JSArray struct {
+	JSObject
+	elements []Object
+	sparse   bool
+	// length is the length of a sparse array
+	length         uint32
+	lengthReadOnly bool
+}
+
+// NewArray creates an array with the given elements
+func NewArray(elements []Object) *JSArray {
+	return &JSArray{
+		JSObject: JSObject{class: "Array", proto: arrayPrototype},
+		elements: elements,
+	}
+}
+
+// hole marks an elision in an array literal
+type hole struct{}
+
+func (self hole) Type() JSObjectType { return "" }
+
+// Hole is an element of an array literal that is left out, like in [1, , 3]
+var Hole Object = hole{}
+
+// NewArrayLiteral creates the array of an array literal, whose elisions are
+// marked with Hole
+func NewArrayLiteral(elements []Object) *JSArray {
+	a := NewArray(elements)
+	for i, e := range elements {
+		if e != Hole {
+			continue
+		}
+
+		a.makeSparse()
+		for j := i; j < len(elements); j++ {
+			if elements[j] == Hole {
+				a.JSObject.deleteProperty(indexKey(int64(j)))
+			}
+		}
+		break
+	}
+
+	return a
+}
+
+func (self *JSArray) Len() int64 {
+	if self.sparse {
+		return int64(self.length)
+	}
+
+	return int64(len(self.elements))
+}
+
+// makeSparse moves the elements of a dense array to ordinary properties
+func (self *JSArray) makeSparse() {
+	if self.sparse {
+		return
+	}
+
+	for i, v := range self.elements {
+		self.JSObject.setOwn(indexKey(int64(i)), &Property{value: v, writable: true, enumerable: true, configurable: true})
+	}
+
+	self.length = uint32(len(self.elements))
+	self.elements = nil
+	self.sparse = true
+}
+
+func (self *JSArray) GetProperty(prop string) (Object, bool) {
+	return lookupProperty(self, prop)
+}
+
+func (self *JSArray) getOwnProperty(key PropertyKey) *Property {
+	if key == JSString("length") {
+		return &Property{value: JSNumber(self.Len()), writable: !self.lengthReadOnly}
+	}
+
+	if !self.sparse {
+		if i, ok := arrayIndex(key); ok && int64(i) < int64(len(self.elements)) {
+			return &Property{value: self.elements[i], writable: true, enumerable: true, configurable: true}
+		}
+	}
+
+	return self.JSObject.getOwnProperty(key)
+}
+
+func (self *JSArray) defineOwnProperty(key PropertyKey, desc *PropertyDescriptor) bool {
+	if key == JSString("length") {
+		return self.setLength(desc)
+	}
+
+	i, ok := arrayIndex(key)
+	if !ok {
+		return self.JSObject.defineOwnProperty(key, desc)
+	}
+
+	if !self.sparse {
+		n := uint32(len(self.elements))
+		switch {
+		case i < n && isDefaultDataDescriptor(desc, false):
+			if desc.HasValue {
+				self.elements[i] = desc.Value
+			}
+			return true
+		case i == n && isDefaultDataDescriptor(desc, true) && !self.nonExtensible && !self.lengthReadOnly:
+			self.elements = append(self.elements, desc.Value)
+			return true
+		}
+
+		self.makeSparse()
+	}
+
+	if i >= self.length && self.lengthReadOnly {
+		return false
+	}
+
+	if !self.JSObject.defineOwnProperty(key, desc) {
+		return false
+	}
+
+	if i >= self.length {
+		self.length = i + 1
+	}
+
+	return true
+}
+
+// isDefaultDataDescriptor tells whether a descriptor keeps an element
+// writable, enumerable and configurable, which a dense array requires
+// A new element requires all attributes to be present.
+func isDefaultDataDescriptor(desc *PropertyDescriptor, isNew bool) bool {
+	if desc.isAccessor() {
+		return false
+	}
+
+	if isNew {
+		return desc.HasWritable && desc.Writable && desc.HasEnumerable && desc.Enumerable && desc.HasConfigurable && desc.Configurable
+	}
+
+	return (!desc.HasWritable || desc.Writable) && (!desc.HasEnumerable || desc.Enumerable) && (!desc.HasConfigurable || desc.Configurable)
+}
+
+// setLength implements ArraySetLength, which deletes the elements past a new
+// shorter length
+func (self *JSArray) setLength(desc *PropertyDescriptor) bool {
+	if desc.isAccessor() || (desc.HasConfigurable && desc.Configurable) || (desc.HasEnumerable && desc.Enumerable) {
+		return false
+	}
+	if desc.HasWritable && desc.Writable && self.lengthReadOnly {
+		return false
+	}
+
+	defer func() {
+		if desc.HasWritable && !desc.Writable {
+			self.lengthReadOnly = true
+		}
+	}()
+
+	if !desc.HasValue {
+		return true
+	}
+
+	newLen := toArrayLength(desc.Value)
+	oldLen := uint32(self.Len())
+	if newLen == oldLen {
+		return true
+	}
+	if self.lengthReadOnly {
+		return false
+	}
+
+	if !self.sparse {
+		if newLen < oldLen {
+			for i := newLen; i < oldLen; i++ {
+				self.elements[i] = nil
+			}
+			self.elements = self.elements[:newLen]
+			return true
+		}
+
+		self.makeSparse()
+	}
+
+	if newLen > oldLen {
+		self.length = newLen
+		return true
+	}
+
+	// delete the elements from the end, stopping at a non-configurable one
+	var indices []uint32
+	for _, k := range self.JSObject.keys {
+		if i, ok := arrayIndex(k); ok && i >= newLen {
+			indices = append(indices, i)
+		}
+	}
+	sortIndicesDescending(indices)
+
+	for _, i := range indices {
+		if !self.JSObject.deleteProperty(indexKey(int64(i))) {
+			self.length = i + 1
+			return false
+		}
+	}
+
+	self.length = newLen
+	return true
+}
+
+func (self *JSArray) deleteProperty(key PropertyKey) bool {
+	if key == JSString("length") {
+		return false
+	}
+
+	if i, ok := arrayIndex(key); ok && !self.sparse {
+		if int64(i) >= int64(len(self.elements)) {
+			return true
+		}
+
+		self.makeSparse()
+	}
+
+	return self.JSObject.deleteProperty(key)
+}
+
+// ownKeys returns the indices, then length and then the other keys
+func (self *JSArray) ownKeys() []PropertyKey {
+	keys := self.JSObject.ownKeys()
+
+	n := 0
+	for n < len(keys) {
+		if _, ok := arrayIndex(keys[n]); !ok {
+			break
+		}
+		n++
+	}
+
+	var result []PropertyKey
+	for i := range self.elements {
+		result = append(result, indexKey(int64(i)))
+	}
+	result = append(result, keys[:n]...)
+	result = append(result, JSString("length"))
+	result = append(result, keys[n:]...)
+
+	return result
+}
+
+// toArrayLength converts the value of the length property to an array length
+func toArrayLength(v Object) uint32 {
+	n := ToUint32(v)
+	if float64(n) != float64(ToNumber(v)) {
+		panic(newRangeError("Invalid array length"))
+	}
+
+	return n
+}
+
+// indexKey converts an index to a property key
+func indexKey(i int64) PropertyKey {
+	return JSString(strconv.FormatInt(i, 10))
+}
+
+// getIndex gets an element of an array-like object, reading dense arrays
+// directly
+func getIndex(o ObjectValue, i int64) Object {
+	if a, ok := o.(*JSArray); ok && !a.sparse && i < int64(len(a.elements)) {
+		return a.elements[i]
+	}
+
+	return getProperty(o, indexKey(i), o)
+}
+
+// hasIndex tells whether an array-like object has an element
+func hasIndex(o ObjectValue, i int64) bool {
+	if a, ok := o.(*JSArray); ok && !a.sparse && i < int64(len(a.elements)) {
+		return true
+	}
+
+	return hasProperty(o, indexKey(i))
+}
+
+// setIndex sets an element of an array-like object and fails if it can't
+// be set
+func setIndex(o ObjectValue, i int64, v Object) {
+	if a, ok := o.(*JSArray); ok && !a.sparse && i < int64(len(a.elements)) {
+		a.elements[i] = v
+		return
+	}
+
+	setOrThrow(o, indexKey(i), v)
+}
+
+// setOrThrow sets a property and fails if it can't be set
+func setOrThrow(o ObjectValue, key PropertyKey, v Object) {
+	if !setProperty(o, key, v, o) {
+		panic(newTypeError("Cannot assign to read only property '" + toDisplayString(key) + "' of object '" + string(ToString(objectPrototypeToString(o, nil))) + "'"))
+	}
+}
+
+// deleteOrThrow deletes a property and fails if it can't be deleted
+func deleteOrThrow(o ObjectValue, key PropertyKey) {
+	if !o.deleteProperty(key) {
+		panic(newTypeError("Cannot delete property '" + toDisplayString(key) + "' of " + string(ToString(objectPrototypeToString(o, nil)))))
+	}
+}
+
+// lengthOfArrayLike returns the length of an array-like object
+func lengthOfArrayLike(o ObjectValue) int64 {
+	if a, ok := o.(*JSArray); ok {
+		return a.Len()
+	}
+
+	return ToLength(getProperty(o, JSString("length"), o))
+}
+
+// relativeIndex converts a relative index argument, which counts from the
+// end if negative, to an index between 0 and length
+func relativeIndex(v Object, length int64, defaultIndex int64) int64 {
+	if v == nil {
+		return defaultIndex
+	}
+
+	f := ToIntegerOrInfinity(v)
+	if f < 0 {
+		return int64(math.Max(float64(length)+f, 0))
+	}
+
+	return int64(math.Min(f, float64(length)))
+}
+
+func sortIndicesDescending(indices []uint32) {
+	sort.Slice(indices, func(i, j int) bool { return indices[i] > indices[j] })
+}
@@ -0,0 +1,130 @@
+package runtime
+
+import (
+	"math"
+	"testing"
+)
+
+func numbers(n ...float64) *JSArray {
+	var elements []Object
+	for _, f := range n {
+		elements = append(elements, JSNumber(f))
+	}
+
+	return NewArray(elements)
+}
+
+func TestArrayMethods(t *testing.T) {
+	double := NewFunction("", 1, func(this Object, args []Object) Object {
+		return ToNumber(args[0]) * 2
+	})
+	odd := NewFunction("", 1, func(this Object, args []Object) Object {
+		return JSBoolean(int(ToNumber(args[0]))%2 == 1)
+	})
+	sum := NewFunction("", 2, func(this Object, args []Object) Object {
+		return ToNumber(args[0]) + ToNumber(args[1])
+	})
+	descending := NewFunction("", 2, func(this Object, args []Object) Object {
+		return ToNumber(args[1]) - ToNumber(args[0])
+	})
+
+	sparse := NewArrayLiteral([]Object{JSNumber(1), Hole, JSNumber(3)})
+
+	tests := []struct {
+		name string
+		fn   func() Object
+		want string
+	}{
+		{"push", func() Object { a := numbers(1); arrayPrototypePush(a, []Object{JSNumber(2), JSNumber(3)}); return a }, "[ 1, 2, 3 ]"},
+		{"push returns length", func() Object { return arrayPrototypePush(numbers(1), []Object{JSNumber(2)}) }, "2"},
+		{"pop", func() Object { a := numbers(1, 2); return NewArray([]Object{arrayPrototypePop(a, nil), a}) }, "[ 2, [ 1 ] ]"},
+		{"pop empty", func() Object { return arrayPrototypePop(numbers(), nil) }, "undefined"},
+		{"shift", func() Object { a := numbers(1, 2); return NewArray([]Object{arrayPrototypeShift(a, nil), a}) }, "[ 1, [ 2 ] ]"},
+		{"unshift", func() Object { a := numbers(3); arrayPrototypeUnshift(a, []Object{JSNumber(1), JSNumber(2)}); return a }, "[ 1, 2, 3 ]"},
+		{"slice", func() Object { return arrayPrototypeSlice(numbers(1, 2, 3, 4), []Object{JSNumber(1), JSNumber(-1)}) }, "[ 2, 3 ]"},
+		{"slice holes", func() Object { return arrayPrototypeSlice(sparse, nil) }, "[ 1, <1 empty item>, 3 ]"},
+		{"splice", func() Object {
+			a := numbers(1, 2, 3, 4)
+			removed := arrayPrototypeSplice(a, []Object{JSNumber(1), JSNumber(2), JSString("x")})
+			return NewArray([]Object{removed, a})
+		}, "[ [ 2, 3 ], [ 1, 'x', 4 ] ]"},
+		{"splice sparse", func() Object {
+			a := NewArrayLiteral([]Object{JSNumber(1), Hole, JSNumber(3), JSNumber(4)})
+			arrayPrototypeSplice(a, []Object{JSNumber(0), JSNumber(1)})
+			return a
+		}, "[ <1 empty item>, 3, 4 ]"},
+		{"map", func() Object { return arrayPrototypeMap(numbers(1, 2), []Object{double}) }, "[ 2, 4 ]"},
+		{"map holes", func() Object { return arrayPrototypeMap(sparse, []Object{double}) }, "[ 2, <1 empty item>, 6 ]"},
+		{"filter", func() Object { return arrayPrototypeFilter(numbers(1, 2, 3), []Object{odd}) }, "[ 1, 3 ]"},
+		{"reduce", func() Object { return arrayPrototypeReduce(numbers(1, 2, 3), []Object{sum}) }, "6"},
+		{"reduce initial value", func() Object { return arrayPrototypeReduce(numbers(), []Object{sum, JSNumber(10)}) }, "10"},
+		{"reduceRight", func() Object {
+			concat := NewFunction("", 2, func(this Object, args []Object) Object { return ToString(args[0]) + ToString(args[1]) })
+			return arrayPrototypeReduceRight(numbers(1, 2, 3), []Object{concat})
+		}, "'321'"},
+		{"forEach skips holes", func() Object {
+			n := 0
+			arrayPrototypeForEach(sparse, []Object{NewFunction("", 0, func(this Object, args []Object) Object { n++; return nil })})
+			return JSNumber(n)
+		}, "2"},
+		{"indexOf", func() Object { return arrayPrototypeIndexOf(numbers(1, 2, 1), []Object{JSNumber(1), JSNumber(1)}) }, "2"},
+		{"lastIndexOf", func() Object { return arrayPrototypeLastIndexOf(numbers(1, 2, 1), []Object{JSNumber(1)}) }, "2"},
+		{"includes NaN", func() Object { return arrayPrototypeIncludes(numbers(nan()), []Object{JSNumber(nan())}) }, "true"},
+		{"indexOf NaN", func() Object { return arrayPrototypeIndexOf(numbers(nan()), []Object{JSNumber(nan())}) }, "-1"},
+		{"join", func() Object { return arrayPrototypeJoin(NewArray([]Object{JSNumber(1), nil, JSString("a")}), nil) }, "'1,,a'"},
+		{"join separator", func() Object { return arrayPrototypeJoin(numbers(1, 2), []Object{JSString("-")}) }, "'1-2'"},
+		{"join cycle", func() Object { a := numbers(1); arrayPrototypePush(a, []Object{a}); return arrayPrototypeJoin(a, nil) }, "'1,'"},
+		{"sort", func() Object { return arrayPrototypeSort(numbers(10, 9, 1), nil) }, "[ 1, 10, 9 ]"},
+		{"sort comparator", func() Object { return arrayPrototypeSort(numbers(1, 3, 2), []Object{descending}) }, "[ 3, 2, 1 ]"},
+		{"sort undefined and holes", func() Object {
+			return arrayPrototypeSort(NewArrayLiteral([]Object{nil, Hole, JSString("b"), JSString("a")}), nil)
+		}, "[ 'a', 'b', undefined, <1 empty item> ]"},
+		{"concat", func() Object { return arrayPrototypeConcat(numbers(1), []Object{numbers(2, 3), JSNumber(4)}) }, "[ 1, 2, 3, 4 ]"},
+		{"find", func() Object { return arrayPrototypeFind(numbers(2, 3, 5), []Object{odd}) }, "3"},
+		{"findIndex", func() Object { return arrayPrototypeFindIndex(numbers(2, 4), []Object{odd}) }, "-1"},
+		{"every", func() Object { return arrayPrototypeEvery(numbers(1, 3), []Object{odd}) }, "true"},
+		{"some", func() Object { return arrayPrototypeSome(numbers(2, 4), []Object{odd}) }, "false"},
+		{"reverse", func() Object { return arrayPrototypeReverse(numbers(1, 2, 3), nil) }, "[ 3, 2, 1 ]"},
+		{"fill", func() Object { return arrayPrototypeFill(numbers(1, 2, 3), []Object{JSNumber(0), JSNumber(1)}) }, "[ 1, 0, 0 ]"},
+		{"generic push", func() Object {
+			o := NewObject()
+			arrayPrototypePush(o, []Object{JSString("a")})
+			return o
+		}, "{ '0': 'a', length: 1 }"},
+	}
+
+	for _, test := range tests {
+		if got := Inspect(test.fn()); got != test.want {
+			t.Errorf("%s: want=%s got=%s", test.name, test.want, got)
+		}
+	}
+}
+
+func TestArrayLength(t *testing.T) {
+	a := numbers(1, 2, 3)
+	setOrThrow(a, JSString("length"), JSNumber(1))
+	if got := Inspect(a); got != "[ 1 ]" {
+		t.Fatalf("truncating length: got=%s", got)
+	}
+
+	setOrThrow(a, indexKey(3), JSNumber(4))
+	if got := Inspect(a); got != "[ 1, <2 empty items>, 4 ]" || !a.sparse {
+		t.Fatalf("assigning past the end should create holes: got=%s", got)
+	}
+
+	setOrThrow(a, JSString("length"), JSNumber(2))
+	if a.Len() != 2 || a.getOwnProperty(indexKey(3)) != nil {
+		t.Fatalf("truncating a sparse array should delete elements: got=%s", Inspect(a))
+	}
+
+	defer func() {
+		if e, ok := recover().(*Exception); !ok || e.Value.(ObjectValue).object().proto != rangeErrorPrototype {
+			t.Fatal("setting an invalid length should be a RangeError")
+		}
+	}()
+	setOrThrow(a, JSString("length"), JSNumber(-1))
+}
+
+func nan() float64 {
+	return math.NaN()
+}
@@ -0,0 +1,74 @@
+package runtime
+
+// Async is a call of an async function, whose body runs as a coroutine that
+// is suspended by await until the awaited promise settles
+// The call returns a promise that is settled with the completion of the
+// body.
+type Async struct {
+	coroutine
+	capability *promiseCapability
+}
+
+// asyncFunctionPrototype is %AsyncFunction.prototype%, the prototype of async
+// functions
+var asyncFunctionPrototype = &JSObject{class: "Object", proto: functionPrototype}
+
+func init() {
+	linkPrototype(NewFunction("AsyncFunction", 1, func(this Object, args []Object) Object {
+		panic(newTypeError("Code generation from strings disallowed for this context"))
+	}), asyncFunctionPrototype)
+	defineToStringTag(asyncFunctionPrototype, "AsyncFunction")
+}
+
+// NewAsyncFunction creates an async function, which isn't a constructor
+// The function returns the promise of the call run by RunAsync.
+func NewAsyncFunction(name string, length int, fn func(this Object, args []Object) Object) *JSFunction {
+	f := NewFunction(name, length, fn)
+	f.proto = asyncFunctionPrototype
+	f.async = true
+
+	return f
+}
+
+// RunAsync runs the body of an async function up to the first await, and
+// returns the promise of the call
+func RunAsync(body func(async *Async) Object) Object {
+	a := &Async{capability: newPromiseCapability(promiseConstructor)}
+	a.body = func() Object { return body(a) }
+	a.step(resumption{method: "next"})
+
+	return a.capability.promise
+}
+
+// step resumes the body, which is resumed again by the reactions of the
+// promise it awaits
+func (a *Async) step(r resumption) {
+	result := a.resume(r)
+	switch {
+	case result.panic != nil:
+		thrown, ok := result.panic.(*Exception)
+		if !ok {
+			panic(result.panic)
+		}
+		Call(a.capability.reject, Undefined, []Object{thrown.Value})
+	case result.done:
+		Call(a.capability.resolve, Undefined, []Object{result.value})
+	default:
+		onFulfilled := NewFunction("", 1, func(this Object, args []Object) Object {
+			a.step(resumption{"next", Arg(args, 0)})
+			return Undefined
+		})
+		onRejected := NewFunction("", 1, func(this Object, args []Object) Object {
+			a.step(resumption{"throw", Arg(args, 0)})
+			return Undefined
+		})
+		result.value.(*Promise).then(onFulfilled, onRejected, nil)
+	}
+}
+
+// Await implements await, which suspends the body until the promise of a
+// value settles, and evaluates to the value of the promise or throws its
+// reason
+func (a *Async) Await(v Object) Object {
+	return continueBody(a.suspend(promiseResolve(promiseConstructor, v)))
+}
@@ -0,0 +1,715 @@
+package runtime
+
+import (
+	"bytes"
+	"sort"
+)
+
+var (
+	// arrayPrototype is Array.prototype, which is itself an array
+	arrayPrototype = &JSArray{JSObject: JSObject{class: "Array", proto: objectPrototype}}
+
+	arrayConstructor = NewFunction("Array", 1, func(this Object, args []Object) Object {
+		if len(args) == 1 {
+			if _, ok := args[0].(JSNumber); ok {
+				a := NewArray(nil)
+				a.setLength(&PropertyDescriptor{Value: args[0], HasValue: true})
+				return a
+			}
+		}
+
+		return NewArray(append([]Object(nil), args...))
+	})
+
+	// arrayPrototypeValues is Array.prototype.values, which is also the
+	// Symbol.iterator method of arrays
+	arrayPrototypeValues *JSFunction
+
+	// joining holds the arrays that are being joined, so that an array that
+	// contains itself is joined as an empty string
+	joining []ObjectValue
+)
+
+func init() {
+	defineConstructor(arrayConstructor, arrayPrototype)
+	defineMethod(arrayConstructor, "from", 1, Array_From)
+	defineMethod(arrayConstructor, "isArray", 1, Array_IsArray)
+	defineMethod(arrayConstructor, "of", 0, Array_Of)
+
+	defineMethod(arrayPrototype, "concat", 1, arrayPrototypeConcat)
+	defineMethod(arrayPrototype, "entries", 0, arrayPrototypeEntries)
+	defineMethod(arrayPrototype, "every", 1, arrayPrototypeEvery)
+	defineMethod(arrayPrototype, "fill", 1, arrayPrototypeFill)
+	defineMethod(arrayPrototype, "filter", 1, arrayPrototypeFilter)
+	defineMethod(arrayPrototype, "find", 1, arrayPrototypeFind)
+	defineMethod(arrayPrototype, "findIndex", 1, arrayPrototypeFindIndex)
+	defineMethod(arrayPrototype, "forEach", 1, arrayPrototypeForEach)
+	defineMethod(arrayPrototype, "includes", 1, arrayPrototypeIncludes)
+	defineMethod(arrayPrototype, "indexOf", 1, arrayPrototypeIndexOf)
+	defineMethod(arrayPrototype, "join", 1, arrayPrototypeJoin)
+	defineMethod(arrayPrototype, "keys", 0, arrayPrototypeKeys)
+	defineMethod(arrayPrototype, "lastIndexOf", 1, arrayPrototypeLastIndexOf)
+	defineMethod(arrayPrototype, "map", 1, arrayPrototypeMap)
+	defineMethod(arrayPrototype, "pop", 0, arrayPrototypePop)
+	defineMethod(arrayPrototype, "push", 1, arrayPrototypePush)
+	defineMethod(arrayPrototype, "reduce", 1, arrayPrototypeReduce)
+	defineMethod(arrayPrototype, "reduceRight", 1, arrayPrototypeReduceRight)
+	defineMethod(arrayPrototype, "reverse", 0, arrayPrototypeReverse)
+	defineMethod(arrayPrototype, "shift", 0, arrayPrototypeShift)
+	defineMethod(arrayPrototype, "slice", 2, arrayPrototypeSlice)
+	defineMethod(arrayPrototype, "some", 1, arrayPrototypeSome)
+	defineMethod(arrayPrototype, "sort", 1, arrayPrototypeSort)
+	defineMethod(arrayPrototype, "splice", 2, arrayPrototypeSplice)
+	defineMethod(arrayPrototype, "toString", 0, arrayPrototypeToString)
+	defineMethod(arrayPrototype, "unshift", 1, arrayPrototypeUnshift)
+	arrayPrototypeValues = defineMethod(arrayPrototype, "values", 0, func(this Object, args []Object) Object {
+		return newIteratorObject(arrayIteratorPrototype, "Array Iterator", newArrayIterator(ToObject(this), "values"))
+	})
+	defineHiddenKey(arrayPrototype, SymbolIterator, arrayPrototypeValues)
+}
+
+// denseArray returns an array that can be modified through its elements
+// slice without observable differences from the generic algorithms
+func denseArray(o ObjectValue) (*JSArray, bool) {
+	a, ok := o.(*JSArray)
+	if !ok || a.sparse || a.nonExtensible || a.lengthReadOnly {
+		return nil, false
+	}
+
+	return a, true
+}
+
+// requireFunction returns the callback argument of a builtin
+func requireFunction(v Object) *JSFunction {
+	f, ok := v.(*JSFunction)
+	if !ok {
+		panic(newTypeError(toDisplayString(v) + " is not a function"))
+	}
+
+	return f
+}
+
+// isArray tells whether a value is an array exotic object
+func isArray(v Object) bool {
+	_, ok := v.(*JSArray)
+	return ok
+}
+
+func Array_From(this Object, args []Object) Object {
+	items := Arg(args, 0)
+	if items == nil {
+		panic(newTypeError("undefined is not iterable (cannot read property Symbol(Symbol.iterator))"))
+	}
+
+	var mapFn *JSFunction
+	if f := Arg(args, 1); f != nil {
+		mapFn = requireFunction(f)
+	}
+
+	var values []Object
+	if isNullish(Get(items, SymbolIterator)) {
+		o := ToObject(items)
+		for i := int64(0); i < lengthOfArrayLike(o); i++ {
+			v := getIndex(o, i)
+			if mapFn != nil {
+				v = mapFn.Call(Arg(args, 2), []Object{v, JSNumber(i)})
+			}
+			values = append(values, v)
+		}
+
+		return NewArray(values)
+	}
+
+	it := GetIterator(items)
+	defer it.CloseOnThrow()
+	for {
+		v, ok := it.Step()
+		if !ok {
+			return NewArray(values)
+		}
+		if mapFn != nil {
+			v = mapFn.Call(Arg(args, 2), []Object{v, JSNumber(len(values))})
+		}
+		values = append(values, v)
+	}
+}
+
+func Array_IsArray(this Object, args []Object) Object {
+	return JSBoolean(isArray(Arg(args, 0)))
+}
+
+func Array_Of(this Object, args []Object) Object {
+	return NewArray(append([]Object(nil), args...))
+}
+
+func arrayPrototypeConcat(this Object, args []Object) Object {
+	var values []Object
+	holes := false
+	for _, item := range append([]Object{ToObject(this)}, args...) {
+		e, ok := item.(*JSArray)
+		if !ok {
+			values = append(values, item)
+			continue
+		}
+
+		for i := int64(0); i < e.Len(); i++ {
+			if hasIndex(e, i) {
+				values = append(values, getIndex(e, i))
+			} else {
+				values = append(values, Hole)
+				holes = true
+			}
+		}
+	}
+
+	if holes {
+		return NewArrayLiteral(values)
+	}
+
+	return NewArray(values)
+}
+
+// iterate calls the callback of an iteration method for each element that
+// is present, until the callback returns false
+func iterate(this Object, args []Object, f func(v Object, i int64, result Object) bool) {
+	o := ToObject(this)
+	n := lengthOfArrayLike(o)
+	callback := requireFunction(Arg(args, 0))
+	thisArg := Arg(args, 1)
+
+	for i := int64(0); i < n; i++ {
+		if !hasIndex(o, i) {
+			continue
+		}
+
+		v := getIndex(o, i)
+		if !f(v, i, callback.Call(thisArg, []Object{v, JSNumber(i), o})) {
+			return
+		}
+	}
+}
+
+func arrayPrototypeEntries(this Object, args []Object) Object {
+	return newIteratorObject(arrayIteratorPrototype, "Array Iterator", newArrayIterator(ToObject(this), "entries"))
+}
+
+func arrayPrototypeEvery(this Object, args []Object) Object {
+	result := true
+	iterate(this, args, func(v Object, i int64, r Object) bool {
+		result = ToBoolean(r)
+		return result
+	})
+
+	return JSBoolean(result)
+}
+
+func arrayPrototypeFill(this Object, args []Object) Object {
+	o := ToObject(this)
+	n := lengthOfArrayLike(o)
+	end := relativeIndex(Arg(args, 2), n, n)
+	for i := relativeIndex(Arg(args, 1), n, 0); i < end; i++ {
+		setIndex(o, i, Arg(args, 0))
+	}
+
+	return o
+}
+
+func arrayPrototypeFilter(this Object, args []Object) Object {
+	var values []Object
+	iterate(this, args, func(v Object, i int64, r Object) bool {
+		if ToBoolean(r) {
+			values = append(values, v)
+		}
+		return true
+	})
+
+	return NewArray(values)
+}
+
+// findIndex calls the predicate for every index, including holes, and
+// returns the first index for which it returns a truthy value
+func findIndex(this Object, args []Object) (int64, Object) {
+	o := ToObject(this)
+	n := lengthOfArrayLike(o)
+	predicate := requireFunction(Arg(args, 0))
+
+	for i := int64(0); i < n; i++ {
+		v := getIndex(o, i)
+		if ToBoolean(predicate.Call(Arg(args, 1), []Object{v, JSNumber(i), o})) {
+			return i, v
+		}
+	}
+
+	return -1, nil
+}
+
+func arrayPrototypeFind(this Object, args []Object) Object {
+	_, v := findIndex(this, args)
+	return v
+}
+
+func arrayPrototypeFindIndex(this Object, args []Object) Object {
+	i, _ := findIndex(this, args)
+	return JSNumber(i)
+}
+
+func arrayPrototypeForEach(this Object, args []Object) Object {
+	iterate(this, args, func(v Object, i int64, r Object) bool { return true })
+	return nil
+}
+
+func arrayPrototypeIncludes(this Object, args []Object) Object {
+	o := ToObject(this)
+	n := lengthOfArrayLike(o)
+	for i := relativeIndex(Arg(args, 1), n, 0); i < n; i++ {
+		if sameValueZero(getIndex(o, i), Arg(args, 0)) {
+			return JSBoolean(true)
+		}
+	}
+
+	return JSBoolean(false)
+}
+
+func arrayPrototypeIndexOf(this Object, args []Object) Object {
+	o := ToObject(this)
+	n := lengthOfArrayLike(o)
+	for i := relativeIndex(Arg(args, 1), n, 0); i < n; i++ {
+		if hasIndex(o, i) && StrictEquals(getIndex(o, i), Arg(args, 0)) {
+			return JSNumber(i)
+		}
+	}
+
+	return JSNumber(-1)
+}
+
+func arrayPrototypeJoin(this Object, args []Object) Object {
+	o := ToObject(this)
+	n := lengthOfArrayLike(o)
+	sep := JSString(",")
+	if s := Arg(args, 0); s != nil {
+		sep = ToString(s)
+	}
+
+	for _, j := range joining {
+		if j == o {
+			return JSString("")
+		}
+	}
+	joining = append(joining, o)
+	defer func() { joining = joining[:len(joining)-1] }()
+
+	var out bytes.Buffer
+	for i := int64(0); i < n; i++ {
+		if i > 0 {
+			out.WriteString(string(sep))
+		}
+		if v := getIndex(o, i); !isNullish(v) {
+			out.WriteString(string(ToString(v)))
+		}
+	}
+
+	return JSString(out.String())
+}
+
+func arrayPrototypeKeys(this Object, args []Object) Object {
+	return newIteratorObject(arrayIteratorPrototype, "Array Iterator", newArrayIterator(ToObject(this), "keys"))
+}
+
+func arrayPrototypeLastIndexOf(this Object, args []Object) Object {
+	o := ToObject(this)
+	n := lengthOfArrayLike(o)
+	from := n - 1
+	if len(args) > 1 {
+		f := ToIntegerOrInfinity(args[1])
+		if f < 0 {
+			f += float64(n)
+		}
+		if f < float64(from) {
+			from = int64(f)
+		}
+	}
+
+	for i := from; i >= 0; i-- {
+		if hasIndex(o, i) && StrictEquals(getIndex(o, i), Arg(args, 0)) {
+			return JSNumber(i)
+		}
+	}
+
+	return JSNumber(-1)
+}
+
+func arrayPrototypeMap(this Object, args []Object) Object {
+	o := ToObject(this)
+	values := make([]Object, lengthOfArrayLike(o))
+	for i := range values {
+		values[i] = Hole
+	}
+
+	holes := false
+	iterate(this, args, func(v Object, i int64, r Object) bool {
+		values[i] = r
+		return true
+	})
+	for _, v := range values {
+		holes = holes || v == Hole
+	}
+
+	if holes {
+		return NewArrayLiteral(values)
+	}
+
+	return NewArray(values)
+}
+
+func arrayPrototypePop(this Object, args []Object) Object {
+	o := ToObject(this)
+	if a, ok := denseArray(o); ok {
+		n := len(a.elements)
+		if n == 0 {
+			return nil
+		}
+
+		v := a.elements[n-1]
+		a.elements[n-1] = nil
+		a.elements = a.elements[:n-1]
+		return v
+	}
+
+	n := lengthOfArrayLike(o)
+	if n == 0 {
+		setOrThrow(o, JSString("length"), JSNumber(0))
+		return nil
+	}
+
+	v := getIndex(o, n-1)
+	deleteOrThrow(o, indexKey(n-1))
+	setOrThrow(o, JSString("length"), JSNumber(n-1))
+
+	return v
+}
+
+func arrayPrototypePush(this Object, args []Object) Object {
+	o := ToObject(this)
+	if a, ok := denseArray(o); ok {
+		a.elements = append(a.elements, args...)
+		return JSNumber(len(a.elements))
+	}
+
+	n := lengthOfArrayLike(o)
+	for _, v := range args {
+		setIndex(o, n, v)
+		n++
+	}
+	setOrThrow(o, JSString("length"), JSNumber(n))
+
+	return JSNumber(n)
+}
+
+// reduce folds the present elements, in ascending order or descending order
+func reduce(this Object, args []Object, indices func(n int64) (int64, int64, int64)) Object {
+	o := ToObject(this)
+	n := lengthOfArrayLike(o)
+	callback := requireFunction(Arg(args, 0))
+	start, end, step := indices(n)
+
+	i := start
+	var acc Object
+	if len(args) > 1 {
+		acc = args[1]
+	} else {
+		for ; i != end && !hasIndex(o, i); i += step {
+		}
+		if i == end {
+			panic(newTypeError("Reduce of empty array with no initial value"))
+		}
+
+		acc = getIndex(o, i)
+		i += step
+	}
+
+	for ; i != end; i += step {
+		if hasIndex(o, i) {
+			acc = callback.Call(nil, []Object{acc, getIndex(o, i), JSNumber(i), o})
+		}
+	}
+
+	return acc
+}
+
+func arrayPrototypeReduce(this Object, args []Object) Object {
+	return reduce(this, args, func(n int64) (int64, int64, int64) { return 0, n, 1 })
+}
+
+func arrayPrototypeReduceRight(this Object, args []Object) Object {
+	return reduce(this, args, func(n int64) (int64, int64, int64) { return n - 1, -1, -1 })
+}
+
+func arrayPrototypeReverse(this Object, args []Object) Object {
+	o := ToObject(this)
+	n := lengthOfArrayLike(o)
+	for lower, upper := int64(0), n-1; lower < upper; lower, upper = lower+1, upper-1 {
+		lowerExists, upperExists := hasIndex(o, lower), hasIndex(o, upper)
+		lowerValue, upperValue := getIndex(o, lower), getIndex(o, upper)
+
+		switch {
+		case lowerExists && upperExists:
+			setIndex(o, lower, upperValue)
+			setIndex(o, upper, lowerValue)
+		case upperExists:
+			setIndex(o, lower, upperValue)
+			deleteOrThrow(o, indexKey(upper))
+		case lowerExists:
+			deleteOrThrow(o, indexKey(lower))
+			setIndex(o, upper, lowerValue)
+		}
+	}
+
+	return o
+}
+
+func arrayPrototypeShift(this Object, args []Object) Object {
+	o := ToObject(this)
+	if a, ok := denseArray(o); ok {
+		if len(a.elements) == 0 {
+			return nil
+		}
+
+		v := a.elements[0]
+		copy(a.elements, a.elements[1:])
+		a.elements[len(a.elements)-1] = nil
+		a.elements = a.elements[:len(a.elements)-1]
+		return v
+	}
+
+	n := lengthOfArrayLike(o)
+	if n == 0 {
+		setOrThrow(o, JSString("length"), JSNumber(0))
+		return nil
+	}
+
+	first := getIndex(o, 0)
+	moveElements(o, 1, 0, n-1)
+	deleteOrThrow(o, indexKey(n-1))
+	setOrThrow(o, JSString("length"), JSNumber(n-1))
+
+	return first
+}
+
+// moveElements moves count elements from one index to another, preserving
+// holes
+func moveElements(o ObjectValue, from, to, count int64) {
+	move := func(k int64) {
+		if hasIndex(o, from+k) {
+			setIndex(o, to+k, getIndex(o, from+k))
+		} else {
+			deleteOrThrow(o, indexKey(to+k))
+		}
+	}
+
+	if from > to {
+		for k := int64(0); k < count; k++ {
+			move(k)
+		}
+	} else {
+		for k := count - 1; k >= 0; k-- {
+			move(k)
+		}
+	}
+}
+
+func arrayPrototypeSlice(this Object, args []Object) Object {
+	o := ToObject(this)
+	n := lengthOfArrayLike(o)
+	start := relativeIndex(Arg(args, 0), n, 0)
+	end := relativeIndex(Arg(args, 1), n, n)
+
+	var values []Object
+	holes := false
+	for i := start; i < end; i++ {
+		if hasIndex(o, i) {
+			values = append(values, getIndex(o, i))
+		} else {
+			values = append(values, Hole)
+			holes = true
+		}
+	}
+
+	if holes {
+		return NewArrayLiteral(values)
+	}
+
+	return NewArray(values)
+}
+
+func arrayPrototypeSome(this Object, args []Object) Object {
+	result := false
+	iterate(this, args, func(v Object, i int64, r Object) bool {
+		result = ToBoolean(r)
+		return !result
+	})
+
+	return JSBoolean(result)
+}
+
+func arrayPrototypeSort(this Object, args []Object) Object {
+	var compare *JSFunction
+	if f := Arg(args, 0); f != nil {
+		compare = requireFunction(f)
+	}
+
+	o := ToObject(this)
+	n := lengthOfArrayLike(o)
+
+	// holes are removed and undefined values are moved to the end without
+	// calling the comparator
+	var values []Object
+	undefineds := int64(0)
+	for i := int64(0); i < n; i++ {
+		if !hasIndex(o, i) {
+			continue
+		}
+
+		if v := getIndex(o, i); v == nil {
+			undefineds++
+		} else {
+			values = append(values, v)
+		}
+	}
+
+	if compare != nil {
+		sort.SliceStable(values, func(i, j int) bool {
+			return ToNumber(compare.Call(nil, []Object{values[i], values[j]})) < 0
+		})
+	} else {
+		strs := make([]string, len(values))
+		for i, v := range values {
+			strs[i] = string(ToString(v))
+		}
+		sort.Stable(stringSorter{values, strs})
+	}
+
+	i := int64(0)
+	for _, v := range values {
+		setIndex(o, i, v)
+		i++
+	}
+	for ; i < int64(len(values))+undefineds; i++ {
+		setIndex(o, i, nil)
+	}
+	for ; i < n; i++ {
+		if hasIndex(o, i) {
+			deleteOrThrow(o, indexKey(i))
+		}
+	}
+
+	return o
+}
+
+// stringSorter sorts values by their string values
+type stringSorter struct {
+	values []Object
+	strs   []string
+}
+
+func (s stringSorter) Len() int { return len(s.values) }
+
+func (s stringSorter) Less(i, j int) bool { return compareStrings(s.strs[i], s.strs[j]) < 0 }
+
+func (s stringSorter) Swap(i, j int) {
+	s.values[i], s.values[j] = s.values[j], s.values[i]
+	s.strs[i], s.strs[j] = s.strs[j], s.strs[i]
+}
+
+func arrayPrototypeSplice(this Object, args []Object) Object {
+	o := ToObject(this)
+	n := lengthOfArrayLike(o)
+	start := relativeIndex(Arg(args, 0), n, 0)
+
+	var deleteCount int64
+	switch len(args) {
+	case 0:
+	case 1:
+		deleteCount = n - start
+	default:
+		deleteCount = int64(ToIntegerOrInfinity(args[1]))
+		if deleteCount < 0 {
+			deleteCount = 0
+		}
+		if deleteCount > n-start {
+			deleteCount = n - start
+		}
+	}
+
+	var items []Object
+	if len(args) > 2 {
+		items = args[2:]
+	}
+
+	if a, ok := denseArray(o); ok {
+		removed := append([]Object(nil), a.elements[start:start+deleteCount]...)
+		tail := append([]Object(nil), a.elements[start+deleteCount:]...)
+		a.elements = append(append(a.elements[:start], items...), tail...)
+		return NewArray(removed)
+	}
+
+	var removed []Object
+	holes := false
+	for k := int64(0); k < deleteCount; k++ {
+		if hasIndex(o, start+k) {
+			removed = append(removed, getIndex(o, start+k))
+		} else {
+			removed = append(removed, Hole)
+			holes = true
+		}
+	}
+
+	itemCount := int64(len(items))
+	if itemCount < deleteCount {
+		moveElements(o, start+deleteCount, start+itemCount, n-start-deleteCount)
+		for k := n; k > n-deleteCount+itemCount; k-- {
+			deleteOrThrow(o, indexKey(k-1))
+		}
+	} else if itemCount > deleteCount {
+		moveElements(o, start+deleteCount, start+itemCount, n-start-deleteCount)
+	}
+
+	for k, v := range items {
+		setIndex(o, start+int64(k), v)
+	}
+	setOrThrow(o, JSString("length"), JSNumber(n-deleteCount+itemCount))
+
+	if holes {
+		return NewArrayLiteral(removed)
+	}
+
+	return NewArray(removed)
+}
+
+func arrayPrototypeToString(this Object, args []Object) Object {
+	o := ToObject(this)
+	if join, ok := getProperty(o, JSString("join"), o).(*JSFunction); ok {
+		return join.Call(o, nil)
+	}
+
+	return objectPrototypeToString(o, nil)
+}
+
+func arrayPrototypeUnshift(this Object, args []Object) Object {
+	o := ToObject(this)
+	if a, ok := denseArray(o); ok {
+		a.elements = append(append([]Object(nil), args...), a.elements...)
+		return JSNumber(len(a.elements))
+	}
+
+	n := lengthOfArrayLike(o)
+	count := int64(len(args))
+	if count > 0 {
+		moveElements(o, 0, count, n)
+		for k, v := range args {
+			setIndex(o, int64(k), v)
+		}
+	}
+	setOrThrow(o, JSString("length"), JSNumber(n+count))
+
+	return JSNumber(n + count)
+}
@@ -0,0 +1,142 @@
+package runtime
+
+var (
+	// errorPrototype is Error.prototype, which is an ordinary object
+	errorPrototype   = &JSObject{class: "Object", proto: objectPrototype}
+	errorConstructor = newErrorConstructor("Error", errorPrototype)
+
+	typeErrorPrototype        = &JSObject{class: "Object", proto: errorPrototype}
+	typeErrorConstructor      = newErrorConstructor("TypeError", typeErrorPrototype)
+	rangeErrorPrototype       = &JSObject{class: "Object", proto: errorPrototype}
+	rangeErrorConstructor     = newErrorConstructor("RangeError", rangeErrorPrototype)
+	referenceErrorPrototype   = &JSObject{class: "Object", proto: errorPrototype}
+	referenceErrorConstructor = newErrorConstructor("ReferenceError", referenceErrorPrototype)
+	syntaxErrorPrototype      = &JSObject{class: "Object", proto: errorPrototype}
+	syntaxErrorConstructor    = newErrorConstructor("SyntaxError", syntaxErrorPrototype)
+)
+
+func init() {
+	defineConstructor(errorConstructor, errorPrototype)
+	defineHidden(errorPrototype, "name", JSString("Error"))
+	defineHidden(errorPrototype, "message", JSString(""))
+	defineMethod(errorPrototype, "toString", 0, errorPrototypeToString)
+
+	nativeErrors := []struct {
+		constructor *JSFunction
+		prototype   *JSObject
+	}{
+		{typeErrorConstructor, typeErrorPrototype},
+		{rangeErrorConstructor, rangeErrorPrototype},
+		{referenceErrorConstructor, referenceErrorPrototype},
+		{syntaxErrorConstructor, syntaxErrorPrototype},
+	}
+	for _, e := range nativeErrors {
+		e.constructor.proto = errorConstructor
+		defineConstructor(e.constructor, e.prototype)
+		defineHidden(e.prototype, "name", JSString(e.constructor.name))
+		defineHidden(e.prototype, "message", JSString(""))
+	}
+}
+
+// errorObject is an error, whose stack is formatted when it's first used
+// like V8 does, so that it shows the name given by a subclass constructor
+type errorObject struct {
+	JSObject
+	// frames is the captured stack trace
+	frames    string
+	formatted bool
+}
+
+func (self *errorObject) getOwnProperty(key PropertyKey) *Property {
+	p := self.JSObject.getOwnProperty(key)
+	if p != nil && !self.formatted && key == JSString("stack") {
+		self.formatted = true
+		stack := ToString(errorPrototypeToString(self, nil))
+		if self.frames != "" {
+			stack += JSString("\n" + self.frames)
+		}
+		p.value = stack
+	}
+
+	return p
+}
+
+func (self *errorObject) defineOwnProperty(key PropertyKey, desc *PropertyDescriptor) bool {
+	self.getOwnProperty(key)
+	return self.JSObject.defineOwnProperty(key, desc)
+}
+
+// newErrorConstructor creates the constructor of an error type, which
+// creates an error whether it's called as a function or as a constructor
+// The stack trace starts at the caller of the constructor, or at the caller
+// of new.target for a subclass.
+func newErrorConstructor(name string, proto *JSObject) *JSFunction {
+	var c *JSFunction
+	c = NewFunction(name, 1, func(this Object, args []Object) Object {
+		return newError(proto, Arg(args, 0), Arg(args, 1), c)
+	})
+	c.construct = func(args []Object, newTarget ObjectValue) Object {
+		return newError(prototypeFromConstructor(newTarget, proto), Arg(args, 0), Arg(args, 1), newTarget)
+	}
+
+	return c
+}
+
+// newError creates an error with an optional message and options, whose
+// stack is captured from the JavaScript frames below the call of caller
+func newError(proto ObjectValue, message, options Object, caller ObjectValue) *errorObject {
+	o := &errorObject{JSObject: JSObject{class: "Error", proto: proto}}
+	defineHidden(o, "stack", JSString(""))
+	if message != Undefined {
+		defineHidden(o, "message", ToString(message))
+	}
+	if opts, ok := options.(ObjectValue); ok && hasProperty(opts, JSString("cause")) {
+		defineHidden(o, "cause", getProperty(opts, JSString("cause"), opts))
+	}
+	o.frames = captureStack(caller)
+
+	return o
+}
+
+// newTypeError creates a TypeError to be thrown by the runtime with panic
+func newTypeError(msg string) *Exception {
+	return &Exception{newError(typeErrorPrototype, JSString(msg), Undefined, nil)}
+}
+
+func newRangeError(msg string) *Exception {
+	return &Exception{newError(rangeErrorPrototype, JSString(msg), Undefined, nil)}
+}
+
+func newReferenceError(msg string) *Exception {
+	return &Exception{newError(referenceErrorPrototype, JSString(msg), Undefined, nil)}
+}
+
+func newSyntaxError(msg string) *Exception {
+	return &Exception{newError(syntaxErrorPrototype, JSString(msg), Undefined, nil)}
+}
+
+func errorPrototypeToString(this Object, args []Object) Object {
+	o, ok := this.(ObjectValue)
+	if !ok {
+		panic(newTypeError("Error.prototype.toString requires that 'this' be an Object"))
+	}
+
+	name := JSString("Error")
+	if v := getProperty(o, JSString("name"), o); v != Undefined {
+		name = ToString(v)
+	}
+
+	msg := JSString("")
+	if v := getProperty(o, JSString("message"), o); v != Undefined {
+		msg = ToString(v)
+	}
+
+	switch {
+	case name == "":
+		return msg
+	case msg == "":
+		return name
+	default:
+		return name + ": " + msg
+	}
+}
@@ -0,0 +1,63 @@
+package runtime
+
+var (
+	// iteratorPrototype is %IteratorPrototype%, which the prototypes of the
+	// builtin iterators inherit from
+	iteratorPrototype = NewObject()
+
+	arrayIteratorPrototype  = newIteratorPrototype("Array Iterator")
+	stringIteratorPrototype = newIteratorPrototype("String Iterator")
+)
+
+func init() {
+	defineHiddenKey(iteratorPrototype, SymbolIterator, NewFunction("[Symbol.iterator]", 0, func(this Object, args []Object) Object {
+		return this
+	}))
+}
+
+// iteratorObject is a builtin iterator object, like the Array Iterator
+// returned by Array.prototype.values, which steps through a Go iterator
+type iteratorObject struct {
+	JSObject
+	iterator *Iterator
+	// tag is the kind of the iterator, like "Array Iterator"
+	tag string
+	// preview returns the values the iterator hasn't visited yet for
+	// inspection, as key-value pairs for an iterator over entries, or nil
+	// if they can't be previewed
+	preview func() (values []Object, entries bool)
+}
+
+// newIteratorObject creates a builtin iterator object that inherits from
+// the prototype of its kind
+func newIteratorObject(proto *JSObject, tag string, it *Iterator) *iteratorObject {
+	return &iteratorObject{JSObject: JSObject{class: "Object", proto: proto}, iterator: it, tag: tag}
+}
+
+// newIteratorPrototype creates the prototype of a kind of builtin iterator,
+// whose next method steps through the iterators of that kind
+func newIteratorPrototype(tag string) *JSObject {
+	proto := &JSObject{class: "Object", proto: iteratorPrototype}
+	defineMethod(proto, "next", 0, func(this Object, args []Object) Object {
+		o, ok := this.(*iteratorObject)
+		if !ok || o.tag != tag {
+			panic(newTypeError("Method " + tag + ".prototype.next called on incompatible receiver " + toDisplayString(this)))
+		}
+
+		v, ok := o.iterator.Step()
+		return createIterResultObject(v, !ok)
+	})
+	defineToStringTag(proto, tag)
+
+	return proto
+}
+
+// createIterResultObject creates the result of the next method of an
+// iterator
+func createIterResultObject(v Object, done bool) *JSObject {
+	o := NewObject()
+	o.DefineProperty("value", v)
+	o.DefineProperty("done", JSBoolean(done))
+
+	return o
+}
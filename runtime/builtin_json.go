@@ -0,0 +1,111 @@
+package runtime
+
+import (
+	"math"
+	"strings"
+	"unicode/utf16"
+)
+
+// jsonObject is JSON, which holds the functions that parse and serialize
+// JSON text
+var jsonObject = &JSObject{class: "Object", proto: objectPrototype}
+
+func init() {
+	defineMethod(jsonObject, "parse", 2, JSON_Parse)
+	defineMethod(jsonObject, "stringify", 3, JSON_Stringify)
+	defineToStringTag(jsonObject, "JSON")
+}
+
+func JSON_Parse(this Object, args []Object) Object {
+	v := newJSONParser(ToString(Arg(args, 0))).parse()
+
+	reviver := Arg(args, 1)
+	if !isCallable(reviver) {
+		return v
+	}
+
+	root := NewObject()
+	createDataProperty(root, JSString(""), v)
+
+	return internalizeJSONProperty(root, JSString(""), reviver)
+}
+
+func JSON_Stringify(this Object, args []Object) Object {
+	s := &jsonSerializer{}
+	switch replacer := Arg(args, 1); {
+	case isCallable(replacer):
+		s.replacer = replacer
+	case isArray(replacer):
+		s.keys = jsonPropertyList(replacer.(*JSArray))
+	}
+	s.gap = jsonGap(Arg(args, 2))
+
+	root := NewObject()
+	createDataProperty(root, JSString(""), Arg(args, 0))
+	str, ok := s.serializeProperty(root, JSString(""))
+	if !ok {
+		return Undefined
+	}
+
+	return JSString(str)
+}
+
+// jsonPropertyList returns the keys of a replacer array, which are its
+// strings and numbers without duplicates
+func jsonPropertyList(replacer *JSArray) []PropertyKey {
+	keys := []PropertyKey{}
+	seen := make(map[JSString]bool)
+	for i := int64(0); i < lengthOfArrayLike(replacer); i++ {
+		var key JSString
+		switch v := getIndex(replacer, i).(type) {
+		case JSString:
+			key = v
+		case JSNumber:
+			key = ToString(v)
+		case ObjectValue:
+			switch v.object().class {
+			case "String", "Number":
+				key = ToString(v.object().primitive)
+			default:
+				continue
+			}
+		default:
+			continue
+		}
+
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// jsonGap returns the indentation of the space argument of JSON.stringify,
+// which is a number of spaces or a string, up to 10 characters
+func jsonGap(space Object) string {
+	if o, ok := space.(ObjectValue); ok {
+		switch o.object().class {
+		case "String", "Number":
+			space = o.object().primitive
+		}
+	}
+
+	switch space := space.(type) {
+	case JSNumber:
+		n := math.Min(10, ToIntegerOrInfinity(space))
+		if n >= 1 {
+			return strings.Repeat(" ", int(n))
+		}
+	case JSString:
+		units := utf16.Encode([]rune(string(space)))
+		if len(units) > 10 {
+			units = units[:10]
+		}
+
+		return string(utf16.Decode(units))
+	}
+
+	return ""
+}
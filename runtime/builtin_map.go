@@ -0,0 +1,183 @@
+package runtime
+
+// JSMap is a Map object, whose keys can be any values
+type JSMap struct {
+	JSObject
+	collection
+}
+
+var (
+	mapPrototype         = &JSObject{class: "Object", proto: objectPrototype}
+	mapIteratorPrototype = newIteratorPrototype("Map Iterator")
+
+	// mapConstructor is Map, which can't be called as a function
+	mapConstructor = NewFunction("Map", 0, func(this Object, args []Object) Object {
+		panic(newTypeError("Constructor Map requires 'new'"))
+	})
+
+	// mapPrototypeEntries is Map.prototype.entries, which is also the
+	// Symbol.iterator method of maps
+	mapPrototypeEntries *JSFunction
+)
+
+func init() {
+	mapConstructor.construct = constructMap
+	defineConstructor(mapConstructor, mapPrototype)
+
+	defineMethod(mapPrototype, "clear", 0, mapPrototypeClear)
+	defineMethod(mapPrototype, "delete", 1, mapPrototypeDelete)
+	mapPrototypeEntries = defineMethod(mapPrototype, "entries", 0, func(this Object, args []Object) Object {
+		return newCollectionIterator(mapIteratorPrototype, "Map Iterator", &thisMap(this, "entries").collection, "entries")
+	})
+	defineMethod(mapPrototype, "forEach", 1, mapPrototypeForEach)
+	defineMethod(mapPrototype, "get", 1, mapPrototypeGet)
+	defineMethod(mapPrototype, "has", 1, mapPrototypeHas)
+	defineMethod(mapPrototype, "keys", 0, mapPrototypeKeys)
+	defineMethod(mapPrototype, "set", 2, mapPrototypeSet)
+	defineMethod(mapPrototype, "values", 0, mapPrototypeValues)
+	defineGetter(mapPrototype, "size", mapPrototypeSize)
+	defineHiddenKey(mapPrototype, SymbolIterator, mapPrototypeEntries)
+	defineToStringTag(mapPrototype, "Map")
+}
+
+// constructMap creates a Map with the entries of an iterable, which are
+// added by its set method
+func constructMap(args []Object, newTarget ObjectValue) Object {
+	m := &JSMap{JSObject: JSObject{class: "Object", proto: prototypeFromConstructor(newTarget, mapPrototype)}}
+	if iterable := Arg(args, 0); !isNullish(iterable) {
+		addEntriesFromIterable(m, iterable, "set")
+	}
+
+	return m
+}
+
+// addEntriesFromIterable calls the adder method of a new Map or WeakMap
+// with the key and the value of each entry object of an iterable
+func addEntriesFromIterable(m ObjectValue, iterable Object, adder string) {
+	add := getAdder(m, adder)
+	it := GetIterator(iterable)
+	defer it.CloseOnThrow()
+	for {
+		entry, ok := it.Step()
+		if !ok {
+			return
+		}
+
+		if !isObject(entry) {
+			panic(newTypeError("Iterator value " + toDisplayString(entry) + " is not an entry object"))
+		}
+
+		Call(add, m, []Object{Get(entry, JSString("0")), Get(entry, JSString("1"))})
+	}
+}
+
+// getAdder returns the method of a new collection that its constructor
+// calls to add the values of an iterable
+func getAdder(o ObjectValue, name string) Object {
+	add := Get(o, JSString(name))
+	if !isCallable(add) {
+		panic(newTypeError("'" + toDisplayString(add) + "' returned for property '" + name + "' of object '" + toDisplayString(o) + "' is not a function"))
+	}
+
+	return add
+}
+
+// defineGetter adds an accessor property without a setter like the size
+// properties of the collections
+func defineGetter(o ObjectValue, name string, fn func(this Object, args []Object) Object) {
+	o.defineOwnProperty(JSString(name), &PropertyDescriptor{
+		Get:             NewFunction("get "+name, 0, fn),
+		Configurable:    true,
+		HasGet:          true,
+		HasSet:          true,
+		HasEnumerable:   true,
+		HasConfigurable: true,
+	})
+}
+
+// thisMap returns this, which must be a Map
+func thisMap(this Object, method string) *JSMap {
+	m, ok := this.(*JSMap)
+	if !ok {
+		panic(newTypeError("Method Map.prototype." + method + " called on incompatible receiver " + toDisplayString(this)))
+	}
+
+	return m
+}
+
+// forEachEntry calls a callback with the value and the key of each entry
+// of a collection, and the collection itself
+func forEachEntry(o ObjectValue, c *collection, args []Object) {
+	callback := Arg(args, 0)
+	if !isCallable(callback) {
+		panic(newTypeError(describeCallback(callback) + " is not a function"))
+	}
+
+	c.forEach(func(e *collectionEntry) {
+		Call(callback, Arg(args, 1), []Object{e.value, e.key, o})
+	})
+}
+
+// describeCallback describes a callback that isn't callable with its type,
+// like string "x", for the error messages of the collection methods
+func describeCallback(v Object) string {
+	switch v := v.(type) {
+	case nil:
+		return "undefined"
+	case null:
+		return "object null"
+	case JSString:
+		return "string \"" + string(v) + "\""
+	case *Symbol, ObjectValue:
+		return string(TypeOf(v).(JSString))
+	default:
+		return string(TypeOf(v).(JSString)) + " " + string(ToString(v))
+	}
+}
+
+func mapPrototypeClear(this Object, args []Object) Object {
+	thisMap(this, "clear").clear()
+	return Undefined
+}
+
+func mapPrototypeDelete(this Object, args []Object) Object {
+	return JSBoolean(thisMap(this, "delete").delete(Arg(args, 0)))
+}
+
+func mapPrototypeForEach(this Object, args []Object) Object {
+	m := thisMap(this, "forEach")
+	forEachEntry(m, &m.collection, args)
+
+	return Undefined
+}
+
+func mapPrototypeGet(this Object, args []Object) Object {
+	v, _ := thisMap(this, "get").get(Arg(args, 0))
+	return v
+}
+
+func mapPrototypeHas(this Object, args []Object) Object {
+	return JSBoolean(thisMap(this, "has").has(Arg(args, 0)))
+}
+
+func mapPrototypeKeys(this Object, args []Object) Object {
+	return newCollectionIterator(mapIteratorPrototype, "Map Iterator", &thisMap(this, "keys").collection, "keys")
+}
+
+func mapPrototypeSet(this Object, args []Object) Object {
+	thisMap(this, "set").set(Arg(args, 0), Arg(args, 1))
+	return this
+}
+
+func mapPrototypeValues(this Object, args []Object) Object {
+	return newCollectionIterator(mapIteratorPrototype, "Map Iterator", &thisMap(this, "values").collection, "values")
+}
+
+func mapPrototypeSize(this Object, args []Object) Object {
+	m, ok := this.(*JSMap)
+	if !ok {
+		panic(newTypeError("Method get Map.prototype.size called on incompatible receiver " + toDisplayString(this)))
+	}
+
+	return JSNumber(m.size)
+}
@@ -0,0 +1,314 @@
+package runtime
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+var (
+	// mathObject is Math, which holds the mathematical constants and functions
+	mathObject = &JSObject{class: "Object", proto: objectPrototype}
+
+	// mathRandom generates the numbers of Math.random, seeded differently on
+	// each run
+	mathRandom = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+func init() {
+	defineValue(mathObject, "E", JSNumber(math.E))
+	defineValue(mathObject, "LN10", JSNumber(math.Ln10))
+	defineValue(mathObject, "LN2", JSNumber(math.Ln2))
+	defineValue(mathObject, "LOG10E", JSNumber(math.Log10E))
+	defineValue(mathObject, "LOG2E", JSNumber(math.Log2E))
+	defineValue(mathObject, "PI", JSNumber(math.Pi))
+	defineValue(mathObject, "SQRT1_2", JSNumber(math.Sqrt2/2))
+	defineValue(mathObject, "SQRT2", JSNumber(math.Sqrt2))
+
+	defineMethod(mathObject, "abs", 1, Math_Abs)
+	defineMethod(mathObject, "acos", 1, Math_Acos)
+	defineMethod(mathObject, "acosh", 1, Math_Acosh)
+	defineMethod(mathObject, "asin", 1, Math_Asin)
+	defineMethod(mathObject, "asinh", 1, Math_Asinh)
+	defineMethod(mathObject, "atan", 1, Math_Atan)
+	defineMethod(mathObject, "atanh", 1, Math_Atanh)
+	defineMethod(mathObject, "atan2", 2, Math_Atan2)
+	defineMethod(mathObject, "cbrt", 1, Math_Cbrt)
+	defineMethod(mathObject, "ceil", 1, Math_Ceil)
+	defineMethod(mathObject, "clz32", 1, Math_Clz32)
+	defineMethod(mathObject, "cos", 1, Math_Cos)
+	defineMethod(mathObject, "cosh", 1, Math_Cosh)
+	defineMethod(mathObject, "exp", 1, Math_Exp)
+	defineMethod(mathObject, "expm1", 1, Math_Expm1)
+	defineMethod(mathObject, "floor", 1, Math_Floor)
+	defineMethod(mathObject, "fround", 1, Math_Fround)
+	defineMethod(mathObject, "hypot", 2, Math_Hypot)
+	defineMethod(mathObject, "imul", 2, Math_Imul)
+	defineMethod(mathObject, "log", 1, Math_Log)
+	defineMethod(mathObject, "log1p", 1, Math_Log1p)
+	defineMethod(mathObject, "log10", 1, Math_Log10)
+	defineMethod(mathObject, "log2", 1, Math_Log2)
+	defineMethod(mathObject, "max", 2, Math_Max)
+	defineMethod(mathObject, "min", 2, Math_Min)
+	defineMethod(mathObject, "pow", 2, Math_Pow)
+	defineMethod(mathObject, "random", 0, Math_Random)
+	defineMethod(mathObject, "round", 1, Math_Round)
+	defineMethod(mathObject, "sign", 1, Math_Sign)
+	defineMethod(mathObject, "sin", 1, Math_Sin)
+	defineMethod(mathObject, "sinh", 1, Math_Sinh)
+	defineMethod(mathObject, "sqrt", 1, Math_Sqrt)
+	defineMethod(mathObject, "tan", 1, Math_Tan)
+	defineMethod(mathObject, "tanh", 1, Math_Tanh)
+	defineMethod(mathObject, "trunc", 1, Math_Trunc)
+	defineToStringTag(mathObject, "Math")
+}
+
+// numberArg returns the i-th argument converted to a number
+func numberArg(args []Object, i int) float64 {
+	return float64(ToNumber(Arg(args, i)))
+}
+
+// numberArgs returns all the arguments converted to numbers, in order
+func numberArgs(args []Object) []float64 {
+	numbers := make([]float64, len(args))
+	for i, v := range args {
+		numbers[i] = float64(ToNumber(v))
+	}
+
+	return numbers
+}
+
+func Math_Abs(this Object, args []Object) Object {
+	return JSNumber(math.Abs(numberArg(args, 0)))
+}
+
+func Math_Acos(this Object, args []Object) Object {
+	return JSNumber(fdlibmAcos(numberArg(args, 0)))
+}
+
+func Math_Acosh(this Object, args []Object) Object {
+	return JSNumber(fdlibmAcosh(numberArg(args, 0)))
+}
+
+func Math_Asin(this Object, args []Object) Object {
+	return JSNumber(fdlibmAsin(numberArg(args, 0)))
+}
+
+func Math_Asinh(this Object, args []Object) Object {
+	return JSNumber(fdlibmAsinh(numberArg(args, 0)))
+}
+
+func Math_Atan(this Object, args []Object) Object {
+	return JSNumber(fdlibmAtan(numberArg(args, 0)))
+}
+
+func Math_Atanh(this Object, args []Object) Object {
+	return JSNumber(fdlibmAtanh(numberArg(args, 0)))
+}
+
+func Math_Atan2(this Object, args []Object) Object {
+	y := numberArg(args, 0)
+	return JSNumber(fdlibmAtan2(y, numberArg(args, 1)))
+}
+
+func Math_Cbrt(this Object, args []Object) Object {
+	return JSNumber(fdlibmCbrt(numberArg(args, 0)))
+}
+
+func Math_Ceil(this Object, args []Object) Object {
+	return JSNumber(math.Ceil(numberArg(args, 0)))
+}
+
+// Math_Clz32 counts the leading zero bits of a number as a 32-bit integer
+func Math_Clz32(this Object, args []Object) Object {
+	n := 0
+	for x := ToUint32(Arg(args, 0)); n < 32 && x&(1<<31) == 0; x <<= 1 {
+		n++
+	}
+
+	return JSNumber(n)
+}
+
+func Math_Cos(this Object, args []Object) Object {
+	return JSNumber(fdlibmCos(numberArg(args, 0)))
+}
+
+func Math_Cosh(this Object, args []Object) Object {
+	return JSNumber(fdlibmCosh(numberArg(args, 0)))
+}
+
+func Math_Exp(this Object, args []Object) Object {
+	return JSNumber(fdlibmExp(numberArg(args, 0)))
+}
+
+func Math_Expm1(this Object, args []Object) Object {
+	return JSNumber(math.Expm1(numberArg(args, 0)))
+}
+
+func Math_Floor(this Object, args []Object) Object {
+	return JSNumber(math.Floor(numberArg(args, 0)))
+}
+
+// Math_Fround rounds a number to the nearest single precision float
+func Math_Fround(this Object, args []Object) Object {
+	return JSNumber(float32(numberArg(args, 0)))
+}
+
+// Math_Hypot is the square root of the sum of the squares of the arguments,
+// which are scaled by the largest one so that the squares don't overflow
+// The squares are added with Kahan summation, like V8 does.
+func Math_Hypot(this Object, args []Object) Object {
+	numbers := numberArgs(args)
+	largest, hasNaN := 0.0, false
+	for i, n := range numbers {
+		if math.IsNaN(n) {
+			hasNaN = true
+			continue
+		}
+
+		numbers[i] = math.Abs(n)
+		largest = math.Max(largest, numbers[i])
+	}
+
+	switch {
+	case math.IsInf(largest, 1):
+		return JSNumber(math.Inf(1))
+	case hasNaN:
+		return JSNumber(math.NaN())
+	case largest == 0:
+		return JSNumber(0)
+	}
+
+	sum, compensation := 0.0, 0.0
+	for _, n := range numbers {
+		n /= largest
+		summand := n*n - compensation
+		preliminary := sum + summand
+		compensation = (preliminary - sum) - summand
+		sum = preliminary
+	}
+
+	return JSNumber(math.Sqrt(sum) * largest)
+}
+
+// Math_Imul is the 32-bit integer product of two numbers, which wraps
+// around like the multiplication in C
+func Math_Imul(this Object, args []Object) Object {
+	a := ToInt32(Arg(args, 0))
+	return JSNumber(a * ToInt32(Arg(args, 1)))
+}
+
+func Math_Log(this Object, args []Object) Object {
+	return JSNumber(fdlibmLog(numberArg(args, 0)))
+}
+
+func Math_Log1p(this Object, args []Object) Object {
+	return JSNumber(math.Log1p(numberArg(args, 0)))
+}
+
+func Math_Log10(this Object, args []Object) Object {
+	return JSNumber(fdlibmLog10(numberArg(args, 0)))
+}
+
+func Math_Log2(this Object, args []Object) Object {
+	return JSNumber(fdlibmLog2(numberArg(args, 0)))
+}
+
+// Math_Max returns the largest argument, or -Infinity without arguments
+// All the arguments are converted before NaN is returned for any of them,
+// and +0 is larger than -0.
+func Math_Max(this Object, args []Object) Object {
+	result := math.Inf(-1)
+	for _, n := range numberArgs(args) {
+		switch {
+		case math.IsNaN(result):
+		case math.IsNaN(n), n > result, n == 0 && result == 0 && !math.Signbit(n):
+			result = n
+		}
+	}
+
+	return JSNumber(result)
+}
+
+// Math_Min returns the smallest argument, or Infinity without arguments
+// All the arguments are converted before NaN is returned for any of them,
+// and -0 is smaller than +0.
+func Math_Min(this Object, args []Object) Object {
+	result := math.Inf(1)
+	for _, n := range numberArgs(args) {
+		switch {
+		case math.IsNaN(result):
+		case math.IsNaN(n), n < result, n == 0 && result == 0 && math.Signbit(n):
+			result = n
+		}
+	}
+
+	return JSNumber(result)
+}
+
+func Math_Pow(this Object, args []Object) Object {
+	x := numberArg(args, 0)
+	return JSNumber(pow(x, numberArg(args, 1)))
+}
+
+func Math_Random(this Object, args []Object) Object {
+	return JSNumber(mathRandom.Float64())
+}
+
+// Math_Round rounds a number to the nearest integer, and rounds halves up
+// towards +Infinity, so that Math.round(-0.5) is -0
+func Math_Round(this Object, args []Object) Object {
+	x := numberArg(args, 0)
+	if math.IsInf(x, 0) || math.Abs(x) >= 1<<52 {
+		return JSNumber(x)
+	}
+
+	r := math.Floor(x)
+	if x-r >= 0.5 {
+		r++
+	}
+	if r == 0 {
+		// a number between -0.5 and -0 rounds to -0
+		r = math.Copysign(0, x)
+	}
+
+	return JSNumber(r)
+}
+
+// Math_Sign returns 1 or -1 by the sign of a number, which is returned
+// itself if it's a zero or NaN
+func Math_Sign(this Object, args []Object) Object {
+	x := numberArg(args, 0)
+	switch {
+	case x > 0:
+		return JSNumber(1)
+	case x < 0:
+		return JSNumber(-1)
+	}
+
+	return JSNumber(x)
+}
+
+func Math_Sin(this Object, args []Object) Object {
+	return JSNumber(fdlibmSin(numberArg(args, 0)))
+}
+
+func Math_Sinh(this Object, args []Object) Object {
+	return JSNumber(fdlibmSinh(numberArg(args, 0)))
+}
+
+func Math_Sqrt(this Object, args []Object) Object {
+	return JSNumber(math.Sqrt(numberArg(args, 0)))
+}
+
+func Math_Tan(this Object, args []Object) Object {
+	return JSNumber(fdlibmTan(numberArg(args, 0)))
+}
+
+func Math_Tanh(this Object, args []Object) Object {
+	return JSNumber(fdlibmTanh(numberArg(args, 0)))
+}
+
+func Math_Trunc(this Object, args []Object) Object {
+	return JSNumber(math.Trunc(numberArg(args, 0)))
+}
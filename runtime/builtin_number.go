@@ -0,0 +1,179 @@
+package runtime
+
+import "math"
+
+var (
+	// numberPrototype is Number.prototype, which is itself a Number wrapper
+	// object of 0
+	numberPrototype = &JSObject{class: "Number", proto: objectPrototype, primitive: JSNumber(0)}
+
+	// numberConstructor is Number, which converts a value to a number when
+	// it's called and wraps it when it's constructed
+	numberConstructor = NewFunction("Number", 1, func(this Object, args []Object) Object {
+		if len(args) == 0 {
+			return JSNumber(0)
+		}
+
+		return ToNumber(args[0])
+	})
+
+	// numberParseFloat and numberParseInt are Number.parseFloat and
+	// Number.parseInt, which are also the global parseFloat and parseInt
+	numberParseFloat *JSFunction
+	numberParseInt   *JSFunction
+)
+
+func init() {
+	numberConstructor.construct = constructNumber
+	defineConstructor(numberConstructor, numberPrototype)
+	defineValue(numberConstructor, "EPSILON", JSNumber(1.0/(1<<52)))
+	defineValue(numberConstructor, "MAX_SAFE_INTEGER", JSNumber(1<<53-1))
+	defineValue(numberConstructor, "MAX_VALUE", JSNumber(math.MaxFloat64))
+	defineValue(numberConstructor, "MIN_SAFE_INTEGER", JSNumber(-(1<<53 - 1)))
+	defineValue(numberConstructor, "MIN_VALUE", JSNumber(math.SmallestNonzeroFloat64))
+	defineValue(numberConstructor, "NaN", JSNumber(math.NaN()))
+	defineValue(numberConstructor, "NEGATIVE_INFINITY", JSNumber(math.Inf(-1)))
+	defineValue(numberConstructor, "POSITIVE_INFINITY", Infinity)
+	defineMethod(numberConstructor, "isFinite", 1, Number_IsFinite)
+	defineMethod(numberConstructor, "isInteger", 1, Number_IsInteger)
+	defineMethod(numberConstructor, "isNaN", 1, Number_IsNaN)
+	defineMethod(numberConstructor, "isSafeInteger", 1, Number_IsSafeInteger)
+	numberParseFloat = defineMethod(numberConstructor, "parseFloat", 1, Number_ParseFloat)
+	numberParseInt = defineMethod(numberConstructor, "parseInt", 2, Number_ParseInt)
+
+	defineMethod(numberPrototype, "toExponential", 1, numberPrototypeToExponential)
+	defineMethod(numberPrototype, "toFixed", 1, numberPrototypeToFixed)
+	defineMethod(numberPrototype, "toPrecision", 1, numberPrototypeToPrecision)
+	defineMethod(numberPrototype, "toString", 1, numberPrototypeToString)
+	defineMethod(numberPrototype, "valueOf", 0, numberPrototypeValueOf)
+}
+
+// constructNumber creates a Number wrapper object of the number that Number
+// converts its argument to
+func constructNumber(args []Object, newTarget ObjectValue) Object {
+	n := numberConstructor.fn(Undefined, args)
+	return &JSObject{class: "Number", proto: prototypeFromConstructor(newTarget, numberPrototype), primitive: n}
+}
+
+// thisNumberValue returns the number of this, which is either a number or a
+// Number wrapper object
+func thisNumberValue(this Object, method string) float64 {
+	switch v := this.(type) {
+	case JSNumber:
+		return float64(v)
+	case *JSObject:
+		if n, ok := v.primitive.(JSNumber); ok {
+			return float64(n)
+		}
+	}
+
+	panic(newTypeError(method + " requires that 'this' be a Number"))
+}
+
+// isInteger tells if a value is a number without a fraction
+func isInteger(v Object) bool {
+	n, ok := v.(JSNumber)
+	return ok && !math.IsInf(float64(n), 0) && math.Trunc(float64(n)) == float64(n)
+}
+
+func Number_IsFinite(this Object, args []Object) Object {
+	n, ok := Arg(args, 0).(JSNumber)
+	return JSBoolean(ok && !math.IsInf(float64(n), 0) && !math.IsNaN(float64(n)))
+}
+
+func Number_IsInteger(this Object, args []Object) Object {
+	return JSBoolean(isInteger(Arg(args, 0)))
+}
+
+func Number_IsNaN(this Object, args []Object) Object {
+	n, ok := Arg(args, 0).(JSNumber)
+	return JSBoolean(ok && math.IsNaN(float64(n)))
+}
+
+func Number_IsSafeInteger(this Object, args []Object) Object {
+	v := Arg(args, 0)
+	return JSBoolean(isInteger(v) && math.Abs(float64(v.(JSNumber))) <= 1<<53-1)
+}
+
+func Number_ParseFloat(this Object, args []Object) Object {
+	return JSNumber(parseFloat(string(ToString(Arg(args, 0)))))
+}
+
+func Number_ParseInt(this Object, args []Object) Object {
+	s := ToString(Arg(args, 0))
+	return JSNumber(parseInt(string(s), ToInt32(Arg(args, 1))))
+}
+
+// numberPrototypeToExponential writes a number in exponential notation with
+// the given number of digits after the decimal point, or as many as it takes
+// to tell the number apart
+func numberPrototypeToExponential(this Object, args []Object) Object {
+	x := thisNumberValue(this, "Number.prototype.toExponential")
+	f := ToIntegerOrInfinity(Arg(args, 0))
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		return JSString(numberToString(x))
+	}
+	if f < 0 || f > 100 {
+		panic(newRangeError("toExponential() argument must be between 0 and 100"))
+	}
+	if Arg(args, 0) == Undefined {
+		return JSString(numberToExponential(x, -1))
+	}
+
+	return JSString(numberToExponential(x, int(f)))
+}
+
+// numberPrototypeToFixed writes a number in fixed-point notation with the
+// given number of digits after the decimal point
+func numberPrototypeToFixed(this Object, args []Object) Object {
+	x := thisNumberValue(this, "Number.prototype.toFixed")
+	f := ToIntegerOrInfinity(Arg(args, 0))
+	if f < 0 || f > 100 {
+		panic(newRangeError("toFixed() digits argument must be between 0 and 100"))
+	}
+	if math.IsNaN(x) || math.Abs(x) >= 1e21 {
+		return JSString(numberToString(x))
+	}
+
+	return JSString(numberToFixed(x, int(f)))
+}
+
+// numberPrototypeToPrecision writes a number with the given number of
+// significant digits, in exponential notation if its exponent is too small
+// or too large for them
+func numberPrototypeToPrecision(this Object, args []Object) Object {
+	x := thisNumberValue(this, "Number.prototype.toPrecision")
+	if Arg(args, 0) == Undefined {
+		return JSString(numberToString(x))
+	}
+
+	p := ToIntegerOrInfinity(Arg(args, 0))
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		return JSString(numberToString(x))
+	}
+	if p < 1 || p > 100 {
+		panic(newRangeError("toPrecision() argument must be between 1 and 100"))
+	}
+
+	return JSString(numberToPrecision(x, int(p)))
+}
+
+func numberPrototypeToString(this Object, args []Object) Object {
+	x := thisNumberValue(this, "Number.prototype.toString")
+	radix := 10.0
+	if r := Arg(args, 0); r != Undefined {
+		radix = ToIntegerOrInfinity(r)
+	}
+	if radix < 2 || radix > 36 {
+		panic(newRangeError("toString() radix argument must be between 2 and 36"))
+	}
+	if radix == 10 {
+		return JSString(numberToString(x))
+	}
+
+	return JSString(numberToRadixString(x, int(radix)))
+}
+
+func numberPrototypeValueOf(this Object, args []Object) Object {
+	return JSNumber(thisNumberValue(this, "Number.prototype.valueOf"))
+}
@@ -0,0 +1,521 @@
+package runtime
+
+import "fmt"
+
+var (
+	// objectPrototype is Object.prototype, the end of most prototype chains
+	objectPrototype = &JSObject{class: "Object"}
+	// functionPrototype is Function.prototype, which is itself a function
+	// that accepts any arguments and returns undefined
+	functionPrototype = &JSFunction{
+		JSObject: JSObject{class: "Function", proto: objectPrototype},
+		fn:       func(this Object, args []Object) Object { return nil },
+	}
+
+	// functionPrototypeHasInstance is Function.prototype[Symbol.hasInstance],
+	// which instanceof doesn't need to call
+	functionPrototypeHasInstance = NewFunction("[Symbol.hasInstance]", 1, func(this Object, args []Object) Object {
+		f, ok := this.(*JSFunction)
+		return JSBoolean(ok && ordinaryHasInstance(f, Arg(args, 0)))
+	})
+
+	objectConstructor = NewFunction("Object", 1, func(this Object, args []Object) Object {
+		v := Arg(args, 0)
+		if v == nil {
+			return NewObject()
+		}
+
+		return ToObject(v)
+	})
+)
+
+func init() {
+	defineConstructor(objectConstructor, objectPrototype)
+	defineMethod(objectConstructor, "assign", 2, Object_Assign)
+	defineMethod(objectConstructor, "create", 2, Object_Create)
+	defineMethod(objectConstructor, "defineProperties", 2, Object_DefineProperties)
+	defineMethod(objectConstructor, "defineProperty", 3, Object_DefineProperty)
+	defineMethod(objectConstructor, "freeze", 1, Object_Freeze)
+	defineMethod(objectConstructor, "entries", 1, Object_Entries)
+	defineMethod(objectConstructor, "getOwnPropertyDescriptor", 2, Object_GetOwnPropertyDescriptor)
+	defineMethod(objectConstructor, "getOwnPropertyNames", 1, Object_GetOwnPropertyNames)
+	defineMethod(objectConstructor, "getOwnPropertySymbols", 1, Object_GetOwnPropertySymbols)
+	defineMethod(objectConstructor, "getPrototypeOf", 1, Object_GetPrototypeOf)
+	defineMethod(objectConstructor, "is", 2, Object_Is)
+	defineMethod(objectConstructor, "isExtensible", 1, Object_IsExtensible)
+	defineMethod(objectConstructor, "isFrozen", 1, Object_IsFrozen)
+	defineMethod(objectConstructor, "isSealed", 1, Object_IsSealed)
+	defineMethod(objectConstructor, "keys", 1, Object_Keys)
+	defineMethod(objectConstructor, "preventExtensions", 1, Object_PreventExtensions)
+	defineMethod(objectConstructor, "seal", 1, Object_Seal)
+	defineMethod(objectConstructor, "setPrototypeOf", 2, Object_SetPrototypeOf)
+	defineMethod(objectConstructor, "values", 1, Object_Values)
+
+	defineMethod(objectPrototype, "hasOwnProperty", 1, objectPrototypeHasOwnProperty)
+	defineMethod(objectPrototype, "isPrototypeOf", 1, objectPrototypeIsPrototypeOf)
+	defineMethod(objectPrototype, "propertyIsEnumerable", 1, objectPrototypePropertyIsEnumerable)
+	defineMethod(objectPrototype, "toLocaleString", 0, objectPrototypeToLocaleString)
+	defineMethod(objectPrototype, "toString", 0, objectPrototypeToString)
+	defineMethod(objectPrototype, "valueOf", 0, objectPrototypeValueOf)
+
+	functionPrototype.defineOwnProperty(SymbolHasInstance, &PropertyDescriptor{Value: functionPrototypeHasInstance, HasValue: true, HasWritable: true, HasEnumerable: true, HasConfigurable: true})
+
+	defineConstructor(NewFunction("Function", 1, func(this Object, args []Object) Object {
+		panic(newTypeError("Code generation from strings disallowed for this context"))
+	}), functionPrototype)
+}
+
+// defineMethod adds a builtin method, which is writable, configurable and
+// not enumerable like the methods of builtin prototypes
+func defineMethod(o ObjectValue, name string, length int, fn func(this Object, args []Object) Object) *JSFunction {
+	f := NewFunction(name, length, fn)
+	defineHidden(o, name, f)
+
+	return f
+}
+
+// defineHidden adds a writable and configurable data property that isn't
+// enumerable
+func defineHidden(o ObjectValue, name string, v Object) {
+	defineHiddenKey(o, JSString(name), v)
+}
+
+func defineHiddenKey(o ObjectValue, key PropertyKey, v Object) {
+	o.defineOwnProperty(key, &PropertyDescriptor{
+		Value:           v,
+		Writable:        true,
+		Configurable:    true,
+		HasValue:        true,
+		HasWritable:     true,
+		HasEnumerable:   true,
+		HasConfigurable: true,
+	})
+}
+
+// defineToStringTag adds the Symbol.toStringTag property of a builtin
+// prototype, which Object.prototype.toString reports
+func defineToStringTag(o ObjectValue, tag string) {
+	o.defineOwnProperty(SymbolToStringTag, &PropertyDescriptor{Value: JSString(tag), Configurable: true, HasValue: true, HasWritable: true, HasEnumerable: true, HasConfigurable: true})
+}
+
+// defineConstructor links a builtin constructor with its prototype object
+// A builtin constructor can be constructed as well as called.
+func defineConstructor(c *JSFunction, proto ObjectValue) {
+	if c.construct == nil {
+		c.construct = c.constructBuiltin
+	}
+	linkPrototype(c, proto)
+}
+
+// linkPrototype adds the prototype property of a builtin function and the
+// constructor property of its prototype
+func linkPrototype(c *JSFunction, proto ObjectValue) {
+	c.defineOwnProperty(JSString("prototype"), &PropertyDescriptor{Value: proto, HasValue: true, HasWritable: true, HasEnumerable: true, HasConfigurable: true})
+	proto.defineOwnProperty(JSString("constructor"), &PropertyDescriptor{Value: c, Writable: true, Configurable: true, HasValue: true, HasWritable: true, HasEnumerable: true, HasConfigurable: true})
+}
+
+// definePropertyOrThrow defines a property and fails if it can't be defined
+func definePropertyOrThrow(o ObjectValue, key PropertyKey, desc *PropertyDescriptor) {
+	if !o.defineOwnProperty(key, desc) {
+		if o.object().nonExtensible && o.getOwnProperty(key) == nil {
+			panic(newTypeError(fmt.Sprintf("Cannot define property %s, object is not extensible", toDisplayString(key))))
+		}
+
+		panic(newTypeError("Cannot redefine property: " + toDisplayString(key)))
+	}
+}
+
+// ToPropertyDescriptor converts an object to a property descriptor
+func ToPropertyDescriptor(v Object) *PropertyDescriptor {
+	o, ok := v.(ObjectValue)
+	if !ok {
+		panic(newTypeError("Property description must be an object: " + toDisplayString(v)))
+	}
+
+	desc := &PropertyDescriptor{}
+	if hasProperty(o, JSString("enumerable")) {
+		desc.Enumerable = ToBoolean(getProperty(o, JSString("enumerable"), o))
+		desc.HasEnumerable = true
+	}
+	if hasProperty(o, JSString("configurable")) {
+		desc.Configurable = ToBoolean(getProperty(o, JSString("configurable"), o))
+		desc.HasConfigurable = true
+	}
+	if hasProperty(o, JSString("value")) {
+		desc.Value = getProperty(o, JSString("value"), o)
+		desc.HasValue = true
+	}
+	if hasProperty(o, JSString("writable")) {
+		desc.Writable = ToBoolean(getProperty(o, JSString("writable"), o))
+		desc.HasWritable = true
+	}
+	if hasProperty(o, JSString("get")) {
+		desc.Get = getProperty(o, JSString("get"), o)
+		if _, ok := desc.Get.(*JSFunction); !ok && desc.Get != nil {
+			panic(newTypeError("Getter must be a function: " + toDisplayString(desc.Get)))
+		}
+		desc.HasGet = true
+	}
+	if hasProperty(o, JSString("set")) {
+		desc.Set = getProperty(o, JSString("set"), o)
+		if _, ok := desc.Set.(*JSFunction); !ok && desc.Set != nil {
+			panic(newTypeError("Setter must be a function: " + toDisplayString(desc.Set)))
+		}
+		desc.HasSet = true
+	}
+
+	if desc.isAccessor() && desc.isData() {
+		panic(newTypeError("Invalid property descriptor. Cannot both specify accessors and a value or writable attribute"))
+	}
+
+	return desc
+}
+
+// fromProperty converts a property to a property descriptor object
+func fromProperty(p *Property) Object {
+	if p == nil {
+		return nil
+	}
+
+	o := NewObject()
+	if p.accessor {
+		o.DefineProperty("get", p.getter)
+		o.DefineProperty("set", p.setter)
+	} else {
+		o.DefineProperty("value", p.value)
+		o.DefineProperty("writable", JSBoolean(p.writable))
+	}
+	o.DefineProperty("enumerable", JSBoolean(p.enumerable))
+	o.DefineProperty("configurable", JSBoolean(p.configurable))
+
+	return o
+}
+
+// requireObject returns the argument of an Object function that only
+// accepts objects
+func requireObject(v Object, method string) ObjectValue {
+	o, ok := v.(ObjectValue)
+	if !ok {
+		panic(newTypeError(method + " called on non-object"))
+	}
+
+	return o
+}
+
+// setIntegrityLevel seals or freezes an object
+func setIntegrityLevel(o ObjectValue, frozen bool) {
+	o.object().nonExtensible = true
+	for _, k := range o.ownKeys() {
+		desc := &PropertyDescriptor{HasConfigurable: true}
+		if p := o.getOwnProperty(k); frozen && p != nil && !p.accessor {
+			desc.HasWritable = true
+		}
+		definePropertyOrThrow(o, k, desc)
+	}
+}
+
+// testIntegrityLevel tells whether an object is sealed or frozen
+func testIntegrityLevel(o ObjectValue, frozen bool) bool {
+	if !o.object().nonExtensible {
+		return false
+	}
+
+	for _, k := range o.ownKeys() {
+		p := o.getOwnProperty(k)
+		if p.configurable || (frozen && !p.accessor && p.writable) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func Object_Assign(this Object, args []Object) Object {
+	to := ToObject(Arg(args, 0))
+	for _, v := range args[1:] {
+		if isNullish(v) {
+			continue
+		}
+
+		from := ToObject(v)
+		for _, k := range from.ownKeys() {
+			if p := from.getOwnProperty(k); p != nil && p.enumerable {
+				if !setProperty(to, k, p.get(from), to) {
+					panic(newTypeError(fmt.Sprintf("Cannot assign to read only property '%s' of object '%s'", toDisplayString(k), toDisplayString(to))))
+				}
+			}
+		}
+	}
+
+	return to
+}
+
+func Object_Create(this Object, args []Object) Object {
+	proto, ok := toPrototype(Arg(args, 0))
+	if !ok {
+		panic(newTypeError("Object prototype may only be an Object or null: " + toDisplayString(Arg(args, 0))))
+	}
+
+	o := NewObject()
+	o.proto = proto
+	if props := Arg(args, 1); props != nil {
+		Object_DefineProperties(nil, []Object{o, props})
+	}
+
+	return o
+}
+
+func Object_DefineProperties(this Object, args []Object) Object {
+	o := requireObject(Arg(args, 0), "Object.defineProperties")
+	props := ToObject(Arg(args, 1))
+
+	type pending struct {
+		key  PropertyKey
+		desc *PropertyDescriptor
+	}
+	var descriptors []pending
+	for _, k := range props.ownKeys() {
+		if p := props.getOwnProperty(k); p != nil && p.enumerable {
+			descriptors = append(descriptors, pending{k, ToPropertyDescriptor(p.get(props))})
+		}
+	}
+
+	for _, d := range descriptors {
+		definePropertyOrThrow(o, d.key, d.desc)
+	}
+
+	return o
+}
+
+func Object_DefineProperty(this Object, args []Object) Object {
+	o := requireObject(Arg(args, 0), "Object.defineProperty")
+	key := ToPropertyKey(Arg(args, 1))
+	definePropertyOrThrow(o, key, ToPropertyDescriptor(Arg(args, 2)))
+
+	return o
+}
+
+// enumerableOwnProperties returns the keys, values or entries of the
+// enumerable own string-keyed properties of an object
+func enumerableOwnProperties(v Object, kind string) Object {
+	o := ToObject(v)
+
+	var result []Object
+	for _, k := range o.ownKeys() {
+		if _, ok := k.(JSString); !ok {
+			continue
+		}
+
+		p := o.getOwnProperty(k)
+		if p == nil || !p.enumerable {
+			continue
+		}
+
+		switch kind {
+		case "keys":
+			result = append(result, k)
+		case "values":
+			result = append(result, p.get(o))
+		default:
+			result = append(result, NewArray([]Object{k, p.get(o)}))
+		}
+	}
+
+	return NewArray(result)
+}
+
+func Object_Entries(this Object, args []Object) Object {
+	return enumerableOwnProperties(Arg(args, 0), "entries")
+}
+
+func Object_Freeze(this Object, args []Object) Object {
+	if o, ok := Arg(args, 0).(ObjectValue); ok {
+		setIntegrityLevel(o, true)
+	}
+
+	return Arg(args, 0)
+}
+
+func Object_GetOwnPropertyDescriptor(this Object, args []Object) Object {
+	o := ToObject(Arg(args, 0))
+	return fromProperty(o.getOwnProperty(ToPropertyKey(Arg(args, 1))))
+}
+
+func Object_GetOwnPropertyNames(this Object, args []Object) Object {
+	var names []Object
+	for _, k := range ToObject(Arg(args, 0)).ownKeys() {
+		if _, ok := k.(JSString); ok {
+			names = append(names, k)
+		}
+	}
+
+	return NewArray(names)
+}
+
+func Object_GetOwnPropertySymbols(this Object, args []Object) Object {
+	var symbols []Object
+	for _, k := range ToObject(Arg(args, 0)).ownKeys() {
+		if s, ok := k.(*Symbol); ok {
+			symbols = append(symbols, s)
+		}
+	}
+
+	return NewArray(symbols)
+}
+
+func Object_GetPrototypeOf(this Object, args []Object) Object {
+	proto := ToObject(Arg(args, 0)).object().proto
+	if proto == nil {
+		return Null
+	}
+
+	return proto
+}
+
+func Object_Is(this Object, args []Object) Object {
+	return JSBoolean(SameValue(Arg(args, 0), Arg(args, 1)))
+}
+
+func Object_IsExtensible(this Object, args []Object) Object {
+	o, ok := Arg(args, 0).(ObjectValue)
+	return JSBoolean(ok && !o.object().nonExtensible)
+}
+
+func Object_IsFrozen(this Object, args []Object) Object {
+	o, ok := Arg(args, 0).(ObjectValue)
+	return JSBoolean(!ok || testIntegrityLevel(o, true))
+}
+
+func Object_IsSealed(this Object, args []Object) Object {
+	o, ok := Arg(args, 0).(ObjectValue)
+	return JSBoolean(!ok || testIntegrityLevel(o, false))
+}
+
+func Object_Keys(this Object, args []Object) Object {
+	return enumerableOwnProperties(Arg(args, 0), "keys")
+}
+
+func Object_PreventExtensions(this Object, args []Object) Object {
+	if o, ok := Arg(args, 0).(ObjectValue); ok {
+		o.object().nonExtensible = true
+	}
+
+	return Arg(args, 0)
+}
+
+func Object_Seal(this Object, args []Object) Object {
+	if o, ok := Arg(args, 0).(ObjectValue); ok {
+		setIntegrityLevel(o, false)
+	}
+
+	return Arg(args, 0)
+}
+
+func Object_SetPrototypeOf(this Object, args []Object) Object {
+	v := Arg(args, 0)
+	if isNullish(v) {
+		panic(newTypeError("Object.setPrototypeOf called on null or undefined"))
+	}
+
+	proto, ok := toPrototype(Arg(args, 1))
+	if !ok {
+		panic(newTypeError("Object prototype may only be an Object or null: " + toDisplayString(Arg(args, 1))))
+	}
+
+	if o, ok := v.(ObjectValue); ok && !setPrototypeOf(o, proto) {
+		panic(newTypeError("Cyclic __proto__ value"))
+	}
+
+	return v
+}
+
+// toPrototype converts an object or null to a prototype, which is nil for null
+func toPrototype(v Object) (ObjectValue, bool) {
+	if v == Null {
+		return nil, true
+	}
+
+	o, ok := v.(ObjectValue)
+	return o, ok
+}
+
+// setPrototypeOf implements OrdinarySetPrototypeOf, which refuses to create
+// a cycle in the prototype chain or to change a non-extensible object
+func setPrototypeOf(o ObjectValue, proto ObjectValue) bool {
+	obj := o.object()
+	if obj.proto == proto {
+		return true
+	}
+	if obj.nonExtensible {
+		return false
+	}
+
+	for p := proto; p != nil; p = p.object().proto {
+		if p == o {
+			return false
+		}
+	}
+
+	obj.proto = proto
+	return true
+}
+
+func Object_Values(this Object, args []Object) Object {
+	return enumerableOwnProperties(Arg(args, 0), "values")
+}
+
+func objectPrototypeHasOwnProperty(this Object, args []Object) Object {
+	key := ToPropertyKey(Arg(args, 0))
+	return JSBoolean(ToObject(this).getOwnProperty(key) != nil)
+}
+
+func objectPrototypeIsPrototypeOf(this Object, args []Object) Object {
+	v, ok := Arg(args, 0).(ObjectValue)
+	if !ok {
+		return JSBoolean(false)
+	}
+
+	o := ToObject(this)
+	for p := v.object().proto; p != nil; p = p.object().proto {
+		if p == o {
+			return JSBoolean(true)
+		}
+	}
+
+	return JSBoolean(false)
+}
+
+func objectPrototypePropertyIsEnumerable(this Object, args []Object) Object {
+	key := ToPropertyKey(Arg(args, 0))
+	p := ToObject(this).getOwnProperty(key)
+	return JSBoolean(p != nil && p.enumerable)
+}
+
+func objectPrototypeToLocaleString(this Object, args []Object) Object {
+	return Call(getProperty(ToObject(this), JSString("toString"), this), this, nil)
+}
+
+func objectPrototypeToString(this Object, args []Object) Object {
+	switch this {
+	case Undefined:
+		return JSString("[object Undefined]")
+	case Null:
+		return JSString("[object Null]")
+	}
+
+	o := ToObject(this)
+	tag := "Object"
+	switch c := o.object().class; c {
+	case "Array", "Arguments", "Function", "Error", "Boolean", "Number", "String":
+		tag = c
+	}
+	if t, ok := getProperty(o, SymbolToStringTag, o).(JSString); ok {
+		tag = string(t)
+	}
+
+	return JSString("[object " + tag + "]")
+}
+
+func objectPrototypeValueOf(this Object, args []Object) Object {
+	return ToObject(this)
+}
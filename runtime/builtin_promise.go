@@ -0,0 +1,205 @@
+package runtime
+
+var (
+	promisePrototype = &JSObject{class: "Object", proto: objectPrototype}
+
+	// promiseConstructor is Promise, which can't be called as a function
+	promiseConstructor = NewFunction("Promise", 1, func(this Object, args []Object) Object {
+		panic(newTypeError("Promise constructor cannot be invoked without 'new'"))
+	})
+)
+
+// the methods of Promise are unexported, so that the compiled code doesn't
+// call them directly, because they use this as the constructor of the
+// promises they create
+func init() {
+	promiseConstructor.construct = constructPromise
+	defineConstructor(promiseConstructor, promisePrototype)
+	defineMethod(promiseConstructor, "all", 1, promiseConstructorAll)
+	defineMethod(promiseConstructor, "race", 1, promiseConstructorRace)
+	defineMethod(promiseConstructor, "reject", 1, promiseConstructorReject)
+	defineMethod(promiseConstructor, "resolve", 1, promiseConstructorResolve)
+
+	defineMethod(promisePrototype, "catch", 1, promisePrototypeCatch)
+	defineMethod(promisePrototype, "finally", 1, promisePrototypeFinally)
+	defineMethod(promisePrototype, "then", 2, promisePrototypeThen)
+	defineToStringTag(promisePrototype, "Promise")
+}
+
+// constructPromise creates a promise and calls the executor with the
+// functions that resolve and reject it
+// An exception thrown by the executor rejects the promise.
+func constructPromise(args []Object, newTarget ObjectValue) Object {
+	executor := Arg(args, 0)
+	if !isCallable(executor) {
+		panic(newTypeError("Promise resolver " + toDisplayString(executor) + " is not a function"))
+	}
+
+	p := newPromise(prototypeFromConstructor(newTarget, promisePrototype))
+	resolve, reject := p.resolvingFunctions()
+	if thrown := catchThrow(func() { Call(executor, Undefined, []Object{resolve, reject}) }); thrown != nil {
+		Call(reject, Undefined, []Object{thrown.Value})
+	}
+
+	return p
+}
+
+// thisPromise returns this, which must be a promise
+func thisPromise(this Object, method string) *Promise {
+	p, ok := this.(*Promise)
+	if !ok {
+		panic(newTypeError("Method Promise.prototype." + method + " called on incompatible receiver " + toDisplayString(this)))
+	}
+
+	return p
+}
+
+// speciesConstructor returns the constructor of an object, which creates
+// the objects derived from it by builtin methods
+func speciesConstructor(o ObjectValue, fallback Object) Object {
+	c := Get(o, JSString("constructor"))
+	if c == Undefined {
+		return fallback
+	}
+
+	if !isObject(c) {
+		panic(newTypeError("The .constructor property is not an object"))
+	}
+
+	return c
+}
+
+// getPromiseResolve returns the resolve method of a promise constructor,
+// which the combinators call for each value
+func getPromiseResolve(c Object) Object {
+	resolve := Get(c, JSString("resolve"))
+	if !isCallable(resolve) {
+		panic(newTypeError("Promise resolve or reject function is not callable"))
+	}
+
+	return resolve
+}
+
+// promiseConstructorAll returns a promise that is fulfilled with the values
+// of all the promises of an iterable, or rejected with the first rejection
+func promiseConstructorAll(this Object, args []Object) Object {
+	capability := newPromiseCapability(this)
+	if thrown := catchThrow(func() {
+		resolve := getPromiseResolve(this)
+		it := GetIterator(Arg(args, 0))
+		defer it.CloseOnThrow()
+
+		var values []Object
+		// remaining counts the promises that aren't fulfilled yet, and one
+		// more until the iterator is done
+		remaining := 1
+		for v, ok := it.Step(); ok; v, ok = it.Step() {
+			i := len(values)
+			values = append(values, Undefined)
+			alreadyCalled := false
+			onFulfilled := NewFunction("", 1, func(this Object, args []Object) Object {
+				if alreadyCalled {
+					return Undefined
+				}
+				alreadyCalled = true
+
+				values[i] = Arg(args, 0)
+				remaining--
+				if remaining == 0 {
+					Call(capability.resolve, Undefined, []Object{NewArray(values)})
+				}
+
+				return Undefined
+			})
+			remaining++
+			Invoke(Call(resolve, this, []Object{v}), JSString("then"), []Object{onFulfilled, capability.reject})
+		}
+
+		remaining--
+		if remaining == 0 {
+			Call(capability.resolve, Undefined, []Object{NewArray(values)})
+		}
+	}); thrown != nil {
+		Call(capability.reject, Undefined, []Object{thrown.Value})
+	}
+
+	return capability.promise
+}
+
+// promiseConstructorRace returns a promise that is settled like the first
+// promise of an iterable that settles
+func promiseConstructorRace(this Object, args []Object) Object {
+	capability := newPromiseCapability(this)
+	if thrown := catchThrow(func() {
+		resolve := getPromiseResolve(this)
+		it := GetIterator(Arg(args, 0))
+		defer it.CloseOnThrow()
+
+		for v, ok := it.Step(); ok; v, ok = it.Step() {
+			Invoke(Call(resolve, this, []Object{v}), JSString("then"), []Object{capability.resolve, capability.reject})
+		}
+	}); thrown != nil {
+		Call(capability.reject, Undefined, []Object{thrown.Value})
+	}
+
+	return capability.promise
+}
+
+func promiseConstructorReject(this Object, args []Object) Object {
+	capability := newPromiseCapability(this)
+	Call(capability.reject, Undefined, []Object{Arg(args, 0)})
+
+	return capability.promise
+}
+
+func promiseConstructorResolve(this Object, args []Object) Object {
+	if !isObject(this) {
+		panic(newTypeError("PromiseResolve called on non-object"))
+	}
+
+	return promiseResolve(this, Arg(args, 0))
+}
+
+func promisePrototypeCatch(this Object, args []Object) Object {
+	return Invoke(this, JSString("then"), []Object{Undefined, Arg(args, 0)})
+}
+
+// promisePrototypeFinally calls a handler when a promise settles, and passes
+// the result of the promise on once the promise returned by the handler is
+// fulfilled
+func promisePrototypeFinally(this Object, args []Object) Object {
+	o, ok := this.(ObjectValue)
+	if !ok {
+		panic(newTypeError("Promise.prototype.finally called on a non-object"))
+	}
+
+	onFinally := Arg(args, 0)
+	if !isCallable(onFinally) {
+		return Invoke(o, JSString("then"), []Object{onFinally, onFinally})
+	}
+
+	c := speciesConstructor(o, promiseConstructor)
+	thenFinally := NewFunction("", 1, func(this Object, args []Object) Object {
+		value := Arg(args, 0)
+		p := promiseResolve(c, Call(onFinally, Undefined, nil))
+		return Invoke(p, JSString("then"), []Object{NewFunction("", 0, func(this Object, args []Object) Object {
+			return value
+		})})
+	})
+	catchFinally := NewFunction("", 1, func(this Object, args []Object) Object {
+		reason := Arg(args, 0)
+		p := promiseResolve(c, Call(onFinally, Undefined, nil))
+		return Invoke(p, JSString("then"), []Object{NewFunction("", 0, func(this Object, args []Object) Object {
+			panic(&Exception{reason})
+		})})
+	})
+
+	return Invoke(o, JSString("then"), []Object{thenFinally, catchFinally})
+}
+
+func promisePrototypeThen(this Object, args []Object) Object {
+	p := thisPromise(this, "then")
+	capability := newPromiseCapability(speciesConstructor(p, promiseConstructor))
+
+	return p.then(Arg(args, 0), Arg(args, 1), capability)
+}
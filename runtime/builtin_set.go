@@ -0,0 +1,117 @@
+package runtime
+
+// JSSet is a Set object, whose entries have the values as their keys
+type JSSet struct {
+	JSObject
+	collection
+}
+
+var (
+	setPrototype         = &JSObject{class: "Object", proto: objectPrototype}
+	setIteratorPrototype = newIteratorPrototype("Set Iterator")
+
+	// setConstructor is Set, which can't be called as a function
+	setConstructor = NewFunction("Set", 0, func(this Object, args []Object) Object {
+		panic(newTypeError("Constructor Set requires 'new'"))
+	})
+
+	// setPrototypeValues is Set.prototype.values, which is also the keys
+	// method and the Symbol.iterator method of sets
+	setPrototypeValues *JSFunction
+)
+
+func init() {
+	setConstructor.construct = constructSet
+	defineConstructor(setConstructor, setPrototype)
+
+	defineMethod(setPrototype, "add", 1, setPrototypeAdd)
+	defineMethod(setPrototype, "clear", 0, setPrototypeClear)
+	defineMethod(setPrototype, "delete", 1, setPrototypeDelete)
+	defineMethod(setPrototype, "entries", 0, setPrototypeEntries)
+	defineMethod(setPrototype, "forEach", 1, setPrototypeForEach)
+	defineMethod(setPrototype, "has", 1, setPrototypeHas)
+	setPrototypeValues = defineMethod(setPrototype, "values", 0, func(this Object, args []Object) Object {
+		return newCollectionIterator(setIteratorPrototype, "Set Iterator", &thisSet(this, "values").collection, "values")
+	})
+	defineHidden(setPrototype, "keys", setPrototypeValues)
+	defineGetter(setPrototype, "size", setPrototypeSize)
+	defineHiddenKey(setPrototype, SymbolIterator, setPrototypeValues)
+	defineToStringTag(setPrototype, "Set")
+}
+
+// constructSet creates a Set with the values of an iterable, which are
+// added by its add method
+func constructSet(args []Object, newTarget ObjectValue) Object {
+	s := &JSSet{JSObject: JSObject{class: "Object", proto: prototypeFromConstructor(newTarget, setPrototype)}}
+	if iterable := Arg(args, 0); !isNullish(iterable) {
+		addValuesFromIterable(s, iterable)
+	}
+
+	return s
+}
+
+// addValuesFromIterable calls the add method of a new Set or WeakSet with
+// each value of an iterable
+func addValuesFromIterable(s ObjectValue, iterable Object) {
+	add := getAdder(s, "add")
+	it := GetIterator(iterable)
+	defer it.CloseOnThrow()
+	for {
+		v, ok := it.Step()
+		if !ok {
+			return
+		}
+
+		Call(add, s, []Object{v})
+	}
+}
+
+// thisSet returns this, which must be a Set
+func thisSet(this Object, method string) *JSSet {
+	s, ok := this.(*JSSet)
+	if !ok {
+		panic(newTypeError("Method Set.prototype." + method + " called on incompatible receiver " + toDisplayString(this)))
+	}
+
+	return s
+}
+
+func setPrototypeAdd(this Object, args []Object) Object {
+	v := Arg(args, 0)
+	thisSet(this, "add").set(v, v)
+
+	return this
+}
+
+func setPrototypeClear(this Object, args []Object) Object {
+	thisSet(this, "clear").clear()
+	return Undefined
+}
+
+func setPrototypeDelete(this Object, args []Object) Object {
+	return JSBoolean(thisSet(this, "delete").delete(Arg(args, 0)))
+}
+
+func setPrototypeEntries(this Object, args []Object) Object {
+	return newCollectionIterator(setIteratorPrototype, "Set Iterator", &thisSet(this, "entries").collection, "entries")
+}
+
+func setPrototypeForEach(this Object, args []Object) Object {
+	s := thisSet(this, "forEach")
+	forEachEntry(s, &s.collection, args)
+
+	return Undefined
+}
+
+func setPrototypeHas(this Object, args []Object) Object {
+	return JSBoolean(thisSet(this, "has").has(Arg(args, 0)))
+}
+
+func setPrototypeSize(this Object, args []Object) Object {
+	s, ok := this.(*JSSet)
+	if !ok {
+		panic(newTypeError("Method get Set.prototype.size called on incompatible receiver " + toDisplayString(this)))
+	}
+
+	return JSNumber(s.size)
+}
@@ -0,0 +1,43 @@
+package runtime
+
+var (
+	// stringPrototype is String.prototype, which is itself a String wrapper
+	// object of the empty string
+	stringPrototype = &stringObject{JSObject{class: "String", proto: objectPrototype, primitive: JSString("")}}
+
+	// stringPrototypeIterator is String.prototype[Symbol.iterator]
+	stringPrototypeIterator = NewFunction("[Symbol.iterator]", 0, func(this Object, args []Object) Object {
+		if isNullish(this) {
+			panic(newTypeError("String.prototype[Symbol.iterator] called on null or undefined"))
+		}
+
+		return newIteratorObject(stringIteratorPrototype, "String Iterator", newStringIterator(ToString(this)))
+	})
+)
+
+func init() {
+	defineMethod(stringPrototype, "toString", 0, stringPrototypeToString)
+	defineMethod(stringPrototype, "valueOf", 0, stringPrototypeValueOf)
+	defineHiddenKey(stringPrototype, SymbolIterator, stringPrototypeIterator)
+}
+
+// thisStringValue returns the string of this, which is either a string or a
+// String wrapper object
+func thisStringValue(this Object, method string) JSString {
+	switch v := this.(type) {
+	case JSString:
+		return v
+	case *stringObject:
+		return v.primitive.(JSString)
+	}
+
+	panic(newTypeError(method + " requires that 'this' be a String"))
+}
+
+func stringPrototypeToString(this Object, args []Object) Object {
+	return thisStringValue(this, "String.prototype.toString")
+}
+
+func stringPrototypeValueOf(this Object, args []Object) Object {
+	return thisStringValue(this, "String.prototype.valueOf")
+}
@@ -0,0 +1,124 @@
+package runtime
+
+// JSWeakMap is a WeakMap object, whose keys are objects and symbols that
+// are held weakly
+type JSWeakMap struct {
+	JSObject
+	weakCollection
+}
+
+// JSWeakSet is a WeakSet object, whose values are objects and symbols that
+// are held weakly
+type JSWeakSet struct {
+	JSObject
+	weakCollection
+}
+
+var (
+	weakMapPrototype = &JSObject{class: "Object", proto: objectPrototype}
+	weakSetPrototype = &JSObject{class: "Object", proto: objectPrototype}
+
+	// weakMapConstructor is WeakMap, which can't be called as a function
+	weakMapConstructor = NewFunction("WeakMap", 0, func(this Object, args []Object) Object {
+		panic(newTypeError("Constructor WeakMap requires 'new'"))
+	})
+
+	// weakSetConstructor is WeakSet, which can't be called as a function
+	weakSetConstructor = NewFunction("WeakSet", 0, func(this Object, args []Object) Object {
+		panic(newTypeError("Constructor WeakSet requires 'new'"))
+	})
+)
+
+func init() {
+	weakMapConstructor.construct = constructWeakMap
+	defineConstructor(weakMapConstructor, weakMapPrototype)
+	defineMethod(weakMapPrototype, "delete", 1, weakMapPrototypeDelete)
+	defineMethod(weakMapPrototype, "get", 1, weakMapPrototypeGet)
+	defineMethod(weakMapPrototype, "has", 1, weakMapPrototypeHas)
+	defineMethod(weakMapPrototype, "set", 2, weakMapPrototypeSet)
+	defineToStringTag(weakMapPrototype, "WeakMap")
+
+	weakSetConstructor.construct = constructWeakSet
+	defineConstructor(weakSetConstructor, weakSetPrototype)
+	defineMethod(weakSetPrototype, "add", 1, weakSetPrototypeAdd)
+	defineMethod(weakSetPrototype, "delete", 1, weakSetPrototypeDelete)
+	defineMethod(weakSetPrototype, "has", 1, weakSetPrototypeHas)
+	defineToStringTag(weakSetPrototype, "WeakSet")
+}
+
+// constructWeakMap creates a WeakMap with the entries of an iterable
+func constructWeakMap(args []Object, newTarget ObjectValue) Object {
+	m := &JSWeakMap{JSObject: JSObject{class: "Object", proto: prototypeFromConstructor(newTarget, weakMapPrototype)}}
+	if iterable := Arg(args, 0); !isNullish(iterable) {
+		addEntriesFromIterable(m, iterable, "set")
+	}
+
+	return m
+}
+
+// constructWeakSet creates a WeakSet with the values of an iterable
+func constructWeakSet(args []Object, newTarget ObjectValue) Object {
+	s := &JSWeakSet{JSObject: JSObject{class: "Object", proto: prototypeFromConstructor(newTarget, weakSetPrototype)}}
+	if iterable := Arg(args, 0); !isNullish(iterable) {
+		addValuesFromIterable(s, iterable)
+	}
+
+	return s
+}
+
+// thisWeakMap returns this, which must be a WeakMap
+func thisWeakMap(this Object, method string) *JSWeakMap {
+	m, ok := this.(*JSWeakMap)
+	if !ok {
+		panic(newTypeError("Method WeakMap.prototype." + method + " called on incompatible receiver " + toDisplayString(this)))
+	}
+
+	return m
+}
+
+// thisWeakSet returns this, which must be a WeakSet
+func thisWeakSet(this Object, method string) *JSWeakSet {
+	s, ok := this.(*JSWeakSet)
+	if !ok {
+		panic(newTypeError("Method WeakSet.prototype." + method + " called on incompatible receiver " + toDisplayString(this)))
+	}
+
+	return s
+}
+
+func weakMapPrototypeDelete(this Object, args []Object) Object {
+	return JSBoolean(thisWeakMap(this, "delete").delete(Arg(args, 0)))
+}
+
+func weakMapPrototypeGet(this Object, args []Object) Object {
+	v, _ := thisWeakMap(this, "get").get(Arg(args, 0))
+	return v
+}
+
+func weakMapPrototypeHas(this Object, args []Object) Object {
+	return JSBoolean(thisWeakMap(this, "has").has(Arg(args, 0)))
+}
+
+func weakMapPrototypeSet(this Object, args []Object) Object {
+	if !thisWeakMap(this, "set").set(Arg(args, 0), Arg(args, 1)) {
+		panic(newTypeError("Invalid value used as weak map key"))
+	}
+
+	return this
+}
+
+func weakSetPrototypeAdd(this Object, args []Object) Object {
+	if !thisWeakSet(this, "add").set(Arg(args, 0), Undefined) {
+		panic(newTypeError("Invalid value used in weak set"))
+	}
+
+	return this
+}
+
+func weakSetPrototypeDelete(this Object, args []Object) Object {
+	return JSBoolean(thisWeakSet(this, "delete").delete(Arg(args, 0)))
+}
+
+func weakSetPrototypeHas(this Object, args []Object) Object {
+	return JSBoolean(thisWeakSet(this, "has").has(Arg(args, 0)))
+}
@@ -0,0 +1,164 @@
+package runtime
+
+import "fmt"
+
+// NewClass creates the constructor of a class and its prototype object
+// The prototype object inherits from the prototype of the heritage of a
+// derived class, and the constructor from the heritage itself. A nil fn is
+// the default constructor, which passes the arguments to the constructor of
+// the parent class in a derived class.
+func NewClass(name string, length int, derived bool, heritage Object, fn func(this Object, args []Object, newTarget Object) Object) (*JSFunction, *JSObject) {
+	protoParent, constructorParent := ObjectValue(objectPrototype), ObjectValue(functionPrototype)
+	if derived {
+		if heritage == Null {
+			protoParent = nil
+		} else {
+			parent, ok := heritage.(*JSFunction)
+			if !ok || parent.construct == nil {
+				panic(newTypeError(fmt.Sprintf("Class extends value %s is not a constructor or null", toDisplayString(heritage))))
+			}
+
+			switch p := getProperty(parent, JSString("prototype"), parent).(type) {
+			case ObjectValue:
+				protoParent = p
+			case null:
+				protoParent = nil
+			default:
+				panic(newTypeError("Class extends value does not have valid prototype property " + toDisplayString(p)))
+			}
+			constructorParent = parent
+		}
+	}
+
+	proto := &JSObject{class: "Object", proto: protoParent}
+	class := NewFunction(name, length, func(this Object, args []Object) Object {
+		panic(newTypeError(fmt.Sprintf("Class constructor %s cannot be invoked without 'new'", name)))
+	})
+	class.proto = constructorParent
+	class.isClass = true
+	class.construct = func(args []Object, newTarget ObjectValue) Object {
+		if derived {
+			if fn == nil {
+				return superConstructor(class).Construct(args, newTarget)
+			}
+
+			return fn(Uninitialized, args, newTarget)
+		}
+
+		this := ordinaryCreateFromConstructor(newTarget, objectPrototype)
+		if fn != nil {
+			if result, ok := fn(this, args, newTarget).(ObjectValue); ok {
+				return result
+			}
+		}
+
+		return this
+	}
+
+	class.defineOwnProperty(JSString("prototype"), &PropertyDescriptor{Value: proto, HasValue: true, HasWritable: true, HasEnumerable: true, HasConfigurable: true})
+	defineHidden(proto, "constructor", class)
+
+	return class, proto
+}
+
+// superConstructor returns the constructor of the parent class of a derived
+// class
+func superConstructor(class *JSFunction) *JSFunction {
+	parent, ok := class.proto.(*JSFunction)
+	if !ok || parent.construct == nil {
+		name := class.name
+		if name == "" {
+			name = "anonymous class"
+		}
+
+		// the parent of a class that extends null is Function.prototype
+		display := "null"
+		if class.proto != ObjectValue(functionPrototype) {
+			display = toDisplayString(class.proto)
+		}
+
+		panic(newTypeError(fmt.Sprintf("Super constructor %s of %s is not a constructor", display, name)))
+	}
+
+	return parent
+}
+
+// DefineMethod adds a method to a class or its prototype, naming the
+// function after the property
+// Methods aren't enumerable, unlike the methods of object literals.
+func DefineMethod(home ObjectValue, key PropertyKey, fn Object) {
+	SetFunctionName(fn, key, "")
+	home.defineOwnProperty(key, &PropertyDescriptor{Value: fn, Writable: true, Configurable: true, HasValue: true, HasWritable: true, HasEnumerable: true, HasConfigurable: true})
+}
+
+// DefineGetter adds the getter of an accessor property to a class or its
+// prototype
+func DefineGetter(home ObjectValue, key PropertyKey, getter Object) {
+	SetFunctionName(getter, key, "get")
+	home.defineOwnProperty(key, &PropertyDescriptor{Get: getter, Configurable: true, HasGet: true, HasEnumerable: true, HasConfigurable: true})
+}
+
+// DefineSetter adds the setter of an accessor property to a class or its
+// prototype
+func DefineSetter(home ObjectValue, key PropertyKey, setter Object) {
+	SetFunctionName(setter, key, "set")
+	home.defineOwnProperty(key, &PropertyDescriptor{Set: setter, Configurable: true, HasSet: true, HasEnumerable: true, HasConfigurable: true})
+}
+
+// SuperCall implements super(...) in the constructor of a derived class,
+// which initializes this with the object created by the parent class
+func SuperCall(class *JSFunction, this *Object, newTarget Object, args []Object) Object {
+	result := superConstructor(class).Construct(args, newTarget.(ObjectValue))
+	if *this != Uninitialized {
+		panic(newReferenceError("Super constructor may only be called once"))
+	}
+	*this = result
+
+	return result
+}
+
+// SuperGet implements super.prop in a method, looking up the property in the
+// prototype of the object the method is defined on
+func SuperGet(home ObjectValue, key PropertyKey, this Object) Object {
+	proto := home.object().proto
+	if proto == nil {
+		return Undefined
+	}
+
+	return getProperty(proto, key, this)
+}
+
+// SuperSet assigns to a property of the prototype of the home object of a
+// method like super.prop = value, with this as the receiver
+func SuperSet(home ObjectValue, key PropertyKey, value Object, this Object) Object {
+	if proto := home.object().proto; proto != nil {
+		setProperty(proto, key, value, this)
+	} else {
+		Set(this, key, value)
+	}
+
+	return value
+}
+
+// CheckThis returns this in the constructor of a derived class, which must
+// have called super
+func CheckThis(this Object) Object {
+	if this == Uninitialized {
+		panic(newReferenceError("Must call super constructor in derived class before accessing 'this' or returning from derived constructor"))
+	}
+
+	return this
+}
+
+// DerivedReturn returns the result of the constructor of a derived class,
+// which is this unless an object is returned
+func DerivedReturn(v Object, this Object) Object {
+	if _, ok := v.(ObjectValue); ok {
+		return v
+	}
+	if v != Undefined {
+		panic(newTypeError("Derived constructors may only return object or undefined"))
+	}
+
+	return CheckThis(this)
+}
@@ -0,0 +1,366 @@
+package runtime
+
+import (
+	"math"
+	"runtime"
+	"sync/atomic"
+)
+
+// collection holds the entries of a Map or a Set in insertion order, with
+// keys compared by SameValueZero
+// A deleted entry leaves a hole, so that the iterations in progress keep
+// their positions. When the holes make up most of the entries, they are
+// removed and the cursors of the iterations are moved along.
+type collection struct {
+	entries []*collectionEntry
+	// index maps the hash keys to the positions of the entries
+	index map[interface{}]int
+	size  int
+	// cursors are the positions of the iterations in progress
+	cursors []*cursorPosition
+}
+
+type collectionEntry struct {
+	key   Object
+	value Object
+}
+
+// collectionCursor is an iteration of a collection
+// The collection holds the position of the cursor rather than the cursor, so
+// that an iterator that is dropped before it's done is simply collected. The
+// finalizer of the cursor tells the collection to forget the position.
+type collectionCursor struct {
+	*cursorPosition
+}
+
+type cursorPosition struct {
+	position int
+	// dropped is set by the finalizer of the cursor, which runs in its own
+	// goroutine
+	dropped int32
+}
+
+// nanKey is the hash key of NaN, which isn't equal to itself as a float
+type nanKey struct{}
+
+// hashKey returns the Go map key of a key, which is the same for all the
+// keys that are SameValueZero
+func hashKey(key Object) interface{} {
+	if n, ok := key.(JSNumber); ok {
+		if math.IsNaN(float64(n)) {
+			return nanKey{}
+		}
+
+		if n == 0 {
+			return JSNumber(0)
+		}
+	}
+
+	return key
+}
+
+func (c *collection) get(key Object) (Object, bool) {
+	i, ok := c.index[hashKey(key)]
+	if !ok {
+		return Undefined, false
+	}
+
+	return c.entries[i].value, true
+}
+
+func (c *collection) has(key Object) bool {
+	_, ok := c.index[hashKey(key)]
+	return ok
+}
+
+// set adds an entry, or replaces the value of the entry with the key
+// A key of -0 is added as +0.
+func (c *collection) set(key, value Object) {
+	h := hashKey(key)
+	if i, ok := c.index[h]; ok {
+		c.entries[i].value = value
+		return
+	}
+
+	if n, ok := key.(JSNumber); ok && n == 0 {
+		key = JSNumber(0)
+	}
+
+	if c.index == nil {
+		c.index = make(map[interface{}]int)
+	}
+	c.index[h] = len(c.entries)
+	c.entries = append(c.entries, &collectionEntry{key: key, value: value})
+	c.size++
+}
+
+func (c *collection) delete(key Object) bool {
+	h := hashKey(key)
+	i, ok := c.index[h]
+	if !ok {
+		return false
+	}
+
+	delete(c.index, h)
+	c.entries[i] = nil
+	c.size--
+
+	if len(c.entries) >= 8 && c.size < len(c.entries)/2 {
+		c.compact()
+	}
+
+	return true
+}
+
+// clear deletes all the entries, and moves the iterations in progress to
+// the entries added afterwards
+func (c *collection) clear() {
+	c.entries = nil
+	c.index = nil
+	c.size = 0
+	c.eachCursor(func(cursor *cursorPosition) {
+		cursor.position = 0
+	})
+}
+
+// compact removes the holes of the deleted entries
+func (c *collection) compact() {
+	// positions maps the old positions to the new ones
+	positions := make([]int, len(c.entries)+1)
+	entries := make([]*collectionEntry, 0, c.size)
+	for i, e := range c.entries {
+		positions[i] = len(entries)
+		if e != nil {
+			c.index[hashKey(e.key)] = len(entries)
+			entries = append(entries, e)
+		}
+	}
+	positions[len(c.entries)] = len(entries)
+
+	c.entries = entries
+	c.eachCursor(func(cursor *cursorPosition) {
+		cursor.position = positions[cursor.position]
+	})
+}
+
+// eachCursor calls f with the positions of the iterations in progress, and
+// forgets the ones whose cursors were collected
+func (c *collection) eachCursor(f func(cursor *cursorPosition)) {
+	cursors := c.cursors[:0]
+	for _, p := range c.cursors {
+		if atomic.LoadInt32(&p.dropped) == 0 {
+			f(p)
+			cursors = append(cursors, p)
+		}
+	}
+	for i := len(cursors); i < len(c.cursors); i++ {
+		c.cursors[i] = nil
+	}
+	c.cursors = cursors
+}
+
+// newCursor starts an iteration at the first entry
+func (c *collection) newCursor() *collectionCursor {
+	if len(c.cursors) == cap(c.cursors) {
+		c.eachCursor(func(cursor *cursorPosition) {})
+	}
+
+	p := &cursorPosition{}
+	cursor := &collectionCursor{p}
+	runtime.SetFinalizer(cursor, func(*collectionCursor) {
+		atomic.StoreInt32(&p.dropped, 1)
+	})
+	c.cursors = append(c.cursors, p)
+
+	return cursor
+}
+
+// next returns the entry at the cursor, and moves the cursor past it
+// Entries added during the iteration are visited too.
+func (c *collection) next(cursor *collectionCursor) (*collectionEntry, bool) {
+	for cursor.position < len(c.entries) {
+		e := c.entries[cursor.position]
+		cursor.position++
+		if e != nil {
+			return e, true
+		}
+	}
+
+	return nil, false
+}
+
+// forEach calls f with each entry, including the ones added by f
+func (c *collection) forEach(f func(e *collectionEntry)) {
+	cursor := c.newCursor()
+	for e, ok := c.next(cursor); ok; e, ok = c.next(cursor) {
+		f(e)
+	}
+}
+
+// iterator returns an iterator over the keys, the values or the entries of
+// the collection, with the cursor of its iteration
+func (c *collection) iterator(kind string) (*Iterator, *collectionCursor) {
+	cursor := c.newCursor()
+	return &Iterator{next: func() (Object, bool) {
+		e, ok := c.next(cursor)
+		if !ok {
+			return Undefined, false
+		}
+
+		switch kind {
+		case "keys":
+			return e.key, true
+		case "values":
+			return e.value, true
+		default:
+			return NewArray([]Object{e.key, e.value}), true
+		}
+	}}, cursor
+}
+
+// newCollectionIterator creates a Map Iterator or a Set Iterator, which
+// previews the entries it hasn't visited yet when it's inspected
+func newCollectionIterator(proto *JSObject, tag string, c *collection, kind string) *iteratorObject {
+	it, cursor := c.iterator(kind)
+	o := newIteratorObject(proto, tag, it)
+	o.preview = func() ([]Object, bool) {
+		var preview []Object
+		if it.done {
+			return preview, kind == "entries"
+		}
+
+		for _, e := range c.entries[cursor.position:] {
+			switch {
+			case e == nil:
+			case kind == "keys":
+				preview = append(preview, e.key)
+			case kind == "values":
+				preview = append(preview, e.value)
+			default:
+				preview = append(preview, e.key, e.value)
+			}
+		}
+
+		return preview, kind == "entries"
+	}
+
+	return o
+}
+
+// weakCollection holds the entries of a WeakMap or a WeakSet, whose keys
+// are held weakly so that they don't keep the objects and symbols alive
+// The entries are held by the weakRefs of the keys, which a finalizer marks
+// when the key is collected. The entries of the keys that were collected are
+// removed when the collection has doubled in size since the last sweep. A
+// value that refers to its own key keeps the key alive, because the values
+// are held strongly, and so does a key in a reference cycle with itself,
+// whose finalizer doesn't run.
+type weakCollection struct {
+	entries map[*weakRef]Object
+	// sweepSize is the number of entries that triggers the next sweep
+	sweepSize int
+}
+
+// weakRef stands for an object or a symbol in the weak collections, which
+// don't refer to the key itself
+type weakRef struct {
+	// collected is set by the finalizer of the key, which runs in its own
+	// goroutine
+	collected int32
+}
+
+// weakRefOf returns the field that holds the weakRef of a key, or nil if the
+// key can't be held weakly, which is the case for primitives and registered
+// symbols
+func weakRefOf(key Object) **weakRef {
+	switch key := key.(type) {
+	case ObjectValue:
+		return &key.object().weakRef
+	case *Symbol:
+		if key.description != nil && symbolRegistry[key.description.(JSString)] == key {
+			return nil
+		}
+
+		return &key.weakRef
+	}
+
+	return nil
+}
+
+// newWeakRef creates the weakRef of a key, which is marked collected when
+// the key is
+func newWeakRef(key Object) *weakRef {
+	r := &weakRef{}
+	runtime.SetFinalizer(key, func(interface{}) { r.markCollected() })
+
+	return r
+}
+
+// markCollected marks the key of a weakRef collected, which the finalizer of
+// the key does
+func (r *weakRef) markCollected() {
+	atomic.StoreInt32(&r.collected, 1)
+}
+
+func (c *weakCollection) get(key Object) (Object, bool) {
+	r := weakRefOf(key)
+	if r == nil || *r == nil {
+		return Undefined, false
+	}
+
+	v, ok := c.entries[*r]
+	return v, ok
+}
+
+func (c *weakCollection) has(key Object) bool {
+	_, ok := c.get(key)
+	return ok
+}
+
+// set adds an entry, or returns false if the key can't be held weakly
+func (c *weakCollection) set(key, value Object) bool {
+	r := weakRefOf(key)
+	if r == nil {
+		return false
+	}
+	if *r == nil {
+		*r = newWeakRef(key)
+	}
+
+	if c.entries == nil {
+		c.entries = make(map[*weakRef]Object)
+	}
+	c.entries[*r] = value
+
+	if len(c.entries) >= c.sweepSize {
+		c.sweep()
+	}
+
+	return true
+}
+
+func (c *weakCollection) delete(key Object) bool {
+	r := weakRefOf(key)
+	if r == nil || *r == nil {
+		return false
+	}
+
+	_, ok := c.entries[*r]
+	delete(c.entries, *r)
+
+	return ok
+}
+
+// sweep removes the entries of the keys that were collected
+func (c *weakCollection) sweep() {
+	for r := range c.entries {
+		if atomic.LoadInt32(&r.collected) != 0 {
+			delete(c.entries, r)
+		}
+	}
+
+	c.sweepSize = 2 * len(c.entries)
+	if c.sweepSize < 8 {
+		c.sweepSize = 8
+	}
+}
@@ -0,0 +1,51 @@
+package runtime
+
+import "testing"
+
+func TestCollectionCompactionKeepsCursors(t *testing.T) {
+	var c collection
+	for i := 0; i < 20; i++ {
+		c.set(JSNumber(i), JSNumber(i))
+	}
+
+	it, cursor := c.iterator("keys")
+	for i := 0; i < 5; i++ {
+		it.Step()
+	}
+	for i := 0; i < 18; i++ {
+		if i != 3 && i != 12 {
+			c.delete(JSNumber(i))
+		}
+	}
+
+	if len(c.entries) >= 20 {
+		t.Fatalf("the holes should be removed: entries=%d size=%d", len(c.entries), c.size)
+	}
+	if cursor.position != 1 {
+		t.Fatalf("the cursor should move along: position=%d", cursor.position)
+	}
+
+	var got []Object
+	for v, ok := it.Step(); ok; v, ok = it.Step() {
+		got = append(got, v)
+	}
+	if len(got) != 3 || got[0] != JSNumber(12) || got[1] != JSNumber(18) || got[2] != JSNumber(19) {
+		t.Fatalf("unexpected keys: got=%v", got)
+	}
+}
+
+func TestWeakCollectionSweepsCollectedKeys(t *testing.T) {
+	var c weakCollection
+	key := NewObject()
+	c.set(key, JSNumber(1))
+	for i := 0; i < 3; i++ {
+		collected := NewObject()
+		c.set(collected, JSNumber(i))
+		(*weakRefOf(collected)).markCollected()
+	}
+
+	c.sweep()
+	if len(c.entries) != 1 || !c.has(key) {
+		t.Fatalf("only the entry of the live key should remain: n=%d", len(c.entries))
+	}
+}
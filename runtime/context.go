@@ -1,13 +1,39 @@
 package runtime
 
+import "math"
+
 func NewDefaultContext() *Context {
-	return &Context{
-		Global: &JSObject{
-			properties: map[string]Object{
-				"console": console,
-			},
-		},
-	}
+	global := NewObject()
+	defineValue(global, "undefined", Undefined)
+	defineValue(global, "NaN", JSNumber(math.NaN()))
+	defineValue(global, "Infinity", Infinity)
+	global.DefineProperty("parseFloat", numberParseFloat)
+	global.DefineProperty("parseInt", numberParseInt)
+	global.DefineProperty("console", console)
+	global.DefineProperty("Object", objectConstructor)
+	global.DefineProperty("Array", arrayConstructor)
+	global.DefineProperty("Symbol", symbolConstructor)
+	global.DefineProperty("Number", numberConstructor)
+	global.DefineProperty("JSON", jsonObject)
+	global.DefineProperty("Math", mathObject)
+	global.DefineProperty("Promise", promiseConstructor)
+	global.DefineProperty("Map", mapConstructor)
+	global.DefineProperty("Set", setConstructor)
+	global.DefineProperty("WeakMap", weakMapConstructor)
+	global.DefineProperty("WeakSet", weakSetConstructor)
+	global.DefineProperty("Error", errorConstructor)
+	global.DefineProperty("TypeError", typeErrorConstructor)
+	global.DefineProperty("RangeError", rangeErrorConstructor)
+	global.DefineProperty("ReferenceError", referenceErrorConstructor)
+	global.DefineProperty("SyntaxError", syntaxErrorConstructor)
+
+	return &Context{Global: global}
+}
+
+// defineValue creates a read-only value property like the value properties of
+// the global object
+func defineValue(o ObjectValue, name string, v Object) {
+	o.defineOwnProperty(JSString(name), &PropertyDescriptor{Value: v, HasValue: true, HasWritable: true, HasEnumerable: true, HasConfigurable: true})
 }
 
 type Context struct {
@@ -0,0 +1,307 @@
+package runtime
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ToBoolean converts a value to a Go bool
+func ToBoolean(v Object) bool {
+	switch v := v.(type) {
+	case nil, null:
+		return false
+	case JSString:
+		return v != ""
+	case JSNumber:
+		return v != 0 && v == v
+	case JSBoolean:
+		return bool(v)
+	default:
+		return true
+	}
+}
+
+// ToPrimitive converts an object to a primitive value by calling its
+// Symbol.toPrimitive method with the hint, or else its valueOf and toString
+// methods in the order given by the hint
+func ToPrimitive(v Object, hint string) Object {
+	o, ok := v.(ObjectValue)
+	if !ok {
+		return v
+	}
+
+	if exotic := getProperty(o, SymbolToPrimitive, o); !isNullish(exotic) {
+		result := Call(exotic, o, []Object{JSString(hint)})
+		if isObject(result) {
+			panic(newTypeError("Cannot convert object to primitive value"))
+		}
+
+		return result
+	}
+
+	methods := []string{"valueOf", "toString"}
+	if hint == "string" {
+		methods = []string{"toString", "valueOf"}
+	}
+
+	for _, name := range methods {
+		if f, ok := getProperty(o, JSString(name), o).(*JSFunction); ok {
+			if result := f.Call(o, nil); !isObject(result) {
+				return result
+			}
+		}
+	}
+
+	panic(newTypeError("Cannot convert object to primitive value"))
+}
+
+// ToNumber converts a value to a number
+func ToNumber(v Object) JSNumber {
+	switch v := v.(type) {
+	case nil:
+		return JSNumber(math.NaN())
+	case null:
+		return 0
+	case JSNumber:
+		return v
+	case JSString:
+		return JSNumber(stringToNumber(string(v)))
+	case JSBoolean:
+		if v {
+			return 1
+		}
+
+		return 0
+	case *Symbol:
+		panic(newTypeError("Cannot convert a Symbol value to a number"))
+	case ObjectValue:
+		return ToNumber(ToPrimitive(v, "number"))
+	default:
+		return JSNumber(math.NaN())
+	}
+}
+
+var decimalLiteralRegexp = regexp.MustCompile(`^[+-]?(\d+\.?\d*([eE][+-]?\d+)?|\.\d+([eE][+-]?\d+)?)$`)
+
+// stringToNumber parses a string with the StringNumericLiteral grammar
+func stringToNumber(s string) float64 {
+	s = strings.TrimFunc(s, isWhiteSpaceOrLineTerminator)
+	if s == "" {
+		return 0
+	}
+
+	switch s {
+	case "Infinity", "+Infinity":
+		return math.Inf(1)
+	case "-Infinity":
+		return math.Inf(-1)
+	}
+
+	if len(s) > 2 && s[0] == '0' {
+		base := 0
+		switch s[1] {
+		case 'x', 'X':
+			base = 16
+		case 'o', 'O':
+			base = 8
+		case 'b', 'B':
+			base = 2
+		}
+
+		if base != 0 {
+			for _, r := range s[2:] {
+				if digitValue(r) >= base {
+					return math.NaN()
+				}
+			}
+
+			return parseDigits(s[2:], base)
+		}
+	}
+
+	if !decimalLiteralRegexp.MatchString(s) {
+		return math.NaN()
+	}
+
+	// out of range values are rounded to infinity
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
+
+// digitValue returns the value of a digit in bases up to 36, or 36 if r
+// isn't a digit
+func digitValue(r rune) int {
+	switch {
+	case '0' <= r && r <= '9':
+		return int(r - '0')
+	case 'a' <= r && r <= 'z':
+		return int(r-'a') + 10
+	case 'A' <= r && r <= 'Z':
+		return int(r-'A') + 10
+	default:
+		return 36
+	}
+}
+
+func isWhiteSpaceOrLineTerminator(r rune) bool {
+	switch r {
+	case '\t', '\n', '\v', '\f', '\r', ' ', '\u00a0', '\u1680', '\u2028', '\u2029', '\u202f', '\u205f', '\u3000', '\ufeff':
+		return true
+	}
+
+	return '\u2000' <= r && r <= '\u200a'
+}
+
+// ToIntegerOrInfinity converts a value to an integral number, truncating
+// towards zero
+func ToIntegerOrInfinity(v Object) float64 {
+	f := float64(ToNumber(v))
+	if math.IsNaN(f) {
+		return 0
+	}
+
+	return math.Trunc(f) + 0
+}
+
+// ToLength converts a value to an integer suitable as the length of an
+// array-like object
+func ToLength(v Object) int64 {
+	f := ToIntegerOrInfinity(v)
+	if f <= 0 {
+		return 0
+	}
+
+	return int64(math.Min(f, 1<<53-1))
+}
+
+// ToUint32 converts a value to an unsigned 32-bit integer
+func ToUint32(v Object) uint32 {
+	f := float64(ToNumber(v))
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0
+	}
+
+	return uint32(int64(math.Mod(math.Trunc(f), 1<<32)))
+}
+
+// ToInt32 converts a value to a signed 32-bit integer
+func ToInt32(v Object) int32 {
+	return int32(ToUint32(v))
+}
+
+// ToString converts a value to a string
+func ToString(v Object) JSString {
+	switch v := v.(type) {
+	case nil:
+		return "undefined"
+	case null:
+		return "null"
+	case JSString:
+		return v
+	case JSNumber:
+		return JSString(numberToString(float64(v)))
+	case JSBoolean:
+		if v {
+			return "true"
+		}
+
+		return "false"
+	case *Symbol:
+		panic(newTypeError("Cannot convert a Symbol value to a string"))
+	case ObjectValue:
+		return ToString(ToPrimitive(v, "string"))
+	default:
+		return JSString(fmt.Sprint(v))
+	}
+}
+
+// ToPropertyKey converts a value to a property key, which is a string or a
+// symbol
+func ToPropertyKey(v Object) PropertyKey {
+	switch k := ToPrimitive(v, "string").(type) {
+	case JSString:
+		return k
+	case *Symbol:
+		return k
+	default:
+		return ToString(k)
+	}
+}
+
+// ToObject converts a value to an object, wrapping primitive values
+func ToObject(v Object) ObjectValue {
+	switch v := v.(type) {
+	case nil, null:
+		panic(newTypeError("Cannot convert undefined or null to object"))
+	case ObjectValue:
+		return v
+	case JSString:
+		return newStringObject(v)
+	case JSNumber:
+		return &JSObject{class: "Number", proto: numberPrototype, primitive: v}
+	case JSBoolean:
+		return &JSObject{class: "Boolean", proto: objectPrototype, primitive: v}
+	case *Symbol:
+		return &JSObject{class: "Symbol", proto: symbolPrototype, primitive: v}
+	default:
+		panic(newTypeError(fmt.Sprintf("Cannot convert %v to object", v)))
+	}
+}
+
+func isObject(v Object) bool {
+	_, ok := v.(ObjectValue)
+	return ok
+}
+
+func isCallable(v Object) bool {
+	_, ok := v.(*JSFunction)
+	return ok
+}
+
+// numberToString implements Number::toString for radix 10: integers up to
+// 21 digits are written out, and other numbers use the shortest decimal
+// digits that round-trip, in exponential notation when very small or large
+func numberToString(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case f == 0:
+		return "0"
+	case math.IsInf(f, 1):
+		return "Infinity"
+	case math.IsInf(f, -1):
+		return "-Infinity"
+	case f < 0:
+		return "-" + numberToString(-f)
+	}
+
+	// d.ddde±x gives the digits and the exponent
+	s := strconv.FormatFloat(f, 'e', -1, 64)
+	e := strings.IndexByte(s, 'e')
+	digits := strings.Replace(s[:e], ".", "", 1)
+	exp, _ := strconv.Atoi(s[e+1:])
+	k, n := len(digits), exp+1
+
+	switch {
+	case k <= n && n <= 21:
+		return digits + strings.Repeat("0", n-k)
+	case 0 < n && n <= 21:
+		return digits[:n] + "." + digits[n:]
+	case -6 < n && n <= 0:
+		return "0." + strings.Repeat("0", -n) + digits
+	}
+
+	sign := "+"
+	if n-1 < 0 {
+		sign = "-"
+	}
+	exponent := "e" + sign + strconv.Itoa(int(math.Abs(float64(n-1))))
+	if k == 1 {
+		return digits + exponent
+	}
+
+	return digits[:1] + "." + digits[1:] + exponent
+}
@@ -0,0 +1,136 @@
+package runtime
+
+import "runtime"
+
+// coroutine runs the body of a generator or an async function on a goroutine
+// of its own
+//
+// The compiled body is straight-line Go code, which can't be suspended in the
+// middle of a statement. Instead, the body is started on a goroutine by the
+// first resumption, and control is handed back and forth between the caller
+// and the body over channels, so that only one of them runs at a time. A
+// suspended body blocks its goroutine until it's resumed, which keeps the Go
+// stack of the body, with its try blocks, loops and temporaries, the same way
+// a state machine would keep its state.
+//
+// The trade-offs are that a body that is left suspended keeps its goroutine
+// blocked until the object that owns the coroutine is collected, and that
+// the stack trace of an error created by the body only has the frames of the
+// body.
+type coroutine struct {
+	body func() Object
+	// resumptions passes the resumptions to the body, and results passes
+	// the values the body is suspended with and its completion back to the
+	// caller
+	resumptions chan resumption
+	results     chan coroutineResult
+	// suspended is true while the goroutine of the body is blocked
+	suspended bool
+}
+
+// coroutineOwner drops a coroutine that is left suspended when the object
+// that owns it is collected
+// The finalizer is set on an object of its own, like the cursors of
+// collections, because the owner may be a key of a weak collection, which
+// sets a finalizer on the key.
+type coroutineOwner struct {
+	co *coroutine
+}
+
+func newCoroutineOwner(co *coroutine) *coroutineOwner {
+	o := &coroutineOwner{co}
+	runtime.SetFinalizer(o, func(o *coroutineOwner) {
+		if o.co.suspended {
+			close(o.co.resumptions)
+		}
+	})
+
+	return o
+}
+
+// resumption resumes a body with a call of next, return or throw
+type resumption struct {
+	method string
+	value  Object
+}
+
+// coroutineResult is a value the body is suspended with, the value the body
+// completes with, or the panic of a body that throws
+type coroutineResult struct {
+	value Object
+	done  bool
+	panic interface{}
+}
+
+// generatorReturn unwinds the body of a generator that is resumed by its
+// return method
+type generatorReturn struct {
+	value Object
+}
+
+// resume runs the body until it's suspended or completes
+func (co *coroutine) resume(r resumption) coroutineResult {
+	if co.results == nil {
+		co.resumptions = make(chan resumption)
+		co.results = make(chan coroutineResult)
+		go co.run()
+	} else {
+		co.resumptions <- r
+	}
+
+	result := <-co.results
+	co.suspended = !result.done && result.panic == nil
+	return result
+}
+
+// run runs the body on its goroutine, passing its completion back to the
+// caller
+func (co *coroutine) run() {
+	var result coroutineResult
+	defer func() {
+		if r := recover(); r != nil {
+			if ret, ok := r.(*generatorReturn); ok {
+				result = coroutineResult{value: ret.value, done: true}
+			} else {
+				result = coroutineResult{panic: r}
+			}
+		}
+		if !result.done && result.panic == nil {
+			// the body was dropped
+			return
+		}
+		co.results <- result
+	}()
+
+	result = coroutineResult{value: co.body(), done: true}
+}
+
+// suspend passes a value to the caller and waits until the body is resumed
+// A body that is dropped exits its goroutine without running the finalizers
+// of try statements, which would run alongside the program, the same way V8
+// never finishes a generator that is collected.
+func (co *coroutine) suspend(v Object) resumption {
+	co.results <- coroutineResult{value: v}
+	r, ok := <-co.resumptions
+	if !ok {
+		runtime.Goexit()
+	}
+
+	return r
+}
+
+// continueBody continues a suspended body with a resumption, and returns
+// the value passed to next
+// A body resumed by return unwinds like a return statement, running the
+// finalizers of try statements, and one resumed by throw throws the value
+// where it's suspended.
+func continueBody(r resumption) Object {
+	switch r.method {
+	case "return":
+		panic(&generatorReturn{r.value})
+	case "throw":
+		panic(&Exception{r.value})
+	}
+
+	return r.value
+}
@@ -1,11 +1,203 @@
 package runtime
 
-import "fmt"
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
 
-type ReferenceError struct {
-	ref string
+// Exception is a thrown value
+// Throwing a value panics with an *Exception, which unwinds the Go stack up
+// to the closest Try.
+type Exception struct {
+	Value Object
 }
 
-func (self *ReferenceError) Error() string {
-	return fmt.Sprintf("ReferenceError: %s is not defined", self.ref)
+func (self *Exception) Error() string {
+	return "Uncaught " + describeException(self.Value)
+}
+
+// Throw throws a value
+func Throw(v Object) {
+	panic(&Exception{v})
+}
+
+// describeException formats a thrown value, using the stack of errors
+func describeException(v Object) string {
+	if o, ok := v.(ObjectValue); ok && o.object().class == "Error" {
+		if stack, ok := getProperty(o, JSString("stack"), o).(JSString); ok {
+			return string(stack)
+		}
+	}
+
+	return Inspect(v)
+}
+
+// ReportUncaught reports an uncaught exception of the program and exits
+// like Node
+// It must be deferred by the main function.
+func ReportUncaught() {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	e, ok := r.(*Exception)
+	if !ok {
+		panic(r)
+	}
+
+	fmt.Fprintln(os.Stderr, e.Error())
+	os.Exit(1)
+}
+
+// Completion tells how a block of a try statement or the body of a for-of
+// loop completed
+// Blocks complete normally or with a return statement, and the body of a
+// loop with a break out of the loop too. The compiler numbers the break and
+// continue statements that leave a block from JumpCompletion.
+type Completion int
+
+const (
+	NormalCompletion Completion = iota
+	ReturnCompletion
+	BreakCompletion
+	JumpCompletion
+)
+
+// Try runs the blocks of a try statement
+// The handler catches an exception thrown by the block, and the finalizer
+// runs last. An abrupt completion of the finalizer overrides the completion
+// of the other blocks, including an exception. Either handler or finalizer
+// may be nil.
+func Try(block func() (Completion, Object), handler func(Object) (Completion, Object), finalizer func() (Completion, Object)) (Completion, Object) {
+	c, v, p := catchException(block)
+	if e, ok := p.(*Exception); ok && handler != nil {
+		c, v, p = catchException(func() (Completion, Object) { return handler(e.Value) })
+	}
+
+	if finalizer != nil {
+		if fc, fv := finalizer(); fc != NormalCompletion {
+			return fc, fv
+		}
+	}
+
+	if p != nil {
+		panic(p)
+	}
+
+	return c, v
+}
+
+// catchException runs a block and recovers an exception, or the return of a
+// generator resumed by its return method, which runs the finalizer but isn't
+// caught by the handler
+func catchException(block func() (Completion, Object)) (c Completion, v Object, p interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			switch r.(type) {
+			case *Exception, *generatorReturn:
+				p = r
+			default:
+				panic(r)
+			}
+		}
+	}()
+
+	c, v = block()
+	return
+}
+
+// stackTraceLimit is the maximum number of frames of a stack, like
+// Error.stackTraceLimit of V8
+const stackTraceLimit = 10
+
+// captureStack formats the JavaScript frames of the Go stack like V8 does
+// The compiled code maps Go lines to JavaScript lines with line directives,
+// and calls of functions are found by their JSFunction.Call or Construct
+// frame. If caller isn't nil, the calls up to the innermost call of caller
+// are skipped, like the call of an error constructor.
+func captureStack(caller ObjectValue) string {
+	pc := make([]uintptr, 256)
+	frames := runtime.CallersFrames(pc[:runtime.Callers(2, pc)])
+
+	var lines []string
+	top := len(callStack)
+	skip := caller != nil
+	location := ""
+	for len(lines) < stackTraceLimit {
+		f, more := frames.Next()
+		if isCallFrame(f.Function) && top > 0 {
+			top--
+			if skip {
+				skip = ObjectValue(callStack[top].function) != caller
+			} else {
+				lines = append(lines, formatFrame(callStack[top], location))
+			}
+			location = ""
+		} else if location == "" && isScriptFile(f.File) {
+			location = fmt.Sprintf("%s:%d", filepath.Base(f.File), f.Line)
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	if location != "" && len(lines) < stackTraceLimit {
+		lines = append(lines, "    at "+location)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func isCallFrame(function string) bool {
+	return strings.HasSuffix(function, ".(*JSFunction).Call") || strings.HasSuffix(function, ".(*JSFunction).Construct")
+}
+
+func formatFrame(call callFrame, location string) string {
+	name := call.function.name
+	if call.construct {
+		if name == "" {
+			name = "<anonymous>"
+		}
+		name = "new " + name
+	}
+
+	switch {
+	case location == "":
+		location = "<anonymous>"
+	case name == "":
+		return "    at " + location
+	}
+
+	return fmt.Sprintf("    at %s (%s)", name, location)
+}
+
+// isScriptFile tells whether a frame is in the compiled script, whose line
+// directives name the JavaScript file
+func isScriptFile(file string) bool {
+	return file != "" && !strings.HasSuffix(file, ".go") && !strings.HasSuffix(file, ".s")
+}
+
+// toDisplayString formats a value for an error message the way V8 does
+func toDisplayString(v Object) string {
+	switch v := v.(type) {
+	case *JSFunction:
+		return fmt.Sprintf("function %s() { [native code] }", v.name)
+	case *JSArray:
+		return "[object Array]"
+	case ObjectValue:
+		if name := constructorName(v); name != "" {
+			return "#<" + name + ">"
+		}
+
+		return "[object Object]"
+	case *Symbol:
+		return v.String()
+	default:
+		return string(ToString(v))
+	}
 }
@@ -0,0 +1,139 @@
+package runtime
+
+// callStack holds the functions being called, innermost last
+var callStack []callFrame
+
+// maxCallDepth is the number of calls on the call stack beyond which a call
+// throws a RangeError, well before the Go stack runs out
+const maxCallDepth = 10000
+
+// callFrame is a call of a function, either as a function or as a constructor
+type callFrame struct {
+	function  *JSFunction
+	construct bool
+}
+
+// Call invokes fn with the given this value and arguments
+func Call(fn Object, this Object, args []Object) Object {
+	f, ok := fn.(*JSFunction)
+	if !ok {
+		panic(newTypeError(toDisplayString(fn) + " is not a function"))
+	}
+
+	return f.Call(this, args)
+}
+
+// New implements the new operator
+func New(callee Object, args []Object) Object {
+	f, ok := callee.(*JSFunction)
+	if !ok || f.construct == nil {
+		panic(newTypeError(toDisplayString(callee) + " is not a constructor"))
+	}
+
+	return f.Construct(args, f)
+}
+
+// NewConstructor creates an ordinary function, which is a constructor as
+// well as a function
+// The function is told new.target, which is undefined when it's called.
+func NewConstructor(name string, length int, fn func(this Object, args []Object, newTarget Object) Object) *JSFunction {
+	f := NewFunction(name, length, func(this Object, args []Object) Object {
+		return fn(this, args, Undefined)
+	})
+	f.lazyPrototype = true
+	f.construct = func(args []Object, newTarget ObjectValue) Object {
+		this := ordinaryCreateFromConstructor(newTarget, objectPrototype)
+		if result, ok := fn(this, args, newTarget).(ObjectValue); ok {
+			return result
+		}
+
+		return this
+	}
+
+	return f
+}
+
+// constructBuiltin constructs an object with a builtin constructor, which
+// creates the object when it's called
+// The object of a subclass inherits from the prototype of new.target.
+func (self *JSFunction) constructBuiltin(args []Object, newTarget ObjectValue) Object {
+	result := self.fn(Undefined, args)
+	if o, ok := result.(ObjectValue); ok && newTarget != self {
+		o.object().proto = prototypeFromConstructor(newTarget, o.object().proto)
+	}
+
+	return result
+}
+
+// ordinaryCreateFromConstructor creates an object that inherits from the
+// prototype of a constructor
+func ordinaryCreateFromConstructor(constructor ObjectValue, fallback ObjectValue) *JSObject {
+	return &JSObject{class: "Object", proto: prototypeFromConstructor(constructor, fallback)}
+}
+
+// prototypeFromConstructor returns the prototype property of a constructor,
+// or fallback if it's not an object
+func prototypeFromConstructor(constructor ObjectValue, fallback ObjectValue) ObjectValue {
+	if proto, ok := getProperty(constructor, JSString("prototype"), constructor).(ObjectValue); ok {
+		return proto
+	}
+
+	return fallback
+}
+
+// Arg returns the i-th argument of a call
+// Missing arguments are nil
+func Arg(args []Object, i int) Object {
+	if i < len(args) {
+		return args[i]
+	}
+
+	return nil
+}
+
+// RestArgs returns the arguments from the i-th on in an array for a rest
+// parameter
+func RestArgs(args []Object, i int) *JSArray {
+	var rest []Object
+	if i < len(args) {
+		rest = append(rest, args[i:]...)
+	}
+
+	return NewArray(rest)
+}
+
+// BindThis returns the this value of a sloppy mode function, which is the
+// global object for undefined and null, and an object for a primitive
+func BindThis(this Object, global ObjectValue) Object {
+	if isNullish(this) {
+		return global
+	}
+
+	return ToObject(this)
+}
+
+// SetFunctionName names an anonymous function after the property key it's
+// defined with, with a get or set prefix for accessors
+// A function defined with a symbol key is named after the description of the
+// symbol in brackets.
+func SetFunctionName(fn Object, key PropertyKey, prefix string) Object {
+	f, ok := fn.(*JSFunction)
+	if !ok || f.initialized || f.name != "" {
+		return fn
+	}
+
+	var name string
+	if s, ok := key.(*Symbol); ok {
+		if s.description != nil {
+			name = "[" + string(s.description.(JSString)) + "]"
+		}
+	} else {
+		name = string(ToString(key))
+	}
+	if prefix != "" {
+		name = prefix + " " + name
+	}
+	f.name = name
+
+	return fn
+}
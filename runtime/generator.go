@@ -0,0 +1,199 @@
+package runtime
+
+// Generator is a generator object, whose body runs as a coroutine
+// A generator that is resumed by its return method unwinds the body from the
+// yield like a return statement, and one resumed by its throw method throws
+// the exception at the yield.
+type Generator struct {
+	JSObject
+	*coroutine
+	state generatorState
+	// owner drops the coroutine when the generator is collected
+	owner *coroutineOwner
+}
+
+// GeneratorBody is the generator as seen by its body, which yields through
+// it
+// It doesn't refer to the generator object, so that a generator that is left
+// suspended can be collected while its body is parked.
+type GeneratorBody struct {
+	*coroutine
+}
+
+type generatorState int
+
+const (
+	generatorSuspendedStart generatorState = iota
+	generatorSuspendedYield
+	generatorExecuting
+	generatorCompleted
+)
+
+var (
+	// generatorFunctionPrototype is %GeneratorFunction.prototype%, the
+	// prototype of generator functions, and generatorPrototype is
+	// %GeneratorPrototype%, the prototype of the prototype property of
+	// generator functions
+	generatorFunctionPrototype = &JSObject{class: "Object", proto: functionPrototype}
+	generatorPrototype         = &JSObject{class: "Object", proto: iteratorPrototype}
+)
+
+func init() {
+	linkPrototype(NewFunction("GeneratorFunction", 1, func(this Object, args []Object) Object {
+		panic(newTypeError("Code generation from strings disallowed for this context"))
+	}), generatorFunctionPrototype)
+	generatorFunctionPrototype.defineOwnProperty(JSString("prototype"), &PropertyDescriptor{Value: generatorPrototype, Configurable: true, HasValue: true, HasWritable: true, HasEnumerable: true, HasConfigurable: true})
+	defineToStringTag(generatorFunctionPrototype, "GeneratorFunction")
+
+	generatorPrototype.defineOwnProperty(JSString("constructor"), &PropertyDescriptor{Value: generatorFunctionPrototype, Configurable: true, HasValue: true, HasWritable: true, HasEnumerable: true, HasConfigurable: true})
+	defineMethod(generatorPrototype, "next", 1, generatorPrototypeNext)
+	defineMethod(generatorPrototype, "return", 1, generatorPrototypeReturn)
+	defineMethod(generatorPrototype, "throw", 1, generatorPrototypeThrow)
+	defineToStringTag(generatorPrototype, "Generator")
+}
+
+// NewGeneratorFunction creates a generator function, which isn't a
+// constructor
+// The function binds the parameters and returns the generator created by
+// NewGenerator.
+func NewGeneratorFunction(name string, length int, fn func(this Object, args []Object) Object) *JSFunction {
+	f := NewFunction(name, length, fn)
+	f.proto = generatorFunctionPrototype
+	f.generator = true
+	f.lazyPrototype = true
+
+	return f
+}
+
+// NewGenerator creates the generator of a call of a generator function,
+// which is the innermost call, with the body of the function
+func NewGenerator(body func(generator *GeneratorBody) Object) *Generator {
+	callee := callStack[len(callStack)-1].function
+	proto := prototypeFromConstructor(callee, generatorPrototype)
+
+	co := &coroutine{}
+	b := &GeneratorBody{co}
+	co.body = func() Object { return body(b) }
+	g := &Generator{JSObject: JSObject{class: "Object", proto: proto}, coroutine: co, owner: newCoroutineOwner(co)}
+
+	return g
+}
+
+// resume runs the body of a generator until it yields or completes, and
+// returns the iterator result
+func (g *Generator) resume(r resumption) Object {
+	switch g.state {
+	case generatorExecuting:
+		panic(newTypeError("Generator is already running"))
+	case generatorSuspendedStart:
+		if r.method != "next" {
+			g.state = generatorCompleted
+		}
+	}
+
+	if g.state == generatorCompleted {
+		switch r.method {
+		case "return":
+			return createIterResultObject(r.value, true)
+		case "throw":
+			panic(&Exception{r.value})
+		default:
+			return createIterResultObject(Undefined, true)
+		}
+	}
+
+	g.state = generatorExecuting
+	result := g.coroutine.resume(r)
+	g.state = generatorSuspendedYield
+	if result.done || result.panic != nil {
+		g.state = generatorCompleted
+	}
+
+	if result.panic != nil {
+		panic(result.panic)
+	}
+
+	if result.done {
+		return createIterResultObject(result.value, true)
+	}
+
+	// a yield suspends the body with the iterator result
+	return result.value
+}
+
+// Yield implements yield, which suspends the generator with a value and
+// evaluates to the value passed to next
+func (g *GeneratorBody) Yield(v Object) Object {
+	return continueBody(g.suspend(createIterResultObject(v, false)))
+}
+
+// Delegate implements yield*, which passes the resumptions of the generator
+// on to the iterator of an iterable and evaluates to the value the iterator
+// is done with
+// The results of the iterator are yielded as they are.
+func (g *GeneratorBody) Delegate(iterable Object) Object {
+	var method Object
+	if !isNullish(iterable) {
+		method = Get(iterable, SymbolIterator)
+	}
+	iter, next := openIterator(iterable, method)
+
+	r := resumption{method: "next"}
+	for {
+		var result Object
+		switch r.method {
+		case "next":
+			result = Call(next, iter, []Object{r.value})
+		case "throw":
+			throw := Get(iter, JSString("throw"))
+			if isNullish(throw) {
+				closeIterator(iter)
+				panic(newTypeError("The iterator does not provide a 'throw' method."))
+			}
+			result = Call(throw, iter, []Object{r.value})
+		case "return":
+			ret := Get(iter, JSString("return"))
+			if isNullish(ret) {
+				panic(&generatorReturn{r.value})
+			}
+			result = Call(ret, iter, []Object{r.value})
+		}
+
+		if !isObject(result) {
+			panic(newTypeError("Iterator result " + toDisplayString(result) + " is not an object"))
+		}
+
+		if ToBoolean(Get(result, JSString("done"))) {
+			value := Get(result, JSString("value"))
+			if r.method == "return" {
+				panic(&generatorReturn{value})
+			}
+
+			return value
+		}
+
+		r = g.suspend(result)
+	}
+}
+
+// thisGenerator returns this, which must be a generator
+func thisGenerator(this Object, method string) *Generator {
+	g, ok := this.(*Generator)
+	if !ok {
+		panic(newTypeError("Method [Generator].prototype." + method + " called on incompatible receiver " + toDisplayString(this)))
+	}
+
+	return g
+}
+
+func generatorPrototypeNext(this Object, args []Object) Object {
+	return thisGenerator(this, "next").resume(resumption{"next", Arg(args, 0)})
+}
+
+func generatorPrototypeReturn(this Object, args []Object) Object {
+	return thisGenerator(this, "return").resume(resumption{"return", Arg(args, 0)})
+}
+
+func generatorPrototypeThrow(this Object, args []Object) Object {
+	return thisGenerator(this, "throw").resume(resumption{"throw", Arg(args, 0)})
+}
@@ -0,0 +1,38 @@
+package runtime
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestSuspendedGeneratorIsDropped(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+
+	f := NewGeneratorFunction("gen", 0, func(this Object, args []Object) Object {
+		return NewGenerator(func(generator *GeneratorBody) Object {
+			for {
+				generator.Yield(JSNumber(1))
+			}
+		})
+	})
+	func() {
+		for i := 0; i < 10; i++ {
+			g := Call(f, Undefined, nil)
+			generatorPrototypeNext(g, nil)
+		}
+	}()
+	if n := runtime.NumGoroutine(); n < baseline+10 {
+		t.Fatalf("the suspended generators should be parked: n=%d baseline=%d", n, baseline)
+	}
+
+	// the finalizers of the generators run in their own goroutine after a
+	// collection
+	for deadline := time.Now().Add(5 * time.Second); runtime.NumGoroutine() > baseline; {
+		if time.Now().After(deadline) {
+			t.Fatalf("the goroutines of the generators should exit: n=%d baseline=%d", runtime.NumGoroutine(), baseline)
+		}
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,1536 @@
+// The functions in this file are ported from the fdlibm functions in V8's
+// src/base/ieee754.cc, which is adapted from fdlibm
+// (http://www.netlib.org/fdlibm) and from FreeBSD's msun library.
+//
+// ====================================================
+// Copyright (C) 1993-2004 by Sun Microsystems, Inc. All rights reserved.
+//
+// Developed at SunSoft, a Sun Microsystems, Inc. business.
+// Permission to use, copy, modify, and distribute this
+// software is freely granted, provided that this notice
+// is preserved.
+// ====================================================
+//
+// Copyright 2016 the V8 project authors. All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are
+// met:
+//
+//     * Redistributions of source code must retain the above copyright
+//       notice, this list of conditions and the following disclaimer.
+//     * Redistributions in binary form must reproduce the above
+//       copyright notice, this list of conditions and the following
+//       disclaimer in the documentation and/or other materials provided
+//       with the distribution.
+//     * Neither the name of Google Inc. nor the names of its
+//       contributors may be used to endorse or promote products derived
+//       from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS
+// "AS IS" AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT
+// LIMITED TO, THE IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR
+// A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT
+// OWNER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT
+// LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE,
+// DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND ON ANY
+// THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+// (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package runtime
+
+import "math"
+
+// The fdlibm functions compute the math functions the way V8 does, so that
+// their results are the same to the last bit
+// Go's math package rounds differently in the last place for many inputs of
+// these functions. Its Expm1 and Log1p are ports of fdlibm themselves, so
+// Math.expm1, Math.log1p and the hyperbolic functions use them as they are.
+
+func highWord(x float64) int32 {
+	return int32(math.Float64bits(x) >> 32)
+}
+
+func lowWord(x float64) uint32 {
+	return uint32(math.Float64bits(x))
+}
+
+func fromWords(hi int32, lo uint32) float64 {
+	return math.Float64frombits(uint64(uint32(hi))<<32 | uint64(lo))
+}
+
+func setHighWord(x float64, hi int32) float64 {
+	return fromWords(hi, lowWord(x))
+}
+
+// clearLowWord zeroes the low 32 bits of x
+func clearLowWord(x float64) float64 {
+	return math.Float64frombits(math.Float64bits(x) &^ 0xFFFFFFFF)
+}
+
+// twoOverPi holds the bits of 2/pi in 24-bit chunks
+var twoOverPi = [...]int32{
+	0xA2F983, 0x6E4E44, 0x1529FC, 0x2757D1, 0xF534DD, 0xC0DB62,
+	0x95993C, 0x439041, 0xFE5163, 0xABDEBB, 0xC561B7, 0x246E3A,
+	0x424DD2, 0xE00649, 0x2EEA09, 0xD1921C, 0xFE1DEB, 0x1CB129,
+	0xA73EE8, 0x8235F5, 0x2EBB44, 0x84E99C, 0x7026B4, 0x5F7E41,
+	0x3991D6, 0x398353, 0x39F49C, 0x845F8B, 0xBDF928, 0x3B1FF8,
+	0x97FFDE, 0x05980F, 0xEF2F11, 0x8B5A0A, 0x6D1F6D, 0x367ECF,
+	0x27CB09, 0xB74F46, 0x3F669E, 0x5FEA2D, 0x7527BA, 0xC7EBE5,
+	0xF17B3D, 0x0739F7, 0x8A5292, 0xEA6BFB, 0x5FB11F, 0x8D5D08,
+	0x560330, 0x46FC7B, 0x6BABF0, 0xCFBC20, 0x9AF436, 0x1DA9E3,
+	0x91615E, 0xE61B08, 0x659985, 0x5F14A0, 0x68408D, 0xFFD880,
+	0x4D7327, 0x310606, 0x1556CA, 0x73A8C9, 0x60E27B, 0xC08C6B,
+}
+
+// npio2HighWords holds the high words of n*pi/2 for n from 1 to 32
+var npio2HighWords = [...]int32{
+	0x3FF921FB, 0x400921FB, 0x4012D97C, 0x401921FB, 0x401F6A7A, 0x4022D97C,
+	0x4025FDBB, 0x402921FB, 0x402C463A, 0x402F6A7A, 0x4031475C, 0x4032D97C,
+	0x40346B9C, 0x4035FDBB, 0x40378FDB, 0x403921FB, 0x403AB41B, 0x403C463A,
+	0x403DD85A, 0x403F6A7A, 0x40407E4C, 0x4041475C, 0x4042106C, 0x4042D97C,
+	0x4043A28C, 0x40446B9C, 0x404534AC, 0x4045FDBB, 0x4046C6CB, 0x40478FDB,
+	0x404858EB, 0x404921FB,
+}
+
+// remPio2 reduces x to y0+y1 in [-pi/4, pi/4], and returns the number of
+// times pi/2 was taken off
+func remPio2(x float64) (n int32, y0, y1 float64) {
+	const (
+		two24   = 1.67772160000000000000e+07 // 0x41700000, 0x00000000
+		invpio2 = 6.36619772367581382433e-01 // 0x3FE45F30, 0x6DC9C883
+		pio2_1  = 1.57079632673412561417e+00 // 0x3FF921FB, 0x54400000
+		pio2_1t = 6.07710050650619224932e-11 // 0x3DD0B461, 0x1A626331
+		pio2_2  = 6.07710050630396597660e-11 // 0x3DD0B461, 0x1A600000
+		pio2_2t = 2.02226624879595063154e-21 // 0x3BA3198A, 0x2E037073
+		pio2_3  = 2.02226624871116645580e-21 // 0x3BA3198A, 0x2E000000
+		pio2_3t = 8.47842766036889956997e-32 // 0x397B839A, 0x252049C1
+	)
+
+	hx := highWord(x)
+	ix := hx & 0x7FFFFFFF
+	if ix <= 0x3FE921FB { // |x| ~<= pi/4
+		return 0, x, 0
+	}
+
+	if ix < 0x4002D97C { // |x| < 3pi/4
+		if hx > 0 {
+			z := x - pio2_1
+			if ix != 0x3FF921FB {
+				y0 = z - pio2_1t
+				y1 = (z - y0) - pio2_1t
+			} else { // near pi/2
+				z -= pio2_2
+				y0 = z - pio2_2t
+				y1 = (z - y0) - pio2_2t
+			}
+			return 1, y0, y1
+		}
+
+		z := x + pio2_1
+		if ix != 0x3FF921FB {
+			y0 = z + pio2_1t
+			y1 = (z - y0) + pio2_1t
+		} else {
+			z += pio2_2
+			y0 = z + pio2_2t
+			y1 = (z - y0) + pio2_2t
+		}
+		return -1, y0, y1
+	}
+
+	if ix <= 0x413921FB { // |x| ~<= 2^19*(pi/2)
+		t := math.Abs(x)
+		n = int32(t*invpio2 + 0.5)
+		fn := float64(n)
+		r := t - fn*pio2_1
+		w := fn * pio2_1t
+		if n < 32 && ix != npio2HighWords[n-1] {
+			y0 = r - w
+		} else {
+			j := ix >> 20
+			y0 = r - w
+			i := j - ((highWord(y0) >> 20) & 0x7FF)
+			if i > 16 { // a 2nd iteration is needed
+				t = r
+				w = fn * pio2_2
+				r = t - w
+				w = fn*pio2_2t - ((t - r) - w)
+				y0 = r - w
+				i = j - ((highWord(y0) >> 20) & 0x7FF)
+				if i > 49 { // a 3rd iteration is needed
+					t = r
+					w = fn * pio2_3
+					r = t - w
+					w = fn*pio2_3t - ((t - r) - w)
+					y0 = r - w
+				}
+			}
+		}
+		y1 = (r - y0) - w
+		if hx < 0 {
+			return -n, -y0, -y1
+		}
+		return n, y0, y1
+	}
+
+	if ix >= 0x7FF00000 { // Infinity or NaN
+		return 0, x - x, x - x
+	}
+
+	// z = scalbn(|x|, ilogb(x)-23)
+	e0 := (ix >> 20) - 1046
+	z := fromWords(ix-e0<<20, lowWord(x))
+	var tx [3]float64
+	for i := 0; i < 2; i++ {
+		tx[i] = float64(int32(z))
+		z = (z - tx[i]) * two24
+	}
+	tx[2] = z
+	nx := 3
+	for tx[nx-1] == 0 {
+		nx--
+	}
+
+	n, y0, y1 = kernelRemPio2(tx[:nx], e0)
+	if hx < 0 {
+		return -n, -y0, -y1
+	}
+	return n, y0, y1
+}
+
+// kernelRemPio2 reduces the large number made of the 24-bit chunks x times
+// 2^e0 by multiples of pi/2, with the bits of 2/pi that matter to it
+func kernelRemPio2(x []float64, e0 int32) (int32, float64, float64) {
+	const (
+		jk     = 4
+		two24  = 1.67772160000000000000e+07
+		twon24 = 5.96046447753906250000e-08
+	)
+	// pio2 is pi/2 in 24-bit chunks
+	pio2 := [...]float64{
+		1.57079625129699707031e+00, // 0x3FF921FB, 0x40000000
+		7.54978941586159635335e-08, // 0x3E74442D, 0x00000000
+		5.39030252995776476554e-15, // 0x3CF84698, 0x80000000
+		3.28200341580791294123e-22, // 0x3B78CC51, 0x60000000
+		1.27065575308067607349e-29, // 0x39F01B83, 0x80000000
+		1.22933308981111328932e-36, // 0x387A2520, 0x40000000
+		2.73370053816464559624e-44, // 0x36E38222, 0x80000000
+		2.16741683877804819444e-51, // 0x3569F31D, 0x00000000
+	}
+
+	var iq [20]int32
+	var f, fq, q [20]float64
+
+	jx := int32(len(x) - 1)
+	jv := (e0 - 3) / 24
+	if jv < 0 {
+		jv = 0
+	}
+	q0 := e0 - 24*(jv+1)
+
+	// f[0] to f[jx+jk] are the chunks of 2/pi that are needed
+	j := jv - jx
+	for i := int32(0); i <= jx+jk; i, j = i+1, j+1 {
+		if j >= 0 {
+			f[i] = float64(twoOverPi[j])
+		}
+	}
+
+	for i := int32(0); i <= jk; i++ {
+		fw := 0.0
+		for j := int32(0); j <= jx; j++ {
+			fw += x[j] * f[jx+i-j]
+		}
+		q[i] = fw
+	}
+
+	jz := int32(jk)
+	var n, ih int32
+	var z float64
+	for {
+		// distill q into iq reversingly
+		i := int32(0)
+		z = q[jz]
+		for j := jz; j > 0; i, j = i+1, j-1 {
+			fw := float64(int32(twon24 * z))
+			iq[i] = int32(z - two24*fw)
+			z = q[j-1] + fw
+		}
+
+		z = math.Ldexp(z, int(q0))
+		z -= 8 * math.Floor(z*0.125) // trim off the integer >= 8
+		n = int32(z)
+		z -= float64(n)
+		ih = 0
+		if q0 > 0 { // iq[jz-1] is needed to determine n
+			i := iq[jz-1] >> uint(24-q0)
+			n += i
+			iq[jz-1] -= i << uint(24-q0)
+			ih = iq[jz-1] >> uint(23-q0)
+		} else if q0 == 0 {
+			ih = iq[jz-1] >> 23
+		} else if z >= 0.5 {
+			ih = 2
+		}
+
+		if ih > 0 { // q > 0.5
+			n++
+			carry := int32(0)
+			for i := int32(0); i < jz; i++ { // compute 1-q
+				j := iq[i]
+				if carry == 0 {
+					if j != 0 {
+						carry = 1
+						iq[i] = 0x1000000 - j
+					}
+				} else {
+					iq[i] = 0xFFFFFF - j
+				}
+			}
+			switch q0 {
+			case 1:
+				iq[jz-1] &= 0x7FFFFF
+			case 2:
+				iq[jz-1] &= 0x3FFFFF
+			}
+			if ih == 2 {
+				z = 1 - z
+				if carry != 0 {
+					z -= math.Ldexp(1, int(q0))
+				}
+			}
+		}
+
+		// check if more chunks of 2/pi are needed
+		if z != 0 {
+			break
+		}
+		j := int32(0)
+		for i := jz - 1; i >= jk; i-- {
+			j |= iq[i]
+		}
+		if j != 0 {
+			break
+		}
+
+		k := int32(1)
+		for iq[jk-k] == 0 {
+			k++
+		}
+		for i := jz + 1; i <= jz+k; i++ {
+			f[jx+i] = float64(twoOverPi[jv+i])
+			fw := 0.0
+			for j := int32(0); j <= jx; j++ {
+				fw += x[j] * f[jx+i-j]
+			}
+			q[i] = fw
+		}
+		jz += k
+	}
+
+	// chop off the zero terms
+	if z == 0 {
+		jz--
+		q0 -= 24
+		for iq[jz] == 0 {
+			jz--
+			q0 -= 24
+		}
+	} else { // break z into 24 bits if necessary
+		z = math.Ldexp(z, -int(q0))
+		if z >= two24 {
+			fw := float64(int32(twon24 * z))
+			iq[jz] = int32(z - two24*fw)
+			jz++
+			q0 += 24
+			iq[jz] = int32(fw)
+		} else {
+			iq[jz] = int32(z)
+		}
+	}
+
+	fw := math.Ldexp(1, int(q0))
+	for i := jz; i >= 0; i-- {
+		q[i] = fw * float64(iq[i])
+		fw *= twon24
+	}
+
+	for i := jz; i >= 0; i-- {
+		fw := 0.0
+		for k := int32(0); k <= jk && k <= jz-i; k++ {
+			fw += pio2[k] * q[i+k]
+		}
+		fq[jz-i] = fw
+	}
+
+	fw = 0
+	for i := jz; i >= 0; i-- {
+		fw += fq[i]
+	}
+	y0 := fw
+	fw = fq[0] - fw
+	for i := int32(1); i <= jz; i++ {
+		fw += fq[i]
+	}
+	y1 := fw
+	if ih != 0 {
+		y0, y1 = -y0, -y1
+	}
+
+	return n & 7, y0, y1
+}
+
+// kernelSin is fdlibmSin(x+y) for x+y in [-pi/4, pi/4], where y is the tail of x
+// if tail is set
+func kernelSin(x, y float64, tail bool) float64 {
+	const (
+		S1 = -1.66666666666666324348e-01 // 0xBFC55555, 0x55555549
+		S2 = 8.33333333332248946124e-03  // 0x3F811111, 0x1110F8A6
+		S3 = -1.98412698298579493134e-04 // 0xBF2A01A0, 0x19C161D5
+		S4 = 2.75573137070700676789e-06  // 0x3EC71DE3, 0x57B1FE7D
+		S5 = -2.50507602534068634195e-08 // 0xBE5AE5E6, 0x8A2B9CEB
+		S6 = 1.58969099521155010221e-10  // 0x3DE5D93A, 0x5ACFD57C
+	)
+
+	if highWord(x)&0x7FFFFFFF < 0x3E400000 && int32(x) == 0 { // |x| < 2^-27
+		return x
+	}
+
+	z := x * x
+	v := z * x
+	r := S2 + z*(S3+z*(S4+z*(S5+z*S6)))
+	if !tail {
+		return x + v*(S1+z*r)
+	}
+	return x - ((z*(0.5*y-v*r) - y) - v*S1)
+}
+
+// kernelCos is fdlibmCos(x+y) for x+y in [-pi/4, pi/4], where y is the tail of x
+func kernelCos(x, y float64) float64 {
+	const (
+		C1 = 4.16666666666666019037e-02  // 0x3FA55555, 0x5555554C
+		C2 = -1.38888888888741095749e-03 // 0xBF56C16C, 0x16C15177
+		C3 = 2.48015872894767294178e-05  // 0x3EFA01A0, 0x19CB1590
+		C4 = -2.75573143513906633035e-07 // 0xBE927E4F, 0x809C52AD
+		C5 = 2.08757232129817482790e-09  // 0x3E21EE9E, 0xBDB4B1C4
+		C6 = -1.13596475577881948265e-11 // 0xBDA8FAE9, 0xBE8838D4
+	)
+
+	ix := highWord(x) & 0x7FFFFFFF
+	if ix < 0x3E400000 && int32(x) == 0 { // |x| < 2^-27
+		return 1
+	}
+
+	z := x * x
+	r := z * (C1 + z*(C2+z*(C3+z*(C4+z*(C5+z*C6)))))
+	if ix < 0x3FD33333 { // |x| < 0.3
+		return 1 - (0.5*z - (z*r - x*y))
+	}
+
+	qx := 0.28125
+	if ix <= 0x3FE90000 { // |x| <= 0.78125
+		qx = fromWords(ix-0x00200000, 0) // x/4
+	}
+	hz := 0.5*z - qx
+	a := 1 - qx
+	return a - (hz - (z*r - x*y))
+}
+
+// kernelTan is fdlibmTan(x+y) for x+y in [-pi/4, pi/4], where y is the tail of x,
+// or -1/fdlibmTan(x+y) if iy is -1
+func kernelTan(x, y float64, iy int32) float64 {
+	const (
+		pio4   = 7.85398163397448278999e-01 // 0x3FE921FB, 0x54442D18
+		pio4lo = 3.06161699786838301793e-17 // 0x3C81A626, 0x33145C07
+	)
+	T := [...]float64{
+		3.33333333333334091986e-01,  // 0x3FD55555, 0x55555563
+		1.33333333333201242699e-01,  // 0x3FC11111, 0x1110FE7A
+		5.39682539762260521377e-02,  // 0x3FABA1BA, 0x1BB341FE
+		2.18694882948595424599e-02,  // 0x3F9664F4, 0x8406D637
+		8.86323982359930005737e-03,  // 0x3F8226E3, 0xE96E8493
+		3.59207910759131235356e-03,  // 0x3F6D6D22, 0xC9560328
+		1.45620945432529025516e-03,  // 0x3F57DBC8, 0xFEE08315
+		5.88041240820264096874e-04,  // 0x3F4344D8, 0xF2F26501
+		2.46463134818469906812e-04,  // 0x3F3026F7, 0x1A8D1068
+		7.81794442939557092300e-05,  // 0x3F147E88, 0xA03792A6
+		7.14072491382608190305e-05,  // 0x3F12B80F, 0x32F0A7E9
+		-1.85586374855275456654e-05, // 0xBEF375CB, 0xDB605373
+		2.59073051863633712884e-05,  // 0x3EFB2A70, 0x74BF7AD4
+	}
+
+	hx := highWord(x)
+	ix := hx & 0x7FFFFFFF
+	if ix < 0x3E300000 && int32(x) == 0 { // |x| < 2^-28
+		if (ix|int32(lowWord(x)))|(iy+1) == 0 {
+			return 1 / math.Abs(x)
+		}
+		if iy == 1 {
+			return x
+		}
+		return negativeReciprocal(x, y, x+y)
+	}
+
+	if ix >= 0x3FE59428 { // |x| >= 0.6744
+		if hx < 0 {
+			x = -x
+			y = -y
+		}
+		x = (pio4 - x) + (pio4lo - y)
+		y = 0
+	}
+	z := x * x
+	w := z * z
+	// x^5*(T[1]+x^2*T[2]+...) is split into the odd and the even terms
+	r := T[1] + w*(T[3]+w*(T[5]+w*(T[7]+w*(T[9]+w*T[11]))))
+	v := z * (T[2] + w*(T[4]+w*(T[6]+w*(T[8]+w*(T[10]+w*T[12])))))
+	s := z * x
+	r = y + z*(s*(r+v)+y)
+	r += T[0] * s
+	w = x + r
+	if ix >= 0x3FE59428 {
+		v = float64(iy)
+		return float64(1-((hx>>30)&2)) * (v - 2*(x-(w*w/(w+v)-r)))
+	}
+	if iy == 1 {
+		return w
+	}
+	return negativeReciprocal(x, r, w)
+}
+
+// negativeReciprocal computes -1/w accurately, where w is x+r rounded
+func negativeReciprocal(x, r, w float64) float64 {
+	z := clearLowWord(w)
+	v := r - (z - x) // z+v = r+x
+	a := -1 / w
+	t := clearLowWord(a)
+	s := 1 + t*z
+	return t + a*(s+t*v)
+}
+
+func fdlibmSin(x float64) float64 {
+	ix := highWord(x) & 0x7FFFFFFF
+	switch {
+	case ix <= 0x3FE921FB:
+		return kernelSin(x, 0, false)
+	case ix >= 0x7FF00000:
+		return math.NaN()
+	}
+
+	n, y0, y1 := remPio2(x)
+	switch n & 3 {
+	case 0:
+		return kernelSin(y0, y1, true)
+	case 1:
+		return kernelCos(y0, y1)
+	case 2:
+		return -kernelSin(y0, y1, true)
+	default:
+		return -kernelCos(y0, y1)
+	}
+}
+
+func fdlibmCos(x float64) float64 {
+	ix := highWord(x) & 0x7FFFFFFF
+	switch {
+	case ix <= 0x3FE921FB:
+		return kernelCos(x, 0)
+	case ix >= 0x7FF00000:
+		return math.NaN()
+	}
+
+	n, y0, y1 := remPio2(x)
+	switch n & 3 {
+	case 0:
+		return kernelCos(y0, y1)
+	case 1:
+		return -kernelSin(y0, y1, true)
+	case 2:
+		return -kernelCos(y0, y1)
+	default:
+		return kernelSin(y0, y1, true)
+	}
+}
+
+func fdlibmTan(x float64) float64 {
+	ix := highWord(x) & 0x7FFFFFFF
+	switch {
+	case ix <= 0x3FE921FB:
+		return kernelTan(x, 0, 1)
+	case ix >= 0x7FF00000:
+		return math.NaN()
+	}
+
+	n, y0, y1 := remPio2(x)
+	return kernelTan(y0, y1, 1-((n&1)<<1))
+}
+
+const (
+	pio2Hi = 1.57079632679489655800e+00 // 0x3FF921FB, 0x54442D18
+	pio2Lo = 6.12323399573676603587e-17 // 0x3C91A626, 0x33145C07
+)
+
+// asinRatio is the rational approximation of (fdlibmAsin(x)-x)/x^3 in terms of
+// t = x^2
+func asinRatio(t float64) float64 {
+	const (
+		pS0 = 1.66666666666666657415e-01  // 0x3FC55555, 0x55555555
+		pS1 = -3.25565818622400915405e-01 // 0xBFD4D612, 0x03EB6F7D
+		pS2 = 2.01212532134862925881e-01  // 0x3FC9C155, 0x0E884455
+		pS3 = -4.00555345006794114027e-02 // 0xBFA48228, 0xB5688F3B
+		pS4 = 7.91534994289814532176e-04  // 0x3F49EFE0, 0x7501B288
+		pS5 = 3.47933107596021167570e-05  // 0x3F023DE1, 0x0DFDF709
+		qS1 = -2.40339491173441421878e+00 // 0xC0033A27, 0x1C8A2D4B
+		qS2 = 2.02094576023350569471e+00  // 0x40002AE5, 0x9C598AC8
+		qS3 = -6.88283971605453293030e-01 // 0xBFE6066C, 0x1B8D0159
+		qS4 = 7.70381505559019352791e-02  // 0x3FB3B8C5, 0xB12E9282
+	)
+
+	p := t * (pS0 + t*(pS1+t*(pS2+t*(pS3+t*(pS4+t*pS5)))))
+	q := 1 + t*(qS1+t*(qS2+t*(qS3+t*qS4)))
+	return p / q
+}
+
+func fdlibmAsin(x float64) float64 {
+	const pio4Hi = 7.85398163397448278999e-01 // 0x3FE921FB, 0x54442D18
+
+	hx := highWord(x)
+	ix := hx & 0x7FFFFFFF
+	if ix >= 0x3FF00000 { // |x| >= 1
+		if (ix-0x3FF00000)|int32(lowWord(x)) == 0 {
+			return x*pio2Hi + x*pio2Lo
+		}
+		return math.NaN()
+	}
+
+	if ix < 0x3FE00000 { // |x| < 0.5
+		if ix < 0x3E400000 { // |x| < 2^-27
+			return x
+		}
+		return x + x*asinRatio(x*x)
+	}
+
+	w := 1 - math.Abs(x)
+	t := w * 0.5
+	s := math.Sqrt(t)
+	if ix >= 0x3FEF3333 { // |x| > 0.975
+		t = pio2Hi - (2*(s+s*asinRatio(t)) - pio2Lo)
+	} else {
+		w = clearLowWord(s)
+		c := (t - w*w) / (s + w)
+		p := 2*s*asinRatio(t) - (pio2Lo - 2*c)
+		q := pio4Hi - 2*w
+		t = pio4Hi - (p - q)
+	}
+	if hx > 0 {
+		return t
+	}
+	return -t
+}
+
+func fdlibmAcos(x float64) float64 {
+	hx := highWord(x)
+	ix := hx & 0x7FFFFFFF
+	if ix >= 0x3FF00000 { // |x| >= 1
+		if (ix-0x3FF00000)|int32(lowWord(x)) == 0 {
+			if hx > 0 {
+				return 0
+			}
+			return math.Pi
+		}
+		return math.NaN()
+	}
+
+	switch {
+	case ix < 0x3FE00000: // |x| < 0.5
+		if ix <= 0x3C600000 { // |x| < 2^-57
+			return pio2Hi + pio2Lo
+		}
+		return pio2Hi - (x - (pio2Lo - x*asinRatio(x*x)))
+	case hx < 0: // x < -0.5
+		z := (1 + x) * 0.5
+		s := math.Sqrt(z)
+		w := asinRatio(z)*s - pio2Lo
+		return math.Pi - 2*(s+w)
+	default: // x > 0.5
+		z := (1 - x) * 0.5
+		s := math.Sqrt(z)
+		df := clearLowWord(s)
+		c := (z - df*df) / (s + df)
+		w := asinRatio(z)*s + c
+		return 2 * (df + w)
+	}
+}
+
+func fdlibmAtan(x float64) float64 {
+	atanHi := [...]float64{
+		4.63647609000806093515e-01, // fdlibmAtan(0.5)hi 0x3FDDAC67, 0x0561BB4F
+		7.85398163397448278999e-01, // fdlibmAtan(1.0)hi 0x3FE921FB, 0x54442D18
+		9.82793723247329054082e-01, // fdlibmAtan(1.5)hi 0x3FEF730B, 0xD281F69B
+		1.57079632679489655800e+00, // fdlibmAtan(inf)hi 0x3FF921FB, 0x54442D18
+	}
+	atanLo := [...]float64{
+		2.26987774529616870924e-17, // fdlibmAtan(0.5)lo 0x3C7A2B7F, 0x222F65E2
+		3.06161699786838301793e-17, // fdlibmAtan(1.0)lo 0x3C81A626, 0x33145C07
+		1.39033110312309984516e-17, // fdlibmAtan(1.5)lo 0x3C700788, 0x7AF0CBBD
+		6.12323399573676603587e-17, // fdlibmAtan(inf)lo 0x3C91A626, 0x33145C07
+	}
+	aT := [...]float64{
+		3.33333333333329318027e-01,  // 0x3FD55555, 0x5555550D
+		-1.99999999998764832476e-01, // 0xBFC99999, 0x9998EBC4
+		1.42857142725034663711e-01,  // 0x3FC24924, 0x920083FF
+		-1.11111104054623557880e-01, // 0xBFBC71C6, 0xFE231671
+		9.09088713343650656196e-02,  // 0x3FB745CD, 0xC54C206E
+		-7.69187620504482999495e-02, // 0xBFB3B0F2, 0xAF749A6D
+		6.66107313738753120669e-02,  // 0x3FB10D66, 0xA0D03D51
+		-5.83357013379057348645e-02, // 0xBFADDE2D, 0x52DEFD9A
+		4.97687799461593236017e-02,  // 0x3FA97B4B, 0x24760DEB
+		-3.65315727442169155270e-02, // 0xBFA2B444, 0x2C6A6C2F
+		1.62858201153657823623e-02,  // 0x3F90AD3A, 0xE322DA11
+	}
+
+	hx := highWord(x)
+	ix := hx & 0x7FFFFFFF
+	if ix >= 0x44100000 { // |x| >= 2^66
+		switch {
+		case math.IsNaN(x):
+			return x
+		case hx > 0:
+			return atanHi[3] + atanLo[3]
+		default:
+			return -atanHi[3] - atanLo[3]
+		}
+	}
+
+	id := -1
+	if ix < 0x3FDC0000 { // |x| < 0.4375
+		if ix < 0x3E400000 { // |x| < 2^-27
+			return x
+		}
+	} else {
+		x = math.Abs(x)
+		switch {
+		case ix < 0x3FE60000: // 7/16 <= |x| < 11/16
+			id, x = 0, (2*x-1)/(2+x)
+		case ix < 0x3FF30000: // 11/16 <= |x| < 19/16
+			id, x = 1, (x-1)/(x+1)
+		case ix < 0x40038000: // |x| < 2.4375
+			id, x = 2, (x-1.5)/(1+1.5*x)
+		default: // 2.4375 <= |x| < 2^66
+			id, x = 3, -1/x
+		}
+	}
+
+	z := x * x
+	w := z * z
+	// the sum of aT[i]*z^(i+1) is split into the odd and the even terms
+	s1 := z * (aT[0] + w*(aT[2]+w*(aT[4]+w*(aT[6]+w*(aT[8]+w*aT[10])))))
+	s2 := w * (aT[1] + w*(aT[3]+w*(aT[5]+w*(aT[7]+w*aT[9]))))
+	if id < 0 {
+		return x - x*(s1+s2)
+	}
+
+	z = atanHi[id] - ((x*(s1+s2) - atanLo[id]) - x)
+	if hx < 0 {
+		return -z
+	}
+	return z
+}
+
+func fdlibmAtan2(y, x float64) float64 {
+	const (
+		pio4 = 7.8539816339744827900e-01 // 0x3FE921FB, 0x54442D18
+		piLo = 1.2246467991473531772e-16 // 0x3CA1A626, 0x33145C07
+	)
+
+	if math.IsNaN(x) || math.IsNaN(y) {
+		return math.NaN()
+	}
+
+	hx, lx := highWord(x), lowWord(x)
+	ix := hx & 0x7FFFFFFF
+	hy, ly := highWord(y), lowWord(y)
+	iy := hy & 0x7FFFFFFF
+	if (hx-0x3FF00000)|int32(lx) == 0 { // x is 1
+		return fdlibmAtan(y)
+	}
+	m := ((hy >> 31) & 1) | ((hx >> 30) & 2) // 2*sign(x)+sign(y)
+
+	if iy|int32(ly) == 0 { // y is 0
+		switch m {
+		case 0, 1:
+			return y
+		case 2:
+			return math.Pi
+		default:
+			return -math.Pi
+		}
+	}
+
+	if ix|int32(lx) == 0 { // x is 0
+		if hy < 0 {
+			return -pio2Hi
+		}
+		return pio2Hi
+	}
+
+	if ix == 0x7FF00000 { // x is Infinity
+		if iy == 0x7FF00000 {
+			switch m {
+			case 0:
+				return pio4
+			case 1:
+				return -pio4
+			case 2:
+				return 3 * pio4
+			default:
+				return -3 * pio4
+			}
+		}
+
+		switch m {
+		case 0:
+			return 0
+		case 1:
+			return math.Copysign(0, -1)
+		case 2:
+			return math.Pi
+		default:
+			return -math.Pi
+		}
+	}
+
+	if iy == 0x7FF00000 { // y is Infinity
+		if hy < 0 {
+			return -pio2Hi
+		}
+		return pio2Hi
+	}
+
+	var z float64
+	k := (iy - ix) >> 20
+	switch {
+	case k > 60: // |y/x| > 2^60
+		z = pio2Hi + 0.5*piLo
+		m &= 1
+	case hx < 0 && k < -60: // 0 > |y|/x > -2^-60
+		z = 0
+	default:
+		z = fdlibmAtan(math.Abs(y / x))
+	}
+
+	switch m {
+	case 0:
+		return z
+	case 1:
+		return -z
+	case 2:
+		return math.Pi - (z - piLo)
+	default:
+		return (z - piLo) - math.Pi
+	}
+}
+
+func fdlibmExp(x float64) float64 {
+	const (
+		twom1000   = 9.33263618503218878990e-302 // 2^-1000
+		two1023    = 8.988465674311579539e307    // 2^1023
+		oThreshold = 7.09782712893383973096e+02  // 0x40862E42, 0xFEFA39EF
+		uThreshold = -7.45133219101941108420e+02 // 0xC0874910, 0xD52D3051
+		invln2     = 1.44269504088896338700e+00  // 0x3FF71547, 0x652B82FE
+		P1         = 1.66666666666666019037e-01  // 0x3FC55555, 0x5555553E
+		P2         = -2.77777777770155933842e-03 // 0xBF66C16C, 0x16BEBD93
+		P3         = 6.61375632143793436117e-05  // 0x3F11566A, 0xAF25DE2C
+		P4         = -1.65339022054652515390e-06 // 0xBEBBBD41, 0xC5D26BF1
+		P5         = 4.13813679705723846039e-08  // 0x3E663769, 0x72BEA4D0
+	)
+
+	hx := highWord(x)
+	negative := hx < 0
+	hx &= 0x7FFFFFFF
+
+	if hx >= 0x40862E42 { // |x| >= 709.78
+		switch {
+		case math.IsNaN(x):
+			return x
+		case math.IsInf(x, 1):
+			return x
+		case math.IsInf(x, -1):
+			return 0
+		case x > oThreshold:
+			return math.Inf(1)
+		case x < uThreshold:
+			return 0
+		}
+	}
+
+	var hi, lo float64
+	var k int32
+	switch {
+	case hx > 0x3FD62E42: // |x| > 0.5*ln2
+		if hx < 0x3FF0A2B2 { // |x| < 1.5*ln2
+			if x == 1 {
+				return math.E
+			}
+			if negative {
+				hi, lo, k = x+ln2Hi, -ln2Lo, -1
+			} else {
+				hi, lo, k = x-ln2Hi, ln2Lo, 1
+			}
+		} else {
+			if negative {
+				k = int32(invln2*x - 0.5)
+			} else {
+				k = int32(invln2*x + 0.5)
+			}
+			t := float64(k)
+			hi = x - t*ln2Hi // t*ln2Hi is exact here
+			lo = t * ln2Lo
+		}
+		x = hi - lo
+	case hx < 0x3E300000: // |x| < 2^-28
+		return 1 + x
+	}
+
+	// x is now in the primary range
+	t := x * x
+	c := x - t*(P1+t*(P2+t*(P3+t*(P4+t*P5))))
+	if k == 0 {
+		return 1 - ((x*c)/(c-2) - x)
+	}
+
+	y := 1 - ((lo - (x*c)/(2-c)) - hi)
+	switch {
+	case k == 1024:
+		return y * 2 * two1023
+	case k >= -1021:
+		return y * fromWords(0x3FF00000+k<<20, 0)
+	default:
+		return y * fromWords(0x3FF00000+(k+1000)<<20, 0) * twom1000
+	}
+}
+
+const (
+	ln2Hi = 6.93147180369123816490e-01 // 0x3FE62E42, 0xFEE00000
+	ln2Lo = 1.90821492927058770002e-10 // 0x3DEA39EF, 0x35793C76
+	two54 = 1.80143985094819840000e+16 // 0x43500000, 0x00000000
+	lg1   = 6.666666666666735130e-01   // 0x3FE55555, 0x55555593
+	lg2   = 3.999999999940941908e-01   // 0x3FD99999, 0x9997FA04
+	lg3   = 2.857142874366239149e-01   // 0x3FD24924, 0x94229359
+	lg4   = 2.222219843214978396e-01   // 0x3FCC71C5, 0x1D8E78AF
+	lg5   = 1.818357216161805012e-01   // 0x3FC74664, 0x96CB03DE
+	lg6   = 1.531383769920937332e-01   // 0x3FC39A09, 0xD078C69F
+	lg7   = 1.479819860511658591e-01   // 0x3FC2F112, 0xDF3E5244
+)
+
+// normalizeLog splits a finite positive x into 2^k*f, where f is in
+// [sqrt(2)/2, sqrt(2)], or returns the result of the log of x if x is
+// special
+func normalizeLog(x float64) (k int32, f float64, special bool) {
+	hx, lx := highWord(x), lowWord(x)
+	if hx < 0x00100000 { // x < 2^-1022
+		switch {
+		case (hx&0x7FFFFFFF)|int32(lx) == 0:
+			return 0, math.Inf(-1), true
+		case hx < 0:
+			return 0, math.NaN(), true
+		}
+		k -= 54
+		x *= two54 // scale up a subnormal number
+		hx = highWord(x)
+	}
+	if hx >= 0x7FF00000 {
+		return 0, x + x, true
+	}
+
+	k += (hx >> 20) - 1023
+	hx &= 0x000FFFFF
+	i := (hx + 0x95F64) & 0x100000
+	k += i >> 20
+	return k, setHighWord(x, hx|(i^0x3FF00000)), false // x or x/2
+}
+
+func fdlibmLog(x float64) float64 {
+	k, x, special := normalizeLog(x)
+	if special {
+		return x
+	}
+
+	hx := highWord(x) & 0x000FFFFF
+	f := x - 1
+	dk := float64(k)
+	if (0x000FFFFF & (2 + hx)) < 3 { // |f| < 2^-20
+		if f == 0 {
+			if k == 0 {
+				return 0
+			}
+			return dk*ln2Hi + dk*ln2Lo
+		}
+
+		R := f * f * (0.5 - 0.33333333333333333*f)
+		if k == 0 {
+			return f - R
+		}
+		return dk*ln2Hi - ((R - dk*ln2Lo) - f)
+	}
+
+	s := f / (2 + f)
+	z := s * s
+	w := z * z
+	t1 := w * (lg2 + w*(lg4+w*lg6))
+	t2 := z * (lg1 + w*(lg3+w*(lg5+w*lg7)))
+	R := t2 + t1
+	if (hx-0x6147A)|(0x6B851-hx) > 0 {
+		hfsq := 0.5 * f * f
+		if k == 0 {
+			return f - (hfsq - s*(hfsq+R))
+		}
+		return dk*ln2Hi - ((hfsq - (s*(hfsq+R) + dk*ln2Lo)) - f)
+	}
+
+	if k == 0 {
+		return f - s*(f-R)
+	}
+	return dk*ln2Hi - ((s*(f-R) - dk*ln2Lo) - f)
+}
+
+// kernelLog1p is fdlibmLog(1+f)-f+f*f/2 for f in [sqrt(2)/2-1, sqrt(2)-1]
+func kernelLog1p(f float64) float64 {
+	s := f / (2 + f)
+	z := s * s
+	w := z * z
+	t1 := w * (lg2 + w*(lg4+w*lg6))
+	t2 := z * (lg1 + w*(lg3+w*(lg5+w*lg7)))
+	R := t2 + t1
+	hfsq := 0.5 * f * f
+	return s * (hfsq + R)
+}
+
+func fdlibmLog2(x float64) float64 {
+	const (
+		ivln2Hi = 1.44269504072144627571e+00 // 0x3FF71547, 0x65200000
+		ivln2Lo = 1.67517131648865118353e-10 // 0x3DE705FC, 0x2EEFA200
+	)
+
+	if x == 1 {
+		return 0
+	}
+	k, x, special := normalizeLog(x)
+	if special {
+		return x
+	}
+
+	y := float64(k)
+	f := x - 1
+	hfsq := 0.5 * f * f
+	r := kernelLog1p(f)
+	hi := clearLowWord(f - hfsq)
+	lo := (f - hi) - hfsq + r
+	valHi := hi * ivln2Hi
+	valLo := (lo+hi)*ivln2Lo + lo*ivln2Hi
+
+	w := y + valHi
+	valLo += (y - w) + valHi
+	valHi = w
+	return valLo + valHi
+}
+
+func fdlibmLog10(x float64) float64 {
+	const (
+		ivln10    = 4.34294481903251816668e-01 // 0x3FDBCB7B, 0x1526E50E
+		log10_2Hi = 3.01029995663611771306e-01 // 0x3FD34413, 0x509F6000
+		log10_2Lo = 3.69423907715893078616e-13 // 0x3D59FEF3, 0x11F12B36
+	)
+
+	hx, lx := highWord(x), lowWord(x)
+	k := int32(0)
+	if hx < 0x00100000 { // x < 2^-1022
+		switch {
+		case (hx&0x7FFFFFFF)|int32(lx) == 0:
+			return math.Inf(-1)
+		case hx < 0:
+			return math.NaN()
+		}
+		k -= 54
+		x *= two54 // scale up a subnormal number
+		hx = highWord(x)
+	}
+	if hx >= 0x7FF00000 {
+		return x + x
+	}
+
+	k += (hx >> 20) - 1023
+	i := int32(uint32(k) >> 31)
+	hx = (hx & 0x000FFFFF) | ((0x3FF - i) << 20)
+	y := float64(k + i)
+	x = setHighWord(x, hx)
+	z := y*log10_2Lo + ivln10*fdlibmLog(x)
+	return z + y*log10_2Hi
+}
+
+func fdlibmCbrt(x float64) float64 {
+	const (
+		B1 = 715094163 // (1023-1023/3-0.03306235651)*2^20
+		B2 = 696219795 // (1023-1023/3-54/3-0.03306235651)*2^20
+		// the polynomial approximates 1/fdlibmCbrt(x) to 23 bits
+		P0 = 1.87595182427177009643   // 0x3FFE03E6, 0x0F61E692
+		P1 = -1.88497979543377169875  // 0xBFFE28E0, 0x92F02420
+		P2 = 1.621429720105354466140  // 0x3FF9F160, 0x4A49D6C2
+		P3 = -0.758397934778766047437 // 0xBFE844CB, 0xBEE751D9
+		P4 = 0.145996192886612446982  // 0x3FC2B000, 0xD4E4EDD7
+	)
+
+	hx := highWord(x)
+	sign := uint32(hx) & 0x80000000
+	hx &= 0x7FFFFFFF
+	if hx >= 0x7FF00000 || x == 0 {
+		return x
+	}
+
+	// rough cbrt to 5 bits
+	var t float64
+	if hx < 0x00100000 { // subnormal
+		t = fromWords(0x43500000, 0) * x
+		t = fromWords(int32(sign|(uint32(highWord(t))&0x7FFFFFFF)/3+B2), 0)
+	} else {
+		t = fromWords(int32(sign|uint32(hx/3+B1)), 0)
+	}
+
+	// new cbrt to 23 bits
+	r := (t * t) * (t / x)
+	t = t * ((P0 + r*(P1+r*P2)) + ((r*r)*r)*(P3+r*P4))
+
+	// round t away from zero to 23 bits
+	t = math.Float64frombits((math.Float64bits(t) + 0x80000000) & 0xFFFFFFFFC0000000)
+
+	// one step of Newton's method to 53 bits
+	s := t * t
+	r = x / s
+	w := t + t
+	r = (r - t) / (w + r)
+	return t + t*r
+}
+
+func fdlibmSinh(x float64) float64 {
+	const overflow = 710.4758600739439
+
+	h := 0.5
+	if x < 0 {
+		h = -h
+	}
+	ax := math.Abs(x)
+	switch {
+	case ax < 22:
+		if ax < 1.0/(1<<28) { // 2**-28
+			return x
+		}
+		t := math.Expm1(ax)
+		if ax < 1 {
+			return h * (2*t - t*t/(t+1))
+		}
+		return h * (t + t/(t+1))
+	case ax < 709.7822265625: // fdlibmLog(maxdouble)
+		return h * fdlibmExp(ax)
+	case ax <= overflow:
+		w := fdlibmExp(0.5 * ax)
+		t := h * w
+		return t * w
+	}
+
+	return x * 1.0e307
+}
+
+func fdlibmCosh(x float64) float64 {
+	const overflow = 710.4758600739439
+
+	ix := highWord(x) & 0x7FFFFFFF
+	switch {
+	case ix < 0x3FD62E43: // |x| < 0.5*ln2
+		t := math.Expm1(math.Abs(x))
+		w := 1 + t
+		if ix < 0x3C800000 { // |x| < 2^-55
+			return w
+		}
+		return 1 + (t*t)/(w+w)
+	case ix < 0x40360000: // |x| < 22
+		t := fdlibmExp(math.Abs(x))
+		return 0.5*t + 0.5/t
+	case ix < 0x40862E42: // |x| < fdlibmLog(maxdouble)
+		return 0.5 * fdlibmExp(math.Abs(x))
+	case math.Abs(x) <= overflow:
+		w := fdlibmExp(0.5 * math.Abs(x))
+		t := 0.5 * w
+		return t * w
+	case ix >= 0x7FF00000: // Infinity or NaN
+		return x * x
+	}
+
+	return math.Inf(1)
+}
+
+func fdlibmTanh(x float64) float64 {
+	jx := highWord(x)
+	ix := jx & 0x7FFFFFFF
+	if ix >= 0x7FF00000 { // Infinity or NaN
+		if jx >= 0 {
+			return 1/x + 1
+		}
+		return 1/x - 1
+	}
+
+	var z float64
+	switch {
+	case ix < 0x3E300000: // |x| < 2^-28
+		return x
+	case ix >= 0x40360000: // |x| >= 22
+		z = 1
+	case ix >= 0x3FF00000: // |x| >= 1
+		t := math.Expm1(2 * math.Abs(x))
+		z = 1 - 2/(t+2)
+	default:
+		t := math.Expm1(-2 * math.Abs(x))
+		z = -t / (t + 2)
+	}
+	if jx >= 0 {
+		return z
+	}
+	return -z
+}
+
+func fdlibmAcosh(x float64) float64 {
+	const ln2 = 6.93147180559945286227e-01 // 0x3FE62E42, 0xFEFA39EF
+
+	hx := highWord(x)
+	switch {
+	case hx < 0x3FF00000 || math.IsNaN(x): // x < 1
+		return math.NaN()
+	case hx >= 0x41B00000: // x > 2^28
+		if hx >= 0x7FF00000 {
+			return x
+		}
+		return fdlibmLog(x) + ln2
+	case x == 1:
+		return 0
+	case hx > 0x40000000: // 2^28 > x > 2
+		t := x * x
+		return fdlibmLog(2*x - 1/(x+math.Sqrt(t-1)))
+	}
+
+	t := x - 1 // 1 < x < 2
+	return math.Log1p(t + math.Sqrt(2*t+t*t))
+}
+
+func fdlibmAsinh(x float64) float64 {
+	const ln2 = 6.93147180559945286227e-01 // 0x3FE62E42, 0xFEFA39EF
+
+	hx := highWord(x)
+	ix := hx & 0x7FFFFFFF
+	if ix >= 0x7FF00000 || ix < 0x3E300000 { // Infinity, NaN or |x| < 2^-28
+		return x
+	}
+
+	var w float64
+	switch {
+	case ix > 0x41B00000: // |x| > 2^28
+		w = fdlibmLog(math.Abs(x)) + ln2
+	case ix > 0x40000000: // 2^28 > |x| > 2
+		t := math.Abs(x)
+		w = fdlibmLog(2*t + 1/(math.Sqrt(x*x+1)+t))
+	default:
+		t := x * x
+		w = math.Log1p(math.Abs(x) + t/(1+math.Sqrt(1+t)))
+	}
+	if hx > 0 {
+		return w
+	}
+	return -w
+}
+
+func fdlibmAtanh(x float64) float64 {
+	hx := highWord(x)
+	ix := hx & 0x7FFFFFFF
+	ax := math.Abs(x)
+	switch {
+	case ax > 1 || math.IsNaN(x):
+		return math.NaN()
+	case ax == 1:
+		return x / 0
+	case ix < 0x3E300000: // |x| < 2^-28
+		return x
+	}
+
+	var t float64
+	if ix < 0x3FE00000 { // |x| < 0.5
+		t = ax + ax
+		t = 0.5 * math.Log1p(t+t*ax/(1-ax))
+	} else {
+		t = 0.5 * math.Log1p((ax+ax)/(1-ax))
+	}
+	if hx >= 0 {
+		return t
+	}
+	return -t
+}
+
+// fdlibmPow is x**y as fdlibm computes it
+func fdlibmPow(x, y float64) float64 {
+	const (
+		two53   = 9007199254740992.0          // 0x43400000, 0x00000000
+		L1      = 5.99999999999994648725e-01  // 0x3FE33333, 0x33333303
+		L2      = 4.28571428578550184252e-01  // 0x3FDB6DB6, 0xDB6FABFF
+		L3      = 3.33333329818377432918e-01  // 0x3FD55555, 0x518F264D
+		L4      = 2.72728123808534006489e-01  // 0x3FD17460, 0xA91D4101
+		L5      = 2.30660745775561754067e-01  // 0x3FCD864A, 0x93C9DB65
+		L6      = 2.06975017800338417784e-01  // 0x3FCA7E28, 0x4A454EEF
+		P1      = 1.66666666666666019037e-01  // 0x3FC55555, 0x5555553E
+		P2      = -2.77777777770155933842e-03 // 0xBF66C16C, 0x16BEBD93
+		P3      = 6.61375632143793436117e-05  // 0x3F11566A, 0xAF25DE2C
+		P4      = -1.65339022054652515390e-06 // 0xBEBBBD41, 0xC5D26BF1
+		P5      = 4.13813679705723846039e-08  // 0x3E663769, 0x72BEA4D0
+		lg2     = 6.93147180559945286227e-01  // 0x3FE62E42, 0xFEFA39EF
+		lg2Hi   = 6.93147182464599609375e-01  // 0x3FE62E43, 0x00000000
+		lg2Lo   = -1.90465429995776804525e-09 // 0xBE205C61, 0x0CA86C39
+		ovt     = 8.0085662595372944372e-17   // -(1024-fdlibmLog2(ovfl+.5ulp))
+		cp      = 9.61796693925975554329e-01  // 0x3FEEC709, 0xDC3A03FD = 2/(3ln2)
+		cpHi    = 9.61796700954437255859e-01  // 0x3FEEC709, 0xE0000000 = (float)cp
+		cpLo    = -7.02846165095275826516e-09 // 0xBE3E2FE0, 0x145B01F5 = tail of cpHi
+		ivln2   = 1.44269504088896338700e+00  // 0x3FF71547, 0x652B82FE = 1/ln2
+		ivln2Hi = 1.44269502162933349609e+00  // 0x3FF71547, 0x60000000 = 24b 1/ln2
+		ivln2Lo = 1.92596299112661746887e-08  // 0x3E54AE0B, 0xF85DDF44 = 1/ln2 tail
+	)
+	bp := [...]float64{1, 1.5}
+	dpHi := [...]float64{0, 5.84962487220764160156e-01} // 0x3FE2B803, 0x40000000
+	dpLo := [...]float64{0, 1.35003920212974897128e-08} // 0x3E4CFDEB, 0x43CFD006
+
+	hx, lx := highWord(x), lowWord(x)
+	hy, ly := highWord(y), lowWord(y)
+	ix, iy := hx&0x7FFFFFFF, hy&0x7FFFFFFF
+
+	if iy|int32(ly) == 0 { // y is 0
+		return 1
+	}
+	if math.IsNaN(x) || math.IsNaN(y) {
+		return math.NaN()
+	}
+
+	// yisint is 0 if y isn't an integer, 1 if it's odd and 2 if it's even,
+	// which matters when x < 0
+	yisint := int32(0)
+	if hx < 0 {
+		if iy >= 0x43400000 {
+			yisint = 2
+		} else if iy >= 0x3FF00000 {
+			k := (iy >> 20) - 0x3FF
+			if k > 20 {
+				j := ly >> uint(52-k)
+				if j<<uint(52-k) == ly {
+					yisint = 2 - int32(j&1)
+				}
+			} else if ly == 0 {
+				j := iy >> uint(20-k)
+				if j<<uint(20-k) == iy {
+					yisint = 2 - (j & 1)
+				}
+			}
+		}
+	}
+
+	// the special values of y
+	if ly == 0 {
+		if iy == 0x7FF00000 { // y is Infinity
+			switch {
+			case (ix-0x3FF00000)|int32(lx) == 0:
+				return y - y // 1**Infinity is NaN
+			case ix >= 0x3FF00000: // (|x|>1)**+-Infinity is Infinity or 0
+				if hy >= 0 {
+					return y
+				}
+				return 0
+			default: // (|x|<1)**-,+Infinity is Infinity or 0
+				if hy < 0 {
+					return -y
+				}
+				return 0
+			}
+		}
+		if iy == 0x3FF00000 { // y is +-1
+			if hy < 0 {
+				return 1 / x
+			}
+			return x
+		}
+		if hy == 0x40000000 { // y is 2
+			return x * x
+		}
+		if hy == 0x3FE00000 && hx >= 0 { // y is 0.5
+			return math.Sqrt(x)
+		}
+	}
+
+	ax := math.Abs(x)
+	// the special values of x
+	if lx == 0 && (ix == 0x7FF00000 || ix == 0 || ix == 0x3FF00000) { // +-0, +-Infinity or +-1
+		z := ax
+		if hy < 0 {
+			z = 1 / z
+		}
+		if hx < 0 {
+			if (ix-0x3FF00000)|yisint == 0 {
+				z = math.NaN() // (-1)**non-int is NaN
+			} else if yisint == 1 {
+				z = -z // (x<0)**odd = -(|x|**odd)
+			}
+		}
+		return z
+	}
+
+	n := (hx >> 31) + 1
+	if n|yisint == 0 { // (x<0)**non-int is NaN
+		return math.NaN()
+	}
+
+	s := 1.0 // the sign of the result
+	if n|(yisint-1) == 0 {
+		s = -1
+	}
+
+	var t1, t2 float64
+	if iy > 0x41E00000 { // |y| > 2^31
+		if iy > 0x43F00000 { // |y| > 2^64 must overflow or underflow
+			if ix <= 0x3FEFFFFF {
+				if hy < 0 {
+					return math.Inf(1)
+				}
+				return 0
+			}
+			if ix >= 0x3FF00000 {
+				if hy > 0 {
+					return math.Inf(1)
+				}
+				return 0
+			}
+		}
+		// overflow or underflow if x isn't close to one
+		if ix < 0x3FEFFFFF {
+			if hy < 0 {
+				return s * math.Inf(1)
+			}
+			return s * 0
+		}
+		if ix > 0x3FF00000 {
+			if hy > 0 {
+				return s * math.Inf(1)
+			}
+			return s * 0
+		}
+
+		// |1-x| <= 2^-20, so fdlibmLog(x) is x-x^2/2+x^3/3-x^4/4
+		t := ax - 1
+		w := (t * t) * (0.5 - t*(0.3333333333333333333333-t*0.25))
+		u := ivln2Hi * t
+		v := t*ivln2Lo - w*ivln2
+		t1 = clearLowWord(u + v)
+		t2 = v - (t1 - u)
+	} else {
+		n = 0
+		if ix < 0x00100000 { // subnormal
+			ax *= two53
+			n -= 53
+			ix = highWord(ax)
+		}
+		n += (ix >> 20) - 0x3FF
+		j := ix & 0x000FFFFF
+		// determine the interval
+		ix = j | 0x3FF00000
+		k := 0
+		if j <= 0x3988E { // |x| < sqrt(3/2)
+			k = 0
+		} else if j < 0xBB67A { // |x| < sqrt(3)
+			k = 1
+		} else {
+			k = 0
+			n++
+			ix -= 0x00100000
+		}
+		ax = setHighWord(ax, ix)
+
+		// ss = sHi+sLo = (x-1)/(x+1) or (x-1.5)/(x+1.5)
+		u := ax - bp[k]
+		v := 1 / (ax + bp[k])
+		ss := u * v
+		sHi := clearLowWord(ss)
+		tHi := fromWords(((ix>>1)|0x20000000)+0x00080000+int32(k<<18), 0)
+		tLo := ax - (tHi - bp[k])
+		sLo := v * ((u - sHi*tHi) - sHi*tLo)
+
+		// fdlibmLog(ax)
+		s2 := ss * ss
+		r := s2 * s2 * (L1 + s2*(L2+s2*(L3+s2*(L4+s2*(L5+s2*L6)))))
+		r += sLo * (sHi + ss)
+		s2 = sHi * sHi
+		tHi = clearLowWord(3.0 + s2 + r)
+		tLo = r - ((tHi - 3.0) - s2)
+		u = sHi * tHi
+		v = sLo*tHi + tLo*ss
+		pHi := clearLowWord(u + v)
+		pLo := v - (pHi - u)
+		zHi := cpHi * pHi
+		zLo := cpLo*pHi + pLo*cp + dpLo[k]
+
+		// fdlibmLog2(ax) = (ss+..)*2/(3*log2) = n + dpHi + zHi + zLo
+		t := float64(n)
+		t1 = clearLowWord(((zHi + zLo) + dpHi[k]) + t)
+		t2 = zLo - (((t1 - t) - dpHi[k]) - zHi)
+	}
+
+	// split y into y1+y2 and compute (y1+y2)*(t1+t2)
+	y1 := clearLowWord(y)
+	pLo := (y-y1)*t1 + y*t2
+	pHi := y1 * t1
+	z := pLo + pHi
+	j, i := highWord(z), int32(lowWord(z))
+	if j >= 0x40900000 { // z >= 1024
+		if (j-0x40900000)|i != 0 || pLo+ovt > z-pHi {
+			return s * math.Inf(1)
+		}
+	} else if j&0x7FFFFFFF >= 0x4090CC00 { // z <= -1075
+		if uint32(j)-0xC090CC00|uint32(i) != 0 || pLo <= z-pHi {
+			return s * 0
+		}
+	}
+
+	// 2**(pHi+pLo)
+	i = j & 0x7FFFFFFF
+	k := (i >> 20) - 0x3FF
+	n = 0
+	if i > 0x3FE00000 { // |z| > 0.5, n = [z+0.5]
+		n = j + (0x00100000 >> uint(k+1))
+		k = ((n & 0x7FFFFFFF) >> 20) - 0x3FF
+		t := fromWords(n&^(0x000FFFFF>>uint(k)), 0)
+		n = ((n & 0x000FFFFF) | 0x00100000) >> uint(20-k)
+		if j < 0 {
+			n = -n
+		}
+		pHi -= t
+	}
+	t := clearLowWord(pLo + pHi)
+	u := t * lg2Hi
+	v := (pLo-(t-pHi))*lg2 + t*lg2Lo
+	z = u + v
+	w := v - (z - u)
+	t = z * z
+	t1 = z - t*(P1+t*(P2+t*(P3+t*(P4+t*P5))))
+	// V8 folds w+z*w into the divisor, which fdlibm subtracts from the quotient
+	r := (z * t1) / (t1 - 2 - (w + z*w))
+	z = 1 - (r - z)
+	j = highWord(z) + n<<20
+	if j>>20 <= 0 { // subnormal
+		z = math.Ldexp(z, int(n))
+	} else {
+		z = setHighWord(z, j)
+	}
+	return s * z
+}
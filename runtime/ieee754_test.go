@@ -0,0 +1,127 @@
+package runtime
+
+import (
+	"math"
+	"testing"
+)
+
+// fdlibmTests are the results of V8 for the math functions, as the bits of
+// the float64s so that they are compared to the last bit
+var fdlibmTests = []struct {
+	name string
+	got  float64
+	want uint64
+}{
+	{"sin(1)", fdlibmSin(1), 0x3feaed548f090cee},
+	{"sin(-0.5)", fdlibmSin(-0.5), 0xbfdeaee8744b05f0},
+	{"sin(1e-10)", fdlibmSin(1e-10), 0x3ddb7cdfd9d7bdbb},
+	{"sin(math.Pi)", fdlibmSin(math.Pi), 0x3ca1a62633145c07},
+	{"sin(1e300)", fdlibmSin(1e300), 0xbfea2c16b010e385},
+	{"sin(1e22)", fdlibmSin(1e22), 0xbfeb453ab76bf397},
+	{"sin(1.7976931348623157e308)", fdlibmSin(1.7976931348623157e308), 0x3f7452fc98b34e97},
+	{"sin(6.5e9)", fdlibmSin(6.5e9), 0x3fe25ed8b62b8fc8},
+	{"cos(1)", fdlibmCos(1), 0x3fe14a280fb5068c},
+	{"cos(0.7853981633974483)", fdlibmCos(0.7853981633974483), 0x3fe6a09e667f3bcd},
+	{"cos(1e22)", fdlibmCos(1e22), 0x3fe0be2cef01c8f4},
+	{"cos(1e300)", fdlibmCos(1e300), 0xbfe2699022adc4c1},
+	{"cos(-3.5)", fdlibmCos(-3.5), 0xbfedf77403c11a5f},
+	{"cos(1e-300)", fdlibmCos(1e-300), 0x3ff0000000000000},
+	{"tan(1)", fdlibmTan(1), 0x3ff8eb245cbee3a6},
+	{"tan(1e22)", fdlibmTan(1e22), 0xbffa0f79c1b6b258},
+	{"tan(1e300)", fdlibmTan(1e300), 0x3ff6be411f37ac77},
+	{"tan(1.5707963267948966)", fdlibmTan(1.5707963267948966), 0x434d02967c31cdb5},
+	{"tan(-0.3)", fdlibmTan(-0.3), 0xbfd3cc2a44e29998},
+	{"tan(3.0e-8)", fdlibmTan(3.0e-8), 0x3e601b2b29a4692c},
+	{"asin(0.5)", fdlibmAsin(0.5), 0x3fe0c152382d7366},
+	{"asin(-0.9999)", fdlibmAsin(-0.9999), 0xbff8e80e1a01556a},
+	{"asin(1e-9)", fdlibmAsin(1e-9), 0x3e112e0be826d695},
+	{"asin(1)", fdlibmAsin(1), 0x3ff921fb54442d18},
+	{"acos(0.5)", fdlibmAcos(0.5), 0x3ff0c152382d7366},
+	{"acos(-1)", fdlibmAcos(-1), 0x400921fb54442d18},
+	{"acos(-0.3)", fdlibmAcos(-0.3), 0x3ffe0200bbc96ad8},
+	{"acos(0.9999999)", fdlibmAcos(0.9999999), 0x3f3d4effc851e7f2},
+	{"atan(1)", fdlibmAtan(1), 0x3fe921fb54442d18},
+	{"atan(1e20)", fdlibmAtan(1e20), 0x3ff921fb54442d18},
+	{"atan(-0.4375)", fdlibmAtan(-0.4375), 0xbfda64eec3cc23fd},
+	{"atan(2.4375)", fdlibmAtan(2.4375), 0x3ff2e75728833a54},
+	{"atan(1e-30)", fdlibmAtan(1e-30), 0x39b4484bfeebc2a0},
+	{"exp(1)", fdlibmExp(1), 0x4005bf0a8b145769},
+	{"exp(-1)", fdlibmExp(-1), 0x3fd78b56362cef38},
+	{"exp(0.5)", fdlibmExp(0.5), 0x3ffa61298e1e069c},
+	{"exp(709.7)", fdlibmExp(709.7), 0x7fed75ae7a50ee14},
+	{"exp(-745.1)", fdlibmExp(-745.1), 0x0000000000000001},
+	{"exp(1e-20)", fdlibmExp(1e-20), 0x3ff0000000000000},
+	{"exp(100.5)", fdlibmExp(100.5), 0x48ffcc37a76f9e76},
+	{"log(2)", fdlibmLog(2), 0x3fe62e42fefa39ef},
+	{"log(10)", fdlibmLog(10), 0x40026bb1bbb55516},
+	{"log(0.1)", fdlibmLog(0.1), 0xc0026bb1bbb55515},
+	{"log(1e-310)", fdlibmLog(1e-310), 0xc0864e69394d9508},
+	{"log(1.7976931348623157e308)", fdlibmLog(1.7976931348623157e308), 0x40862e42fefa39ef},
+	{"log(1.0000001)", fdlibmLog(1.0000001), 0x3e7ad7f2847b6492},
+	{"log2(3)", fdlibmLog2(3), 0x3ff95c01a39fbd68},
+	{"log2(1e-300)", fdlibmLog2(1e-300), 0xc08f24a09f1a8b89},
+	{"log2(0.7)", fdlibmLog2(0.7), 0xbfe0776228967d13},
+	{"log2(1024.5)", fdlibmLog2(1024.5), 0x4024005c4f58bde5},
+	{"log10(2)", fdlibmLog10(2), 0x3fd34413509f79ff},
+	{"log10(1e22)", fdlibmLog10(1e22), 0x4036000000000000},
+	{"log10(0.001)", fdlibmLog10(0.001), 0xc008000000000000},
+	{"log10(123456.789)", fdlibmLog10(123456.789), 0x40145db61a282512},
+	{"log10(5e-324)", fdlibmLog10(5e-324), 0xc07434e6420f4374},
+	{"cbrt(2)", fdlibmCbrt(2), 0x3ff428a2f98d728b},
+	{"cbrt(-27.5)", fdlibmCbrt(-27.5), 0xc00825b1b6bac03b},
+	{"cbrt(1e-310)", fdlibmCbrt(1e-310), 0x2a7a9d1b0b5d7427},
+	{"cbrt(1e300)", fdlibmCbrt(1e300), 0x54b249ad2594c37d},
+	{"sinh(0.5)", fdlibmSinh(0.5), 0x3fe0acd00fe63b97},
+	{"sinh(-3)", fdlibmSinh(-3), 0xc0240926e70949ae},
+	{"sinh(30)", fdlibmSinh(30), 0x429370470aec28ed},
+	{"sinh(710)", fdlibmSinh(710), 0x7fe3e21a464507fa},
+	{"cosh(0.5)", fdlibmCosh(0.5), 0x3ff20ac1862ae8d0},
+	{"cosh(-3)", fdlibmCosh(-3), 0x402422a497d6185e},
+	{"cosh(30)", fdlibmCosh(30), 0x429370470aec28ed},
+	{"cosh(710)", fdlibmCosh(710), 0x7fe3e21a464507fa},
+	{"tanh(0.5)", fdlibmTanh(0.5), 0x3fdd9353d7568af3},
+	{"tanh(-3)", fdlibmTanh(-3), 0xbfefd77d111a0b00},
+	{"tanh(1e-9)", fdlibmTanh(1e-9), 0x3e112e0be826d695},
+	{"tanh(22.5)", fdlibmTanh(22.5), 0x3ff0000000000000},
+	{"asinh(0.5)", fdlibmAsinh(0.5), 0x3fdecc2caec5160a},
+	{"asinh(-3)", fdlibmAsinh(-3), 0xbffd185b507edc0e},
+	{"asinh(1e300)", fdlibmAsinh(1e300), 0x40859bbfd8b83e43},
+	{"acosh(1.5)", fdlibmAcosh(1.5), 0x3feecc2caec5160a},
+	{"acosh(3)", fdlibmAcosh(3), 0x3ffc34366179d426},
+	{"acosh(1e300)", fdlibmAcosh(1e300), 0x40859bbfd8b83e43},
+	{"atanh(0.5)", fdlibmAtanh(0.5), 0x3fe193ea7aad030a},
+	{"atanh(-0.99)", fdlibmAtanh(-0.99), 0xc0052c581997cd85},
+	{"atanh(1e-9)", fdlibmAtanh(1e-9), 0x3e112e0be826d695},
+	{"atan2(1, -1)", fdlibmAtan2(1, -1), 0x4002d97c7f3321d2},
+	{"atan2(-0.5, -2)", fdlibmAtan2(-0.5, -2), 0xc0072c43f4b1650a},
+	{"atan2(1e300, 1e-300)", fdlibmAtan2(1e300, 1e-300), 0x3ff921fb54442d18},
+	{"atan2(3, 4)", fdlibmAtan2(3, 4), 0x3fe4978fa3269ee1},
+	{"pow(2, 0.5)", fdlibmPow(2, 0.5), 0x3ff6a09e667f3bcd},
+	{"pow(10, -5)", fdlibmPow(10, -5), 0x3ee4f8b588e368f0},
+	{"pow(1.0000001, 1e9)", fdlibmPow(1.0000001, 1e9), 0x48f349445c228792},
+	{"pow(0.5, 1074.5)", fdlibmPow(0.5, 1074.5), 0x0000000000000001},
+	{"pow(3, 40.2)", fdlibmPow(3, 40.2), 0x43ea45cf9f6af3bb},
+	{"pow(1.1, -700.3)", fdlibmPow(1.1, -700.3), 0x39ea1b028f74fa92},
+	{"sin(-0)", fdlibmSin(math.Copysign(0, -1)), 0x8000000000000000},
+	{"tan(-0)", fdlibmTan(math.Copysign(0, -1)), 0x8000000000000000},
+	{"exp(-Infinity)", fdlibmExp(math.Inf(-1)), 0x0000000000000000},
+	{"log(0)", fdlibmLog(0), 0xfff0000000000000},
+	{"atan2(-0, -1)", fdlibmAtan2(math.Copysign(0, -1), -1), 0xc00921fb54442d18},
+	{"pow(-Infinity, -3)", fdlibmPow(math.Inf(-1), -3), 0x8000000000000000},
+}
+
+func TestFdlibm(t *testing.T) {
+	for _, test := range fdlibmTests {
+		if got := math.Float64bits(test.got); got != test.want {
+			t.Errorf("%s: want=%v (%#016x) got=%v (%#016x)", test.name, math.Float64frombits(test.want), test.want, test.got, got)
+		}
+	}
+}
+
+func TestFdlibmNaN(t *testing.T) {
+	for _, got := range []float64{fdlibmSin(math.Inf(1)), fdlibmLog(-1), fdlibmAcos(1.5), fdlibmPow(-8, 1.0/3), fdlibmPow(1, math.NaN())} {
+		if !math.IsNaN(got) {
+			t.Errorf("want=NaN got=%v", got)
+		}
+	}
+}
@@ -0,0 +1,744 @@
+package runtime
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"unicode/utf16"
+)
+
+// inspector formats values for console.log the way Node.js' util.inspect
+// does with its default options
+type inspector struct {
+	seen           []ObjectValue
+	circular       map[ObjectValue]int
+	indentationLvl int
+}
+
+const (
+	inspectDepth          = 2
+	inspectBreakLength    = 80
+	inspectMaxArrayLength = 100
+	inspectCompact        = 3
+)
+
+var inspectKeyRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z_0-9]*$`)
+
+// Inspect returns a string representation of a value for debugging
+func Inspect(v Object) string {
+	i := &inspector{circular: make(map[ObjectValue]int)}
+	return i.formatValue(v, 0)
+}
+
+func (i *inspector) formatValue(v Object, recurseTimes int) string {
+	o, ok := v.(ObjectValue)
+	if !ok {
+		return formatPrimitive(v)
+	}
+
+	for _, s := range i.seen {
+		if s == o {
+			index, ok := i.circular[o]
+			if !ok {
+				index = len(i.circular) + 1
+				i.circular[o] = index
+			}
+
+			return fmt.Sprintf("[Circular *%d]", index)
+		}
+	}
+
+	return i.formatRaw(o, recurseTimes)
+}
+
+func formatPrimitive(v Object) string {
+	switch v := v.(type) {
+	case nil:
+		return "undefined"
+	case null:
+		return "null"
+	case JSString:
+		return quoteString(string(v))
+	case JSNumber:
+		if v == 0 && math.Signbit(float64(v)) {
+			return "-0"
+		}
+
+		return numberToString(float64(v))
+	case JSBoolean:
+		if v {
+			return "true"
+		}
+
+		return "false"
+	case *Symbol:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func (i *inspector) formatRaw(o ObjectValue, recurseTimes int) string {
+	constructor := constructorName(o)
+	keys := enumerableOwnKeys(o)
+
+	var base string
+	braces := [2]string{"{", "}"}
+	array, isArray := o.(*JSArray)
+	switch v := o.(type) {
+	case *JSArray:
+		keys = nonIndexKeys(keys)
+		braces = [2]string{"[", "]"}
+		if constructor != "Array" {
+			braces[0] = prefix(constructor, "Array", fmt.Sprintf("(%d)", v.Len())) + "["
+		}
+
+		if v.Len() == 0 && len(keys) == 0 {
+			return braces[0] + "]"
+		}
+	case *JSFunction:
+		base = functionBase(v, constructor)
+		if len(keys) == 0 {
+			return base
+		}
+	case *Promise:
+		switch constructor {
+		case "Promise":
+			braces[0] = "Promise {"
+		case "":
+			braces[0] = prefix(constructor, "Promise", "") + "{"
+		default:
+			braces[0] = constructor + " [Promise] {"
+		}
+	case *JSMap:
+		braces[0] = collectionPrefix(o, constructor, "Map", v.size) + "{"
+		if v.size == 0 && len(keys) == 0 {
+			return braces[0] + "}"
+		}
+	case *JSSet:
+		braces[0] = collectionPrefix(o, constructor, "Set", v.size) + "{"
+		if v.size == 0 && len(keys) == 0 {
+			return braces[0] + "}"
+		}
+	case *JSWeakMap:
+		braces[0] = collectionPrefix(o, constructor, "WeakMap", -1) + "{"
+	case *JSWeakSet:
+		braces[0] = collectionPrefix(o, constructor, "WeakSet", -1) + "{"
+	default:
+		if it, ok := o.(*iteratorObject); ok && it.preview != nil {
+			braces[0] = "[" + it.tag + "] {"
+			if _, entries := it.preview(); entries {
+				braces[0] = "[" + strings.TrimSuffix(it.tag, " Iterator") + " Entries] {"
+			}
+			break
+		}
+
+		if o.object().class == "Error" {
+			base = i.formatError(o)
+			if len(keys) == 0 {
+				return base
+			}
+			break
+		}
+
+		if prim := o.object().primitive; prim != nil {
+			if _, ok := prim.(JSString); ok {
+				// the characters are shown by the primitive value
+				keys = nonIndexKeys(keys)
+			}
+			base = fmt.Sprintf("[%s: %s]", o.object().class, formatPrimitive(prim))
+			if len(keys) == 0 {
+				return base
+			}
+		} else if o.object().class == "Arguments" {
+			braces[0] = "[Arguments] {"
+		} else if tag := toStringTag(o); tag != "" && tag != constructor {
+			braces[0] = prefix(constructor, "Object", "") + "[" + tag + "] {"
+		} else if constructor != "Object" {
+			braces[0] = prefix(constructor, "Object", "") + "{"
+		}
+
+		if len(keys) == 0 {
+			return braces[0] + "}"
+		}
+	}
+
+	if recurseTimes > inspectDepth {
+		if constructor == "" {
+			return strings.TrimSuffix(prefix(constructor, "Object", ""), " ")
+		}
+
+		return "[" + constructor + "]"
+	}
+
+	recurseTimes++
+	i.seen = append(i.seen, o)
+
+	var output []string
+	if isArray {
+		output = i.formatArray(array, recurseTimes)
+	}
+	switch v := o.(type) {
+	case *Promise:
+		output = append(output, i.formatPromiseResult(v, recurseTimes))
+	case *JSMap:
+		output = i.formatMap(v, recurseTimes)
+	case *JSSet:
+		output = i.formatSet(v, recurseTimes)
+	case *JSWeakMap, *JSWeakSet:
+		// the entries can't be shown, as they may be collected at any time
+		output = append(output, "<items unknown>")
+	case *iteratorObject:
+		if v.preview != nil {
+			output = i.formatIteratorPreview(v, recurseTimes)
+		}
+	}
+	for _, k := range keys {
+		output = append(output, i.formatProperty(o, recurseTimes, k, false))
+	}
+
+	i.seen = i.seen[:len(i.seen)-1]
+
+	if index, ok := i.circular[o]; ok {
+		reference := fmt.Sprintf("<ref *%d>", index)
+		if base == "" {
+			braces[0] = reference + " " + braces[0]
+		} else {
+			base = reference + " " + base
+		}
+	}
+
+	return i.reduceToSingleString(output, base, braces, array)
+}
+
+// prefix returns the prefix of an object whose constructor isn't the
+// default one, like "Foo " or "[Object: null prototype] "
+func prefix(constructor, fallback, size string) string {
+	if constructor == "" {
+		return fmt.Sprintf("[%s%s: null prototype] ", fallback, size)
+	}
+
+	return fmt.Sprintf("%s%s ", constructor, size)
+}
+
+// collectionPrefix returns the prefix of a Map, a Set or a weak collection,
+// like "Map(2) " or "Foo(2) [Map] "
+// The size is omitted if it's negative.
+func collectionPrefix(o ObjectValue, constructor, fallback string, size int) string {
+	var s string
+	if size >= 0 {
+		s = fmt.Sprintf("(%d)", size)
+	}
+
+	if tag := toStringTag(o); constructor != "" && tag != "" && tag != constructor {
+		return constructor + s + " [" + tag + "] "
+	}
+
+	return prefix(constructor, fallback, s)
+}
+
+// formatArray formats the elements of an array, showing at most 100 of
+// them, with holes combined into a single entry
+func (i *inspector) formatArray(a *JSArray, recurseTimes int) []string {
+	var output []string
+	n := a.Len()
+	index := int64(0)
+	for index < n && len(output) < inspectMaxArrayLength {
+		if a.getOwnProperty(indexKey(index)) != nil {
+			output = append(output, i.formatProperty(a, recurseTimes, indexKey(index), true))
+			index++
+			continue
+		}
+
+		// skip to the next element
+		next := n
+		for _, k := range a.ownKeys() {
+			if j, ok := arrayIndex(k); ok && int64(j) > index {
+				next = int64(j)
+				break
+			}
+		}
+
+		output = append(output, emptyItems(next-index))
+		index = next
+	}
+
+	return appendRemaining(output, n-index)
+}
+
+func emptyItems(n int64) string {
+	return fmt.Sprintf("<%d empty item%s>", n, plural(n))
+}
+
+func plural(n int64) string {
+	if n > 1 {
+		return "s"
+	}
+
+	return ""
+}
+
+// nonIndexKeys removes the array indices from a list of keys
+func nonIndexKeys(keys []PropertyKey) []PropertyKey {
+	var result []PropertyKey
+	for _, k := range keys {
+		if _, ok := arrayIndex(k); !ok {
+			result = append(result, k)
+		}
+	}
+
+	return result
+}
+
+// formatProperty formats a property as key: value, or just the value for an
+// array element
+func (i *inspector) formatProperty(o ObjectValue, recurseTimes int, key PropertyKey, element bool) string {
+	var str string
+	p := o.getOwnProperty(key)
+	switch {
+	case p == nil:
+		str = "undefined"
+	case !p.accessor:
+		i.indentationLvl += 2
+		str = i.formatValue(p.value, recurseTimes)
+		i.indentationLvl -= 2
+	case p.getter != nil && p.setter != nil:
+		str = "[Getter/Setter]"
+	case p.getter != nil:
+		str = "[Getter]"
+	case p.setter != nil:
+		str = "[Setter]"
+	default:
+		str = "undefined"
+	}
+
+	if element {
+		return str
+	}
+
+	var name string
+	if s, ok := key.(JSString); ok && inspectKeyRegexp.MatchString(string(s)) {
+		name = string(s)
+	} else if ok {
+		name = quoteString(string(s))
+	} else {
+		name = fmt.Sprintf("[%s]", formatPrimitive(key))
+	}
+
+	return name + ": " + str
+}
+
+func (i *inspector) reduceToSingleString(output []string, base string, braces [2]string, array *JSArray) string {
+	entries := len(output)
+	if array != nil && entries > 6 {
+		output = i.groupArrayElements(output, array)
+	}
+
+	// With the default depth, entries always fit the compact mode, so they
+	// are combined on a single line if the line is short enough
+	if entries == len(output) {
+		start := len(output) + i.indentationLvl + stringLength(braces[0]) + stringLength(base) + 10
+		if isBelowBreakLength(output, start, base) {
+			joined := strings.Join(output, ", ")
+			if !strings.Contains(joined, "\n") {
+				if base != "" {
+					base += " "
+				}
+
+				return base + braces[0] + " " + joined + " " + braces[1]
+			}
+		}
+	}
+
+	indentation := "\n" + strings.Repeat(" ", i.indentationLvl)
+	if base != "" {
+		base += " "
+	}
+
+	return base + braces[0] + indentation + "  " + strings.Join(output, ","+indentation+"  ") + indentation + braces[1]
+}
+
+// groupArrayElements arranges the entries of long arrays with short entries
+// in columns
+func (i *inspector) groupArrayElements(output []string, array *JSArray) []string {
+	totalLength := 0
+	maxLength := 0
+	outputLength := len(output)
+	if inspectMaxArrayLength < len(output) {
+		// the "... more items" entry isn't grouped
+		outputLength--
+	}
+
+	const separatorSpace = 2
+	dataLen := make([]int, outputLength)
+	for j := 0; j < outputLength; j++ {
+		l := stringLength(output[j])
+		dataLen[j] = l
+		totalLength += l + separatorSpace
+		if maxLength < l {
+			maxLength = l
+		}
+	}
+
+	actualMax := maxLength + separatorSpace
+	if actualMax*3+i.indentationLvl >= inspectBreakLength ||
+		(float64(totalLength)/float64(actualMax) <= 5 && maxLength > 6) {
+		return output
+	}
+
+	const approxCharHeights = 2.5
+	averageBias := math.Sqrt(float64(actualMax) - float64(totalLength)/float64(len(output)))
+	biasedMax := math.Max(float64(actualMax)-3-averageBias, 1)
+	columns := int(math.Min(math.Min(
+		jsRound(math.Sqrt(approxCharHeights*biasedMax*float64(outputLength))/biasedMax),
+		math.Floor(float64(inspectBreakLength-i.indentationLvl)/float64(actualMax))),
+		math.Min(inspectCompact*4, 15)))
+	if columns <= 1 {
+		return output
+	}
+
+	var maxLineLength []int
+	for c := 0; c < columns; c++ {
+		lineLength := 0
+		for j := c; j < len(output); j += columns {
+			if j < outputLength && dataLen[j] > lineLength {
+				lineLength = dataLen[j]
+			}
+		}
+		maxLineLength = append(maxLineLength, lineLength+separatorSpace)
+	}
+
+	// numbers are aligned to the right, other values to the left
+	padStart := true
+	for j := 0; j < len(output); j++ {
+		if _, ok := getIndex(array, int64(j)).(JSNumber); !ok {
+			padStart = false
+			break
+		}
+	}
+
+	var grouped []string
+	for j := 0; j < outputLength; j += columns {
+		max := j + columns
+		if max > outputLength {
+			max = outputLength
+		}
+
+		var line bytes.Buffer
+		k := j
+		for ; k < max-1; k++ {
+			line.WriteString(pad(output[k]+", ", maxLineLength[k-j], padStart))
+		}
+		if padStart {
+			line.WriteString(pad(output[k], maxLineLength[k-j]-separatorSpace, true))
+		} else {
+			line.WriteString(output[k])
+		}
+		grouped = append(grouped, line.String())
+	}
+
+	if inspectMaxArrayLength < len(output) {
+		grouped = append(grouped, output[outputLength])
+	}
+
+	return grouped
+}
+
+// pad pads a string with spaces to a width
+func pad(s string, width int, start bool) string {
+	n := width - stringLength(s)
+	if n <= 0 {
+		return s
+	}
+
+	if start {
+		return strings.Repeat(" ", n) + s
+	}
+
+	return s + strings.Repeat(" ", n)
+}
+
+// jsRound rounds like Math.round, with halves rounded up
+func jsRound(f float64) float64 {
+	return math.Floor(f + 0.5)
+}
+
+func isBelowBreakLength(output []string, start int, base string) bool {
+	totalLength := len(output) + start
+	if totalLength+len(output) > inspectBreakLength {
+		return false
+	}
+
+	for _, s := range output {
+		totalLength += stringLength(s)
+		if totalLength > inspectBreakLength {
+			return false
+		}
+	}
+
+	return base == "" || !strings.Contains(base, "\n")
+}
+
+// formatError formats an error with its stack, which is indented like the
+// error
+func (i *inspector) formatError(o ObjectValue) string {
+	stack, ok := getProperty(o, JSString("stack"), o).(JSString)
+	if !ok || stack == "" {
+		stack = ToString(errorPrototypeToString(o, nil))
+	}
+
+	s := string(stack)
+	if !strings.Contains(s, "\n    at") {
+		s = "[" + s + "]"
+	}
+	if i.indentationLvl != 0 {
+		s = strings.Replace(s, "\n", "\n"+strings.Repeat(" ", i.indentationLvl), -1)
+	}
+
+	return s
+}
+
+// formatMap formats the entries of a Map as key => value, showing at most
+// 100 of them
+func (i *inspector) formatMap(m *JSMap, recurseTimes int) []string {
+	var output []string
+	i.indentationLvl += 2
+	m.forEach(func(e *collectionEntry) {
+		if len(output) < inspectMaxArrayLength {
+			output = append(output, i.formatValue(e.key, recurseTimes)+" => "+i.formatValue(e.value, recurseTimes))
+		}
+	})
+	i.indentationLvl -= 2
+
+	return appendRemaining(output, int64(m.size-len(output)))
+}
+
+// formatSet formats the values of a Set, showing at most 100 of them
+func (i *inspector) formatSet(s *JSSet, recurseTimes int) []string {
+	var output []string
+	i.indentationLvl += 2
+	s.forEach(func(e *collectionEntry) {
+		if len(output) < inspectMaxArrayLength {
+			output = append(output, i.formatValue(e.key, recurseTimes))
+		}
+	})
+	i.indentationLvl -= 2
+
+	return appendRemaining(output, int64(s.size-len(output)))
+}
+
+// formatIteratorPreview formats the values that an iterator hasn't visited
+// yet, with the entries as [ key, value ] pairs
+func (i *inspector) formatIteratorPreview(o *iteratorObject, recurseTimes int) []string {
+	values, entries := o.preview()
+	n := len(values)
+	if entries {
+		n /= 2
+	}
+
+	var output []string
+	i.indentationLvl += 2
+	for j := 0; j < n && j < inspectMaxArrayLength; j++ {
+		if !entries {
+			output = append(output, i.formatValue(values[j], recurseTimes))
+			continue
+		}
+
+		pair := []string{i.formatValue(values[2*j], recurseTimes), i.formatValue(values[2*j+1], recurseTimes)}
+		output = append(output, i.reduceToSingleString(pair, "", [2]string{"[", "]"}, nil))
+	}
+	i.indentationLvl -= 2
+
+	return appendRemaining(output, int64(n-len(output)))
+}
+
+// appendRemaining adds the number of the entries that aren't shown
+func appendRemaining(output []string, remaining int64) []string {
+	if remaining > 0 {
+		output = append(output, fmt.Sprintf("... %d more item%s", remaining, plural(remaining)))
+	}
+
+	return output
+}
+
+// formatPromiseResult formats the state of a promise, and its result once
+// it's settled
+func (i *inspector) formatPromiseResult(p *Promise, recurseTimes int) string {
+	switch p.state {
+	case promisePending:
+		return "<pending>"
+	case promiseRejected:
+		return "<rejected> " + i.formatValue(p.result, recurseTimes)
+	default:
+		return i.formatValue(p.result, recurseTimes)
+	}
+}
+
+func functionBase(f *JSFunction, constructor string) string {
+	if f.isClass {
+		return classBase(f, constructor)
+	}
+
+	kind := "Function"
+	if f.generator {
+		kind = "GeneratorFunction"
+	} else if f.async {
+		kind = "AsyncFunction"
+	}
+
+	base := "[" + kind
+	if constructor == "" {
+		base += " (null prototype)"
+	}
+
+	name, _ := f.GetProperty("name")
+	if s, ok := name.(JSString); ok && s != "" {
+		base += ": " + string(s)
+	} else {
+		base += " (anonymous)"
+	}
+	base += "]"
+
+	if constructor != kind && constructor != "" {
+		base += " " + constructor
+	}
+
+	return base
+}
+
+func classBase(f *JSFunction, constructor string) string {
+	base := "[class "
+	if p := f.getOwnProperty(JSString("name")); p != nil && !p.accessor && p.value != JSString("") {
+		base += string(ToString(p.value))
+	} else {
+		base += "(anonymous)"
+	}
+
+	if constructor != "Function" && constructor != "" {
+		base += " [" + constructor + "]"
+	}
+
+	if constructor == "" {
+		base += " extends [null prototype]"
+	} else if parent, ok := f.proto.(*JSFunction); ok {
+		if name, ok := parent.GetProperty("name"); ok && name != JSString("") {
+			base += " extends " + string(ToString(name))
+		}
+	}
+
+	return base + "]"
+}
+
+// constructorName returns the name of the constructor of the first object in
+// the prototype chain that has an own constructor property, which the object
+// is an instance of
+func constructorName(o ObjectValue) string {
+	for obj := o; obj != nil; obj = obj.object().proto {
+		p := obj.getOwnProperty(JSString("constructor"))
+		if p == nil || p.accessor {
+			continue
+		}
+
+		if f, ok := p.value.(*JSFunction); ok && isInstance(o, f) {
+			if name, ok := f.GetProperty("name"); ok {
+				if s, ok := name.(JSString); ok && s != "" {
+					return string(s)
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+// isInstance tells whether the prototype property of a constructor is in the
+// prototype chain of an object, without calling getters
+func isInstance(o ObjectValue, f *JSFunction) bool {
+	p := f.getOwnProperty(JSString("prototype"))
+	if p == nil || p.accessor {
+		return false
+	}
+
+	for proto := o.object().proto; proto != nil; proto = proto.object().proto {
+		if proto == p.value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// toStringTag returns the Symbol.toStringTag of an object that doesn't show
+// it as an own enumerable property
+func toStringTag(o ObjectValue) string {
+	if p := o.getOwnProperty(SymbolToStringTag); p != nil && p.enumerable {
+		return ""
+	}
+
+	tag, _ := getProperty(o, SymbolToStringTag, o).(JSString)
+	return string(tag)
+}
+
+// enumerableOwnKeys returns the enumerable own property keys of an object
+func enumerableOwnKeys(o ObjectValue) []PropertyKey {
+	var keys []PropertyKey
+	for _, k := range o.ownKeys() {
+		if p := o.getOwnProperty(k); p != nil && p.enumerable {
+			keys = append(keys, k)
+		}
+	}
+
+	return keys
+}
+
+// quoteString quotes a string with single quotes, or with double quotes or
+// backticks if the string contains single quotes
+func quoteString(s string) string {
+	quote := byte('\'')
+	if strings.Contains(s, "'") {
+		if !strings.Contains(s, `"`) {
+			quote = '"'
+		} else if !strings.Contains(s, "`") && !strings.Contains(s, "${") {
+			quote = '`'
+		}
+	}
+
+	var out bytes.Buffer
+	out.WriteByte(quote)
+	for _, r := range s {
+		switch {
+		case r == rune(quote) || r == '\\':
+			out.WriteByte('\\')
+			out.WriteRune(r)
+		case r == '\b':
+			out.WriteString(`\b`)
+		case r == '\t':
+			out.WriteString(`\t`)
+		case r == '\n':
+			out.WriteString(`\n`)
+		case r == '\f':
+			out.WriteString(`\f`)
+		case r == '\r':
+			out.WriteString(`\r`)
+		case r < 32 || (r > 126 && r < 160):
+			fmt.Fprintf(&out, `\x%02X`, r)
+		default:
+			out.WriteRune(r)
+		}
+	}
+	out.WriteByte(quote)
+
+	return out.String()
+}
+
+// stringLength returns the length of a string in UTF-16 code units
+func stringLength(s string) int {
+	n := 0
+	for _, r := range s {
+		n += len(utf16.Encode([]rune{r}))
+	}
+
+	return n
+}
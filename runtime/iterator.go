@@ -0,0 +1,276 @@
+package runtime
+
+// Iterator is an iterator record of the iterator protocol, which steps
+// through the values of an iterable
+type Iterator struct {
+	next func() (Object, bool)
+	// close is called when the iteration stops before the iterator is done
+	close func()
+	done  bool
+}
+
+// GetIterator returns an iterator over the values of an iterable, which is
+// created by its Symbol.iterator method
+// The builtin iterators of arrays, array-like objects, strings, maps and sets
+// are stepped through directly: arrays by index up to their current length,
+// strings by code point, and maps and sets in insertion order.
+func GetIterator(v Object) *Iterator {
+	var method Object
+	if !isNullish(v) {
+		method = Get(v, SymbolIterator)
+	}
+
+	switch method {
+	case arrayPrototypeValues:
+		return newArrayIterator(ToObject(v), "values")
+	case stringPrototypeIterator:
+		if s, ok := v.(JSString); ok {
+			return newStringIterator(s)
+		}
+	case mapPrototypeEntries:
+		if m, ok := v.(*JSMap); ok {
+			it, _ := m.iterator("entries")
+			return it
+		}
+	case setPrototypeValues:
+		if s, ok := v.(*JSSet); ok {
+			it, _ := s.iterator("values")
+			return it
+		}
+	}
+
+	iter, next := openIterator(v, method)
+	return &Iterator{
+		next: func() (Object, bool) {
+			result := Call(next, iter, nil)
+			if !isObject(result) {
+				panic(newTypeError("Iterator result " + toDisplayString(result) + " is not an object"))
+			}
+
+			if ToBoolean(Get(result, JSString("done"))) {
+				return Undefined, false
+			}
+
+			return Get(result, JSString("value")), true
+		},
+		close: func() { closeIterator(iter) },
+	}
+}
+
+// openIterator calls the Symbol.iterator method of an iterable, and returns
+// the iterator object with its next method
+func openIterator(v Object, method Object) (iter Object, next Object) {
+	if _, ok := method.(*JSFunction); !ok {
+		panic(newTypeError(toDisplayString(v) + " is not iterable"))
+	}
+
+	iter = Call(method, v, nil)
+	if !isObject(iter) {
+		panic(newTypeError("Result of the Symbol.iterator method is not an object"))
+	}
+
+	return iter, Get(iter, JSString("next"))
+}
+
+// closeIterator calls the return method of an iterator object
+func closeIterator(iter Object) {
+	ret := Get(iter, JSString("return"))
+	if isNullish(ret) {
+		return
+	}
+
+	if result := Call(ret, iter, nil); !isObject(result) {
+		panic(newTypeError("Iterator result " + toDisplayString(result) + " is not an object"))
+	}
+}
+
+// newArrayIterator steps through the keys, the values or the entries of an
+// array-like object up to its current length
+func newArrayIterator(o ObjectValue, kind string) *Iterator {
+	i := int64(0)
+	return &Iterator{next: func() (Object, bool) {
+		if i >= lengthOfArrayLike(o) {
+			return Undefined, false
+		}
+		i++
+
+		switch kind {
+		case "keys":
+			return JSNumber(i - 1), true
+		case "values":
+			return getIndex(o, i-1), true
+		default:
+			return NewArray([]Object{JSNumber(i - 1), getIndex(o, i-1)}), true
+		}
+	}}
+}
+
+// newStringIterator steps through the code points of a string
+func newStringIterator(s JSString) *Iterator {
+	runes := []rune(string(s))
+	i := 0
+	return &Iterator{next: func() (Object, bool) {
+		if i >= len(runes) {
+			return Undefined, false
+		}
+		i++
+
+		return JSString(string(runes[i-1])), true
+	}}
+}
+
+// Step returns the next value, or false when the iterator is done
+// An iterator that throws is done.
+func (it *Iterator) Step() (Object, bool) {
+	if it.done {
+		return Undefined, false
+	}
+
+	it.done = true
+	v, ok := it.next()
+	it.done = !ok
+
+	return v, ok
+}
+
+// Next returns the next value, or undefined when the iterator is done
+func (it *Iterator) Next() Object {
+	v, _ := it.Step()
+	return v
+}
+
+// Rest collects the remaining values in an array
+func (it *Iterator) Rest() *JSArray {
+	var values []Object
+	for {
+		v, ok := it.Step()
+		if !ok {
+			return NewArray(values)
+		}
+		values = append(values, v)
+	}
+}
+
+// Close stops an iterator that isn't done
+func (it *Iterator) Close() {
+	if it.done {
+		return
+	}
+
+	it.done = true
+	if it.close != nil {
+		it.close()
+	}
+}
+
+// CloseOnThrow closes an iterator that isn't done when an exception is
+// thrown, which is rethrown over any exception thrown by closing
+// It must be called by defer.
+func (it *Iterator) CloseOnThrow() {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	func() {
+		defer func() { recover() }()
+		it.Close()
+	}()
+	panic(r)
+}
+
+// ForOf runs the body of a for-of loop for the values of an iterator
+// The iterator is closed if the body completes with a break, a return or a
+// jump out of the loop, or if it throws an exception.
+func ForOf(it *Iterator, body func(Object) (Completion, Object)) (Completion, Object) {
+	defer it.CloseOnThrow()
+	for {
+		v, ok := it.Step()
+		if !ok {
+			return NormalCompletion, Undefined
+		}
+
+		if c, result := body(v); c != NormalCompletion {
+			it.Close()
+			if c == BreakCompletion {
+				return NormalCompletion, Undefined
+			}
+
+			return c, result
+		}
+	}
+}
+
+// ForIn returns an iterator over the keys of a for-in loop, which are the
+// enumerable string keys of an object and of its prototypes
+// The keys of each object are listed when the loop reaches the object. A key
+// that is deleted before it's reached is skipped, like a key of a prototype
+// that is shadowed by a key that was already reached.
+func ForIn(v Object) *Iterator {
+	if isNullish(v) {
+		return &Iterator{next: func() (Object, bool) { return Undefined, false }}
+	}
+
+	o := ToObject(v)
+	visited := make(map[PropertyKey]bool)
+	var keys []PropertyKey
+	listed := false
+	return &Iterator{next: func() (Object, bool) {
+		for o != nil {
+			if !listed {
+				keys, listed = o.ownKeys(), true
+			}
+
+			for len(keys) > 0 {
+				k := keys[0]
+				keys = keys[1:]
+				if _, ok := k.(JSString); !ok || visited[k] {
+					continue
+				}
+
+				p := o.getOwnProperty(k)
+				if p == nil {
+					continue
+				}
+
+				visited[k] = true
+				if p.enumerable {
+					return k, true
+				}
+			}
+
+			o, listed = o.object().proto, false
+		}
+
+		return Undefined, false
+	}}
+}
+
+// spread holds the values of a spread element until the list it's spread in
+// is expanded
+type spread struct {
+	values []Object
+}
+
+func (self *spread) Type() JSObjectType { return "" }
+
+// Spread collects the values of an iterable for a spread element like
+// f(...v) or [...v]
+func Spread(v Object) Object {
+	return &spread{GetIterator(v).Rest().elements}
+}
+
+// ExpandSpread replaces the spread elements of an argument list or an array
+// literal with their values
+func ExpandSpread(items []Object) []Object {
+	var expanded []Object
+	for _, item := range items {
+		if s, ok := item.(*spread); ok {
+			expanded = append(expanded, s.values...)
+		} else {
+			expanded = append(expanded, item)
+		}
+	}
+
+	return expanded
+}
@@ -0,0 +1,90 @@
+package runtime
+
+import "testing"
+
+func TestIteratorCloseOnThrow(t *testing.T) {
+	closed := false
+	it := &Iterator{
+		next:  func() (Object, bool) { return JSNumber(1), true },
+		close: func() { closed = true },
+	}
+
+	func() {
+		defer func() { recover() }()
+		defer it.CloseOnThrow()
+
+		it.Next()
+		panic(newTypeError("boom"))
+	}()
+
+	if !closed {
+		t.Fatal("an exception should close an iterator that isn't done")
+	}
+}
+
+func TestIteratorThrowingNext(t *testing.T) {
+	closed := false
+	it := &Iterator{
+		next:  func() (Object, bool) { panic(newTypeError("boom")) },
+		close: func() { closed = true },
+	}
+
+	func() {
+		defer func() { recover() }()
+		defer it.CloseOnThrow()
+
+		it.Next()
+	}()
+
+	if closed {
+		t.Fatal("an iterator whose next method throws is done and shouldn't be closed")
+	}
+}
+
+func TestArrayIteratorRest(t *testing.T) {
+	it := GetIterator(NewArray([]Object{JSNumber(1), JSNumber(2), JSNumber(3)}))
+	if v := it.Next(); v != JSNumber(1) {
+		t.Fatalf("unexpected first value: v=%v", v)
+	}
+
+	rest := it.Rest()
+	if rest.Len() != 2 || rest.elements[0] != JSNumber(2) || rest.elements[1] != JSNumber(3) {
+		t.Fatalf("unexpected rest: %v", rest.elements)
+	}
+
+	if v, ok := it.Step(); ok || v != Undefined {
+		t.Fatalf("iterator should be done: v=%v", v)
+	}
+}
+
+func TestForOfBreak(t *testing.T) {
+	closed := false
+	it := &Iterator{
+		next:  func() (Object, bool) { return JSNumber(1), true },
+		close: func() { closed = true },
+	}
+
+	c, _ := ForOf(it, func(v Object) (Completion, Object) { return BreakCompletion, Undefined })
+	if c != NormalCompletion {
+		t.Fatalf("a break out of the loop should complete normally: c=%v", c)
+	}
+	if !closed {
+		t.Fatal("a break out of the loop should close the iterator")
+	}
+}
+
+func TestForInDeletedKey(t *testing.T) {
+	o := NewObject()
+	o.DefineProperty("a", JSNumber(1))
+	o.DefineProperty("b", JSNumber(2))
+
+	it := ForIn(o)
+	if k := it.Next(); k != JSString("a") {
+		t.Fatalf("unexpected first key: k=%v", k)
+	}
+
+	o.deleteProperty(JSString("b"))
+	if k, ok := it.Step(); ok {
+		t.Fatalf("a deleted key shouldn't be visited: k=%v", k)
+	}
+}
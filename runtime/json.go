@@ -0,0 +1,613 @@
+package runtime
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// jsonParser parses JSON text, which it reads as UTF-16 code units so that
+// the positions in its error messages are the indices of the string
+// The error messages are the ones of V8.
+type jsonParser struct {
+	source JSString
+	text   []uint16
+	pos    int
+}
+
+func newJSONParser(source JSString) *jsonParser {
+	return &jsonParser{source: source, text: utf16.Encode([]rune(string(source)))}
+}
+
+// parse parses the text as a single JSON value
+func (p *jsonParser) parse() Object {
+	v := p.parseValue()
+	p.skipWhitespace()
+	if p.pos < len(p.text) {
+		panic(newSyntaxError(fmt.Sprintf("Unexpected non-whitespace character after JSON at position %d", p.pos)))
+	}
+
+	return v
+}
+
+func (p *jsonParser) parseValue() Object {
+	p.skipWhitespace()
+	switch c := p.peek(); {
+	case c == '"':
+		return p.parseString()
+	case c == '-' || isDecimalDigit(c):
+		return p.parseNumber()
+	case c == '{':
+		return p.parseObject()
+	case c == '[':
+		return p.parseArray()
+	case c == 't':
+		return p.parseLiteral("true", JSBoolean(true))
+	case c == 'f':
+		return p.parseLiteral("false", JSBoolean(false))
+	case c == 'n':
+		return p.parseLiteral("null", Null)
+	}
+
+	p.unexpected()
+	return nil
+}
+
+func (p *jsonParser) parseObject() Object {
+	o := NewObject()
+	p.pos++
+	p.skipWhitespace()
+	if p.peek() == '}' {
+		p.pos++
+		return o
+	}
+
+	if p.peek() != '"' {
+		p.fail("Expected property name or '}'")
+	}
+
+	for {
+		key := p.parseString()
+		p.skipWhitespace()
+		if p.peek() != ':' {
+			p.fail("Expected ':' after property name")
+		}
+		p.pos++
+
+		createDataProperty(o, key, p.parseValue())
+
+		p.skipWhitespace()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			return o
+		default:
+			p.fail("Expected ',' or '}' after property value")
+		}
+
+		p.skipWhitespace()
+		if p.peek() != '"' {
+			p.fail("Expected double-quoted property name")
+		}
+	}
+}
+
+func (p *jsonParser) parseArray() Object {
+	var elements []Object
+	p.pos++
+	p.skipWhitespace()
+	if p.peek() == ']' {
+		p.pos++
+		return NewArray(elements)
+	}
+
+	for {
+		elements = append(elements, p.parseValue())
+
+		p.skipWhitespace()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+			p.pos++
+			return NewArray(elements)
+		default:
+			p.fail("Expected ',' or ']' after array element")
+		}
+	}
+}
+
+func (p *jsonParser) parseString() JSString {
+	var units []uint16
+	p.pos++
+	for {
+		if p.pos >= len(p.text) {
+			p.fail("Unterminated string")
+		}
+
+		c := p.text[p.pos]
+		switch {
+		case c == '"':
+			p.pos++
+			return JSString(string(utf16.Decode(units)))
+		case c < 0x20:
+			p.fail("Bad control character in string literal")
+		case c != '\\':
+			units = append(units, c)
+			p.pos++
+			continue
+		}
+
+		p.pos++
+		switch p.peek() {
+		case '"', '\\', '/':
+			units = append(units, p.text[p.pos])
+		case 'b':
+			units = append(units, '\b')
+		case 'f':
+			units = append(units, '\f')
+		case 'n':
+			units = append(units, '\n')
+		case 'r':
+			units = append(units, '\r')
+		case 't':
+			units = append(units, '\t')
+		case 'u':
+			units = append(units, p.parseUnicodeEscape())
+			continue
+		case -1:
+			p.unexpected()
+		default:
+			p.fail("Bad escaped character")
+		}
+		p.pos++
+	}
+}
+
+// parseUnicodeEscape parses the four hexadecimal digits of a \u escape
+func (p *jsonParser) parseUnicodeEscape() uint16 {
+	var unit uint16
+	p.pos++
+	for i := 0; i < 4; i++ {
+		d, ok := hexDigit(p.peek())
+		if !ok {
+			p.fail("Bad Unicode escape")
+		}
+		unit = unit<<4 | d
+		p.pos++
+	}
+
+	return unit
+}
+
+func (p *jsonParser) parseNumber() Object {
+	start := p.pos
+	if p.peek() == '-' {
+		p.pos++
+		if !isDecimalDigit(p.peek()) {
+			p.fail("No number after minus sign")
+		}
+	}
+
+	if p.peek() == '0' {
+		p.pos++
+		if isDecimalDigit(p.peek()) {
+			p.unexpected()
+		}
+	} else {
+		p.skipDigits()
+	}
+
+	if p.peek() == '.' {
+		p.pos++
+		if !isDecimalDigit(p.peek()) {
+			p.fail("Unterminated fractional number")
+		}
+		p.skipDigits()
+	}
+
+	if c := p.peek(); c == 'e' || c == 'E' {
+		p.pos++
+		if c := p.peek(); c == '+' || c == '-' {
+			p.pos++
+		}
+		if !isDecimalDigit(p.peek()) {
+			p.fail("Exponent part is missing a number")
+		}
+		p.skipDigits()
+	}
+
+	// the number is ASCII, so the code units are its bytes
+	digits := make([]byte, p.pos-start)
+	for i := range digits {
+		digits[i] = byte(p.text[start+i])
+	}
+	f, _ := strconv.ParseFloat(string(digits), 64)
+
+	return JSNumber(f)
+}
+
+// parseLiteral parses true, false or null
+func (p *jsonParser) parseLiteral(literal string, v Object) Object {
+	for i := 0; i < len(literal); i++ {
+		if p.peek() != rune(literal[i]) {
+			p.unexpected()
+		}
+		p.pos++
+	}
+
+	return v
+}
+
+// peek returns the code unit at the position, or -1 at the end of the text
+func (p *jsonParser) peek() rune {
+	if p.pos >= len(p.text) {
+		return -1
+	}
+
+	return rune(p.text[p.pos])
+}
+
+func (p *jsonParser) skipWhitespace() {
+	for {
+		switch p.peek() {
+		case ' ', '\t', '\n', '\r':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (p *jsonParser) skipDigits() {
+	for isDecimalDigit(p.peek()) {
+		p.pos++
+	}
+}
+
+// fail throws a SyntaxError about the code unit at the position
+func (p *jsonParser) fail(msg string) {
+	panic(newSyntaxError(fmt.Sprintf("%s in JSON at position %d", msg, p.pos)))
+}
+
+// unexpected throws a SyntaxError about an unexpected token, which is
+// quoted with up to 10 code units around it if the text is long
+func (p *jsonParser) unexpected() {
+	const context = 10
+
+	c := p.peek()
+	switch {
+	case c == -1:
+		panic(newSyntaxError("Unexpected end of JSON input"))
+	case c == '-' || isDecimalDigit(c):
+		p.fail("Unexpected number")
+	case c == '"':
+		p.fail("Unexpected string")
+	}
+
+	switch p.source {
+	case "undefined", "NaN", "Infinity", "[object Object]":
+		panic(newSyntaxError(fmt.Sprintf("\"%s\" is not valid JSON", p.source)))
+	}
+
+	token := string(utf16.Decode(p.text[p.pos : p.pos+1]))
+	n := len(p.text)
+	var excerpt string
+	switch {
+	case n <= 2*context:
+		excerpt = "\"" + string(p.source) + "\""
+	case p.pos < context:
+		excerpt = "\"" + string(utf16.Decode(p.text[:p.pos+context])) + "\"..."
+	case p.pos < n-context:
+		excerpt = "...\"" + string(utf16.Decode(p.text[p.pos-context:p.pos+context])) + "\"..."
+	default:
+		excerpt = "...\"" + string(utf16.Decode(p.text[p.pos-context:])) + "\""
+	}
+
+	panic(newSyntaxError(fmt.Sprintf("Unexpected token '%s', %s is not valid JSON", token, excerpt)))
+}
+
+func isDecimalDigit(c rune) bool {
+	return c >= '0' && c <= '9'
+}
+
+func hexDigit(c rune) (uint16, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return uint16(c - '0'), true
+	case c >= 'a' && c <= 'f':
+		return uint16(c - 'a' + 10), true
+	case c >= 'A' && c <= 'F':
+		return uint16(c - 'A' + 10), true
+	}
+
+	return 0, false
+}
+
+// internalizeJSONProperty calls the reviver of JSON.parse with a property
+// of a parsed value, after its own properties
+// A property is deleted if the reviver returns undefined.
+func internalizeJSONProperty(holder ObjectValue, key PropertyKey, reviver Object) Object {
+	v := Get(holder, key)
+	if o, ok := v.(ObjectValue); ok {
+		var keys []PropertyKey
+		if isArray(o) {
+			for i := int64(0); i < lengthOfArrayLike(o); i++ {
+				keys = append(keys, indexKey(i))
+			}
+		} else {
+			keys = enumerableOwnStringKeys(o)
+		}
+
+		for _, k := range keys {
+			if revived := internalizeJSONProperty(o, k, reviver); revived == Undefined {
+				o.deleteProperty(k)
+			} else {
+				createDataProperty(o, k, revived)
+			}
+		}
+	}
+
+	return Call(reviver, holder, []Object{key, v})
+}
+
+// enumerableOwnStringKeys returns the keys of the enumerable own properties
+// of an object that aren't symbols
+func enumerableOwnStringKeys(o ObjectValue) []PropertyKey {
+	var keys []PropertyKey
+	for _, k := range o.ownKeys() {
+		if _, ok := k.(JSString); !ok {
+			continue
+		}
+
+		if p := o.getOwnProperty(k); p != nil && p.enumerable {
+			keys = append(keys, k)
+		}
+	}
+
+	return keys
+}
+
+// jsonSerializer serializes values as JSON text for JSON.stringify
+type jsonSerializer struct {
+	// replacer is the replacer function, or nil
+	replacer Object
+	// keys are the keys of the properties that are serialized, or nil to
+	// serialize all the enumerable ones
+	keys []PropertyKey
+	// gap is the indentation of each level, or empty for a single line
+	gap    string
+	indent string
+	// stack holds the objects being serialized, with the keys they were
+	// reached by, to detect cycles
+	stack []jsonStackEntry
+}
+
+type jsonStackEntry struct {
+	key    string
+	object ObjectValue
+}
+
+// serializeProperty serializes a property of a holder, or returns false if
+// the value can't be serialized, like undefined or a function
+func (s *jsonSerializer) serializeProperty(holder ObjectValue, key PropertyKey) (string, bool) {
+	v := Get(holder, key)
+	if isObject(v) {
+		if toJSON := Get(v, JSString("toJSON")); isCallable(toJSON) {
+			v = Call(toJSON, v, []Object{key})
+		}
+	}
+
+	if s.replacer != nil {
+		v = Call(s.replacer, holder, []Object{key, v})
+	}
+
+	if o, ok := v.(ObjectValue); ok {
+		switch o.object().class {
+		case "Number", "String", "Boolean":
+			if prim := o.object().primitive; prim != nil {
+				v = prim
+			}
+		}
+	}
+
+	switch v := v.(type) {
+	case null:
+		return "null", true
+	case JSBoolean:
+		if v {
+			return "true", true
+		}
+
+		return "false", true
+	case JSString:
+		return quoteJSONString(string(v)), true
+	case JSNumber:
+		if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+			return "null", true
+		}
+
+		return numberToString(float64(v)), true
+	case ObjectValue:
+		if isCallable(v) {
+			break
+		}
+
+		s.enter(holder, key, v)
+		defer func() { s.stack = s.stack[:len(s.stack)-1] }()
+
+		if a, ok := v.(*JSArray); ok {
+			return s.serializeArray(a), true
+		}
+
+		return s.serializeObject(v), true
+	}
+
+	return "", false
+}
+
+func (s *jsonSerializer) serializeObject(o ObjectValue) string {
+	stepback := s.indent
+	s.indent += s.gap
+	defer func() { s.indent = stepback }()
+
+	keys := s.keys
+	if keys == nil {
+		keys = enumerableOwnStringKeys(o)
+	}
+
+	var partial []string
+	for _, k := range keys {
+		str, ok := s.serializeProperty(o, k)
+		if !ok {
+			continue
+		}
+
+		member := quoteJSONString(string(k.(JSString))) + ":"
+		if s.gap != "" {
+			member += " "
+		}
+		partial = append(partial, member+str)
+	}
+
+	return s.join(partial, "{", "}")
+}
+
+func (s *jsonSerializer) serializeArray(a *JSArray) string {
+	stepback := s.indent
+	s.indent += s.gap
+	defer func() { s.indent = stepback }()
+
+	var partial []string
+	for i := int64(0); i < lengthOfArrayLike(a); i++ {
+		str, ok := s.serializeProperty(a, indexKey(i))
+		if !ok {
+			str = "null"
+		}
+		partial = append(partial, str)
+	}
+
+	return s.join(partial, "[", "]")
+}
+
+// join joins the members of an object or the elements of an array, each on
+// its own line at the current indentation if there's a gap
+func (s *jsonSerializer) join(partial []string, open, close string) string {
+	if len(partial) == 0 {
+		return open + close
+	}
+
+	if s.gap == "" {
+		return open + strings.Join(partial, ",") + close
+	}
+
+	stepback := strings.TrimSuffix(s.indent, s.gap)
+	return open + "\n" + s.indent + strings.Join(partial, ",\n"+s.indent) + "\n" + stepback + close
+}
+
+// enter pushes an object on the stack, or throws a TypeError that describes
+// the cycle if the object is already being serialized
+func (s *jsonSerializer) enter(holder ObjectValue, key PropertyKey, o ObjectValue) {
+	for i, e := range s.stack {
+		if e.object == o {
+			panic(newTypeError("Converting circular structure to JSON" + s.describeCycle(i, describeJSONKey(holder, key))))
+		}
+	}
+
+	s.stack = append(s.stack, jsonStackEntry{key: describeJSONKey(holder, key), object: o})
+}
+
+// describeCycle describes the objects of a cycle from the start on the
+// stack, leaving out the middle of long cycles
+func (s *jsonSerializer) describeCycle(start int, closingKey string) string {
+	var b bytes.Buffer
+	b.WriteString("\n    --> starting at object with constructor " + jsonConstructorName(s.stack[start].object))
+
+	line := func(e jsonStackEntry) {
+		b.WriteString("\n    |     " + e.key + " -> object with constructor " + jsonConstructorName(e.object))
+	}
+
+	i := start + 1
+	for ; i < len(s.stack) && i < start+3; i++ {
+		line(s.stack[i])
+	}
+
+	if len(s.stack) > i+1 {
+		b.WriteString("\n    |     ...")
+	}
+
+	if i < len(s.stack)-1 {
+		i = len(s.stack) - 1
+	}
+	for ; i < len(s.stack); i++ {
+		line(s.stack[i])
+	}
+
+	b.WriteString("\n    --- " + closingKey + " closes the circle")
+
+	return b.String()
+}
+
+// describeJSONKey describes the key of a property in the message of a
+// cycle, like index 0 or property 'a'
+func describeJSONKey(holder ObjectValue, key PropertyKey) string {
+	if _, ok := holder.(*JSArray); ok {
+		return "index " + string(key.(JSString))
+	}
+
+	if key == JSString("") {
+		return "<anonymous>"
+	}
+
+	return "property '" + string(key.(JSString)) + "'"
+}
+
+func jsonConstructorName(o ObjectValue) string {
+	name := constructorName(o)
+	if name == "" {
+		name = "Object"
+	}
+
+	return "'" + name + "'"
+}
+
+// quoteJSONString quotes a string with double quotes, escaping the quotes,
+// the backslashes and the control characters
+// Lone surrogates aren't escaped as \uXXXX, because strings hold UTF-8, where
+// a lone surrogate is already replaced with U+FFFD.
+func quoteJSONString(s string) string {
+	var b bytes.Buffer
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+
+	return b.String()
+}
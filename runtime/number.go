@@ -0,0 +1,357 @@
+package runtime
+
+import (
+	"bytes"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+const radixDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// numberToRadixString implements Number::toString for the radixes other
+// than 10, writing the fraction digits only as far as they tell the number
+// apart from its neighbors like V8 does
+func numberToRadixString(f float64, radix int) string {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "Infinity"
+	case math.IsInf(f, -1):
+		return "-Infinity"
+	}
+
+	negative := f < 0
+	if negative {
+		f = -f
+	}
+
+	integer := math.Floor(f)
+	fraction := f - integer
+	// half the distance to the next number
+	delta := math.Max(0.5*(math.Nextafter(f, math.Inf(1))-f), math.SmallestNonzeroFloat64)
+	var fractionDigits []byte
+	if fraction >= delta {
+		for {
+			fraction *= float64(radix)
+			delta *= float64(radix)
+			digit := int(fraction)
+			fractionDigits = append(fractionDigits, radixDigits[digit])
+			fraction -= float64(digit)
+			// round half to even, carrying over into the digits before
+			if (fraction > 0.5 || (fraction == 0.5 && digit&1 == 1)) && fraction+delta > 1 {
+				for {
+					last := len(fractionDigits) - 1
+					if last < 0 {
+						integer++
+						break
+					}
+
+					d := strings.IndexByte(radixDigits, fractionDigits[last])
+					fractionDigits = fractionDigits[:last]
+					if d+1 < radix {
+						fractionDigits = append(fractionDigits, radixDigits[d+1])
+						break
+					}
+				}
+				break
+			}
+
+			if fraction < delta {
+				break
+			}
+		}
+	}
+
+	// the digits below the precision of a float64 are zeros
+	var integerDigits []byte
+	for integer/float64(radix) >= 1<<53 {
+		integer /= float64(radix)
+		integerDigits = append(integerDigits, '0')
+	}
+	for {
+		remainder := math.Mod(integer, float64(radix))
+		integerDigits = append(integerDigits, radixDigits[int(remainder)])
+		integer = (integer - remainder) / float64(radix)
+		if integer <= 0 {
+			break
+		}
+	}
+
+	var b bytes.Buffer
+	if negative {
+		b.WriteByte('-')
+	}
+	for i := len(integerDigits) - 1; i >= 0; i-- {
+		b.WriteByte(integerDigits[i])
+	}
+	if len(fractionDigits) > 0 {
+		b.WriteByte('.')
+		b.Write(fractionDigits)
+	}
+
+	return b.String()
+}
+
+// exactDigits returns the decimal digits of a positive finite number, which
+// are all the digits of its exact binary value, and the exponent of the
+// first digit
+func exactDigits(f float64) (string, int) {
+	// a float64 has at most 767 significant decimal digits
+	s := strconv.FormatFloat(f, 'e', 767, 64)
+	e := strings.IndexByte(s, 'e')
+	exp, _ := strconv.Atoi(s[e+1:])
+
+	return strings.TrimRight(s[:1]+s[2:e], "0"), exp
+}
+
+// roundDigits keeps the first n digits of the exact digits of a number,
+// rounding half up, and returns them with the exponent of the first digit,
+// which a carry increases
+func roundDigits(digits string, exp int, n int) (string, int) {
+	if n < 0 {
+		return "", exp
+	}
+	if len(digits) <= n {
+		return digits + strings.Repeat("0", n-len(digits)), exp
+	}
+
+	b := []byte(digits[:n])
+	if digits[n] >= '5' {
+		i := n - 1
+		for ; i >= 0 && b[i] == '9'; i-- {
+			b[i] = '0'
+		}
+		if i < 0 {
+			// rounding up nothing but nines gives 1 followed by zeros
+			if n == 0 {
+				return "1", exp + 1
+			}
+
+			return "1" + string(b[:n-1]), exp + 1
+		}
+		b[i]++
+	}
+
+	return string(b), exp
+}
+
+// numberToFixed implements Number.prototype.toFixed for numbers below 1e21,
+// writing f digits after the decimal point
+func numberToFixed(x float64, f int) string {
+	sign := ""
+	if x < 0 {
+		sign, x = "-", -x
+	}
+
+	n := "0"
+	if x != 0 {
+		digits, exp := exactDigits(x)
+		// the digits of the integer n for which n / 10^f is closest to x
+		if rounded, exp := roundDigits(digits, exp, exp+1+f); rounded != "" {
+			n = rounded + strings.Repeat("0", exp+1+f-len(rounded))
+		}
+	}
+
+	if len(n) <= f {
+		n = strings.Repeat("0", f+1-len(n)) + n
+	}
+	if f == 0 {
+		return sign + n
+	}
+
+	return sign + n[:len(n)-f] + "." + n[len(n)-f:]
+}
+
+// numberToExponential implements Number.prototype.toExponential for finite
+// numbers, writing f digits after the decimal point, or as many as it takes
+// to tell the number apart if f is negative
+func numberToExponential(x float64, f int) string {
+	sign := ""
+	if x < 0 {
+		sign, x = "-", -x
+	}
+
+	var digits string
+	var exp int
+	switch {
+	case x == 0 && f < 0:
+		digits = "0"
+	case x == 0:
+		digits = strings.Repeat("0", f+1)
+	case f < 0:
+		s := strconv.FormatFloat(x, 'e', -1, 64)
+		e := strings.IndexByte(s, 'e')
+		digits = strings.Replace(s[:e], ".", "", 1)
+		exp, _ = strconv.Atoi(s[e+1:])
+	default:
+		digits, exp = exactDigits(x)
+		digits, exp = roundDigits(digits, exp, f+1)
+	}
+
+	return sign + exponentialNotation(digits, exp)
+}
+
+// numberToPrecision implements Number.prototype.toPrecision for finite
+// numbers, writing p significant digits
+func numberToPrecision(x float64, p int) string {
+	sign := ""
+	if x < 0 {
+		sign, x = "-", -x
+	}
+
+	digits, exp := strings.Repeat("0", p), 0
+	if x != 0 {
+		digits, exp = exactDigits(x)
+		digits, exp = roundDigits(digits, exp, p)
+	}
+
+	switch {
+	case exp < -6 || exp >= p:
+		return sign + exponentialNotation(digits, exp)
+	case exp == p-1:
+		return sign + digits
+	case exp >= 0:
+		return sign + digits[:exp+1] + "." + digits[exp+1:]
+	default:
+		return sign + "0." + strings.Repeat("0", -exp-1) + digits
+	}
+}
+
+// exponentialNotation writes digits with the decimal point after the first
+// digit, and the exponent like e+21
+func exponentialNotation(digits string, exp int) string {
+	s := digits[:1]
+	if len(digits) > 1 {
+		s += "." + digits[1:]
+	}
+	if exp < 0 {
+		return s + "e-" + strconv.Itoa(-exp)
+	}
+
+	return s + "e+" + strconv.Itoa(exp)
+}
+
+// parseFloat parses the longest prefix of a string that is a decimal
+// literal, after the leading white space
+func parseFloat(s string) float64 {
+	s = strings.TrimLeftFunc(s, isWhiteSpaceOrLineTerminator)
+	i := 0
+	if i < len(s) && (s[i] == '+' || s[i] == '-') {
+		i++
+	}
+	if strings.HasPrefix(s[i:], "Infinity") {
+		if s[0] == '-' {
+			return math.Inf(-1)
+		}
+
+		return math.Inf(1)
+	}
+
+	digits := scanDigits(s, &i)
+	if i < len(s) && s[i] == '.' {
+		i++
+		digits += scanDigits(s, &i)
+	}
+	if digits == 0 {
+		return math.NaN()
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		j := i + 1
+		if j < len(s) && (s[j] == '+' || s[j] == '-') {
+			j++
+		}
+		if scanDigits(s, &j) > 0 {
+			i = j
+		}
+	}
+
+	// a number out of range is parsed as an infinity or zero
+	f, _ := strconv.ParseFloat(s[:i], 64)
+	return f
+}
+
+// scanDigits advances i past the decimal digits of s at i, and returns how
+// many there are
+func scanDigits(s string, i *int) int {
+	start := *i
+	for *i < len(s) && '0' <= s[*i] && s[*i] <= '9' {
+		*i++
+	}
+
+	return *i - start
+}
+
+// parseInt parses the longest prefix of a string that is an integer in a
+// radix, after the leading white space
+// A radix of 0 is 10, or 16 for a string starting with 0x.
+func parseInt(s string, radix int32) float64 {
+	s = strings.TrimLeftFunc(s, isWhiteSpaceOrLineTerminator)
+	sign := 1.0
+	if s != "" && (s[0] == '+' || s[0] == '-') {
+		if s[0] == '-' {
+			sign = -1
+		}
+		s = s[1:]
+	}
+
+	stripPrefix := true
+	if radix != 0 {
+		if radix < 2 || radix > 36 {
+			return math.NaN()
+		}
+		stripPrefix = radix == 16
+	} else {
+		radix = 10
+	}
+	if stripPrefix && len(s) >= 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
+		s, radix = s[2:], 16
+	}
+
+	end := 0
+	for end < len(s) && digitValue(rune(s[end])) < int(radix) {
+		end++
+	}
+	if end == 0 {
+		return math.NaN()
+	}
+
+	return sign * parseDigits(s[:end], int(radix))
+}
+
+// parseDigits converts digits in a radix to a number like V8 does: decimal
+// digits and digits in a power of two radix are rounded correctly, and the
+// others are added up in parts that may accumulate rounding errors
+func parseDigits(digits string, radix int) float64 {
+	if radix == 10 {
+		f, _ := strconv.ParseFloat(digits, 64)
+		return f
+	}
+
+	if radix&(radix-1) == 0 {
+		n, _ := new(big.Int).SetString(digits, radix)
+		f, _ := new(big.Float).SetInt(n).Float64()
+		return f
+	}
+
+	// the parts start after the leading zeros
+	digits = strings.TrimLeft(digits, "0")
+	const maxMultiplier = math.MaxUint32 / 36
+	number := 0.0
+	for i := 0; i < len(digits); {
+		part, multiplier := uint32(0), uint32(1)
+		for ; i < len(digits); i++ {
+			m := multiplier * uint32(radix)
+			if m > maxMultiplier {
+				break
+			}
+			part = part*uint32(radix) + uint32(digitValue(rune(digits[i])))
+			multiplier = m
+		}
+		number = number*float64(multiplier) + float64(part)
+	}
+
+	return number
+}
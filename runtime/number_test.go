@@ -0,0 +1,154 @@
+package runtime
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// numberToStringTests are the results of V8 for the conversions of numbers
+// to strings in radix 10
+var numberToStringTests = []struct {
+	name string
+	x    float64
+	want string
+}{
+	{"integer", 123, "123"},
+	{"negative", -1.5, "-1.5"},
+	{"zero", 0, "0"},
+	{"negative zero", math.Copysign(0, -1), "0"},
+	{"NaN", math.NaN(), "NaN"},
+	{"infinity", math.Inf(1), "Infinity"},
+	{"negative infinity", math.Inf(-1), "-Infinity"},
+	{"shortest digits", 0.30000000000000004, "0.30000000000000004"},
+	{"21 digit integer", 1e20, "100000000000000000000"},
+	{"22 digit integer", 1e21, "1e+21"},
+	{"large integer padded with zeros", 1 << 64, "18446744073709552000"},
+	{"large in exponential notation", 1.2345678901234568e+29, "1.2345678901234568e+29"},
+	{"six leading zeros", 0.000001, "0.000001"},
+	{"seven leading zeros", 1e-7, "1e-7"},
+	{"small with digits", 1.5e-10, "1.5e-10"},
+	{"max value", math.MaxFloat64, "1.7976931348623157e+308"},
+	{"min value", math.SmallestNonzeroFloat64, "5e-324"},
+	{"max safe integer", 1<<53 - 1, "9007199254740991"},
+}
+
+func TestNumberToString(t *testing.T) {
+	for _, test := range numberToStringTests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := numberToString(test.x); got != test.want {
+				t.Errorf("want=%q got=%q", test.want, got)
+			}
+		})
+	}
+}
+
+// numberConversionTests are the results of V8 for the conversions of numbers
+// to strings in a radix, in fixed-point and in exponential notation, given
+// the radix or the number of digits
+var numberConversionTests = []struct {
+	name    string
+	convert func(float64, int) string
+	x       float64
+	digits  int
+	want    string
+}{
+	{"radix integer", numberToRadixString, 255, 16, "ff"},
+	{"radix negative", numberToRadixString, -255, 2, "-11111111"},
+	{"radix zero", numberToRadixString, 0, 7, "0"},
+	{"radix negative zero", numberToRadixString, math.Copysign(0, -1), 2, "0"},
+	{"radix fraction", numberToRadixString, 0.5, 2, "0.1"},
+	{"radix third", numberToRadixString, 0.3333333333333333, 3, "0.1"},
+	{"radix repeating fraction", numberToRadixString, 0.1, 3, "0.0022002200220022002200220022002201"},
+	{"radix shortest fraction", numberToRadixString, 0.1, 2, "0.0001100110011001100110011001100110011001100110011001101"},
+	{"radix 36", numberToRadixString, 123456789.123, 36, "21i3v9.4feor"},
+	{"radix large integer", numberToRadixString, 1 << 60, 7, "2031000661631341064200"},
+	{"radix fraction near one", numberToRadixString, 0.9999999999999999, 5, "0.44444444444444444444443"},
+	{"radix NaN", numberToRadixString, math.NaN(), 16, "NaN"},
+	{"radix infinity", numberToRadixString, math.Inf(1), 16, "Infinity"},
+	{"radix negative infinity", numberToRadixString, math.Inf(-1), 8, "-Infinity"},
+	{"radix small", numberToRadixString, 1e-7, 16, "0.000001ad7f29abcaf48"},
+	{"radix pi", numberToRadixString, 3.141592653589793, 12, "3.184809493b91864"},
+	{"fixed zero digits", numberToFixed, 1.5, 0, "2"},
+	{"fixed rounds half up", numberToFixed, 2.5, 0, "3"},
+	{"fixed exact binary value", numberToFixed, 1.005, 2, "1.00"},
+	{"fixed carry", numberToFixed, 9.995, 2, "9.99"},
+	{"fixed carry into new digit", numberToFixed, 99.99, 1, "100.0"},
+	{"fixed negative", numberToFixed, -1.45, 1, "-1.4"},
+	{"fixed negative zero", numberToFixed, math.Copysign(0, -1), 2, "0.00"},
+	{"fixed tiny rounds to zero", numberToFixed, 1e-10, 5, "0.00000"},
+	{"fixed negative tiny", numberToFixed, -1e-10, 2, "-0.00"},
+	{"fixed padding", numberToFixed, 0.5, 10, "0.5000000000"},
+	{"fixed large", numberToFixed, 100000000000000000000, 2, "100000000000000000000.00"},
+	{"fixed many digits", numberToFixed, 0.1, 30, "0.100000000000000005551115123126"},
+	{"fixed 100 digits", numberToFixed, 0.3333333333333333, 100, "0.3333333333333333148296162562473909929394721984863281250000000000000000000000000000000000000000000000"},
+	{"fixed half of last digit", numberToFixed, 0.05, 1, "0.1"},
+	{"fixed zero", numberToFixed, 0, 0, "0"},
+	{"precision rounds", numberToPrecision, 123.456, 4, "123.5"},
+	{"precision exponential large", numberToPrecision, 123456, 2, "1.2e+5"},
+	{"precision exponential small", numberToPrecision, 1e-7, 3, "1.00e-7"},
+	{"precision smallest fixed", numberToPrecision, 0.000001234, 2, "0.0000012"},
+	{"precision integer exact", numberToPrecision, 123, 3, "123"},
+	{"precision padding", numberToPrecision, 1.5, 5, "1.5000"},
+	{"precision carry", numberToPrecision, 9.99, 2, "10"},
+	{"precision carry to exponential", numberToPrecision, 99.9, 2, "1.0e+2"},
+	{"precision zero", numberToPrecision, 0, 3, "0.00"},
+	{"precision negative", numberToPrecision, -0.00012345, 3, "-0.000123"},
+	{"precision one digit", numberToPrecision, 5.5, 1, "6"},
+	{"precision 100 digits", numberToPrecision, 3.141592653589793, 100, "3.141592653589793115997963468544185161590576171875000000000000000000000000000000000000000000000000000"},
+	{"precision max value", numberToPrecision, 1.7976931348623157e+308, 3, "1.80e+308"},
+	{"exponential shortest", numberToExponential, 123.456, -1, "1.23456e+2"},
+	{"exponential shortest integer", numberToExponential, 1, -1, "1e+0"},
+	{"exponential digits", numberToExponential, 123.456, 2, "1.23e+2"},
+	{"exponential rounds half up", numberToExponential, 1.25, 1, "1.3e+0"},
+	{"exponential exact binary value", numberToExponential, 1.45, 1, "1.4e+0"},
+	{"exponential carry", numberToExponential, 9.99, 1, "1.0e+1"},
+	{"exponential zero", numberToExponential, 0, 3, "0.000e+0"},
+	{"exponential zero shortest", numberToExponential, 0, -1, "0e+0"},
+	{"exponential negative", numberToExponential, -0.000123, 1, "-1.2e-4"},
+	{"exponential min value", numberToExponential, 5e-324, 2, "4.94e-324"},
+	{"exponential max value", numberToExponential, 1.7976931348623157e+308, -1, "1.7976931348623157e+308"},
+	{"exponential padding", numberToExponential, 2, 4, "2.0000e+0"},
+	{"radix large exponent", numberToRadixString, math.Pow(2, 100), 2, "1" + strings.Repeat("0", 100)},
+}
+
+func TestNumberConversions(t *testing.T) {
+	for _, test := range numberConversionTests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.convert(test.x, test.digits); got != test.want {
+				t.Errorf("want=%q got=%q", test.want, got)
+			}
+		})
+	}
+}
+
+// parseDigitsTests are the results of parseInt in V8, which rounds the
+// digits in radixes other than 10 and the powers of two by parts
+var parseDigitsTests = []struct {
+	name   string
+	digits string
+	radix  int
+	want   float64
+}{
+	{"parse decimal", "123456789012345678901234567890", 10, 1.2345678901234568e+29},
+	{"parse hex", "ff", 16, 255},
+	{"parse binary", "101", 2, 5},
+	{"parse power of two rounds correctly", "1fffffffffffff1", 16, 144115188075855860},
+	{"parse octal large", "7777777777777777777777", 8, 73786976294838210000},
+	{"parse base 36", "zz", 36, 1295},
+	{"parse base 3", "2222222222222222222222222222222222222222", 3, 12157665459056929000},
+	{"parse leading zeros", "00000000000000000000000000000000000012", 7, 9},
+	{"parse base 36 large", "thequickbrownfoxjumpsoverthelazydog", 36, 2.420279590275804e+54},
+	{"parse base 10 many digits", "9007199254740993", 10, 9007199254740992},
+	{"parse base 5 rounding", "1234123412341234123412341234", 5, 11581832017654028000},
+}
+
+func TestParseDigits(t *testing.T) {
+	for _, test := range parseDigitsTests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := parseDigits(test.digits, test.radix); got != test.want {
+				t.Errorf("want=%v got=%v", test.want, got)
+			}
+		})
+	}
+}
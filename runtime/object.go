@@ -1,11 +1,6 @@
 package runtime
 
-import (
-	"path/filepath"
-	"reflect"
-	"runtime"
-	"strings"
-)
+import "math"
 
 type Object interface {
 	Type() JSObjectType
@@ -14,46 +9,372 @@ type Object interface {
 type JSObjectType string
 
 const (
-	JS_OBJECT_TYPE_OBJECT   = "object"
-	JS_OBJECT_TYPE_STRING   = "string"
-	JS_OBJECT_TYPE_NUMBER   = "number"
-	JS_OBJECT_TYPE_FUNCTION = "function"
+	JS_OBJECT_TYPE_UNDEFINED = "undefined"
+	JS_OBJECT_TYPE_NULL      = "null"
+	JS_OBJECT_TYPE_OBJECT    = "object"
+	JS_OBJECT_TYPE_STRING    = "string"
+	JS_OBJECT_TYPE_NUMBER    = "number"
+	JS_OBJECT_TYPE_BOOLEAN   = "boolean"
+	JS_OBJECT_TYPE_FUNCTION  = "function"
+	JS_OBJECT_TYPE_SYMBOL    = "symbol"
 )
 
+// Undefined is the undefined value, which is the zero value of Object
+// Missing arguments and properties, and variables that aren't assigned
+// are undefined.
+var Undefined Object
+
+type null struct{}
+
+func (self null) Type() JSObjectType { return JS_OBJECT_TYPE_NULL }
+
+// Null is the null value
+var Null Object = null{}
+
+// ObjectValue is implemented by every object: ordinary objects as well as
+// objects with internal slots and exotic behaviors, which embed JSObject and
+// override the internal methods
+type ObjectValue interface {
+	Object
+	GetProperty(prop string) (Object, bool)
+	object() *JSObject
+	getOwnProperty(key PropertyKey) *Property
+	defineOwnProperty(key PropertyKey, desc *PropertyDescriptor) bool
+	deleteProperty(key PropertyKey) bool
+	ownKeys() []PropertyKey
+}
+
+// JSObject is an ordinary object
+// Properties are kept in insertion order, and are looked up along the
+// prototype chain.
 type JSObject struct {
-	properties map[string]Object
+	class         string
+	proto         ObjectValue
+	keys          []PropertyKey
+	properties    map[PropertyKey]*Property
+	nonExtensible bool
+	// primitive is the value of a String or Number wrapper object
+	primitive Object
+	// weakRef stands for the object in the weak collections that hold it
+	weakRef *weakRef
+}
+
+// NewObject creates an empty object that inherits from Object.prototype
+func NewObject() *JSObject {
+	return &JSObject{class: "Object", proto: objectPrototype}
+}
+
+// NewObjectLiteral creates the object of an object literal, whose
+// properties are added with the Init methods
+func NewObjectLiteral() *JSObject {
+	return NewObject()
 }
 
 func (self *JSObject) Type() JSObjectType { return JS_OBJECT_TYPE_OBJECT }
 
+func (self *JSObject) object() *JSObject { return self }
+
+// DefineProperty creates or replaces a writable, enumerable and configurable
+// data property
 func (self *JSObject) DefineProperty(prop string, value Object) {
-	self.properties[prop] = value
+	self.setOwn(JSString(prop), &Property{value: value, writable: true, enumerable: true, configurable: true})
+}
+
+// GetProperty looks up a property along the prototype chain
+// A missing property is undefined, and ok tells whether it was found.
+func (self *JSObject) GetProperty(prop string) (v Object, ok bool) {
+	return lookupProperty(self, prop)
+}
+
+// lookupProperty gets a property like [[Get]] and tells whether it was found
+func lookupProperty(o ObjectValue, prop string) (Object, bool) {
+	key := JSString(prop)
+	for obj := o; obj != nil; obj = obj.object().proto {
+		if p := obj.getOwnProperty(key); p != nil {
+			return p.get(o), true
+		}
+	}
+
+	return Undefined, false
+}
+
+// Init adds a property to an object literal
+func (self *JSObject) Init(key PropertyKey, value Object) *JSObject {
+	self.setOwn(key, &Property{value: value, writable: true, enumerable: true, configurable: true})
+	return self
+}
+
+// InitMethod adds a method to an object literal, naming the function after
+// the property
+func (self *JSObject) InitMethod(key PropertyKey, fn Object) *JSObject {
+	SetFunctionName(fn, key, "")
+	return self.Init(key, fn)
+}
+
+// InitGetter adds the getter of an accessor property to an object literal
+func (self *JSObject) InitGetter(key PropertyKey, getter Object) *JSObject {
+	SetFunctionName(getter, key, "get")
+	p := self.getOwnProperty(key)
+	if p == nil || !p.accessor {
+		p = &Property{accessor: true, enumerable: true, configurable: true}
+		self.setOwn(key, p)
+	}
+	p.getter = getter
+
+	return self
+}
+
+// InitSetter adds the setter of an accessor property to an object literal
+func (self *JSObject) InitSetter(key PropertyKey, setter Object) *JSObject {
+	SetFunctionName(setter, key, "set")
+	p := self.getOwnProperty(key)
+	if p == nil || !p.accessor {
+		p = &Property{accessor: true, enumerable: true, configurable: true}
+		self.setOwn(key, p)
+	}
+	p.setter = setter
+
+	return self
+}
+
+// InitSpread copies the own enumerable properties of a value to an object
+// literal, like {...v}
+func (self *JSObject) InitSpread(v Object) *JSObject {
+	copyDataProperties(self, v, nil)
+	return self
+}
+
+// InitProto sets the prototype of an object literal with a __proto__ property
+// A value that is neither an object nor null is ignored.
+func (self *JSObject) InitProto(proto Object) *JSObject {
+	if v, ok := toPrototype(proto); ok {
+		self.proto = v
+	}
+
+	return self
 }
 
-func (self *JSObject) GetProperty(prop string) (Object, error) {
-	obj := self.properties[prop]
-	if obj == nil {
-		return nil, &ReferenceError{prop}
+func (self *JSObject) getOwnProperty(key PropertyKey) *Property {
+	return self.properties[key]
+}
+
+// setOwn creates or replaces an own property without any checks
+func (self *JSObject) setOwn(key PropertyKey, p *Property) {
+	if self.properties == nil {
+		self.properties = make(map[PropertyKey]*Property)
+	}
+
+	if _, ok := self.properties[key]; !ok {
+		self.keys = append(self.keys, key)
+	}
+	self.properties[key] = p
+}
+
+// defineOwnProperty implements ValidateAndApplyPropertyDescriptor of
+// ordinary objects
+func (self *JSObject) defineOwnProperty(key PropertyKey, desc *PropertyDescriptor) bool {
+	current := self.getOwnProperty(key)
+	if current == nil {
+		if self.nonExtensible {
+			return false
+		}
+
+		self.setOwn(key, desc.toProperty())
+		return true
+	}
+
+	if !isCompatibleDescriptor(current, desc) {
+		return false
 	}
 
-	return obj, nil
+	desc.applyTo(current)
+	return true
+}
+
+// isCompatibleDescriptor tells whether a descriptor can be applied to an
+// existing property, which can only be changed in limited ways if it isn't
+// configurable
+func isCompatibleDescriptor(current *Property, desc *PropertyDescriptor) bool {
+	if current.configurable {
+		return true
+	}
+
+	if desc.HasConfigurable && desc.Configurable {
+		return false
+	}
+	if desc.HasEnumerable && desc.Enumerable != current.enumerable {
+		return false
+	}
+	if desc.isAccessor() != current.accessor && !desc.isGeneric() {
+		return false
+	}
+	if current.accessor {
+		if desc.HasGet && desc.Get != current.getter {
+			return false
+		}
+		if desc.HasSet && desc.Set != current.setter {
+			return false
+		}
+	} else if !current.writable {
+		if desc.HasWritable && desc.Writable {
+			return false
+		}
+		if desc.HasValue && !SameValue(desc.Value, current.value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (self *JSObject) deleteProperty(key PropertyKey) bool {
+	p := self.properties[key]
+	if p == nil {
+		return true
+	}
+	if !p.configurable {
+		return false
+	}
+
+	delete(self.properties, key)
+	for i, k := range self.keys {
+		if k == key {
+			self.keys = append(self.keys[:i], self.keys[i+1:]...)
+			break
+		}
+	}
+
+	return true
+}
+
+// ownKeys returns the own property keys in the order of the spec:
+// array indices in ascending order, then strings and then symbols in the
+// order they were created
+func (self *JSObject) ownKeys() []PropertyKey {
+	return orderKeys(self.keys)
 }
 
 type JSString string
 
 func (self JSString) Type() JSObjectType { return JS_OBJECT_TYPE_STRING }
 
+func (self JSString) propertyKey() {}
+
 type JSNumber float64
 
 func (self JSNumber) Type() JSObjectType { return JS_OBJECT_TYPE_NUMBER }
 
+// Infinity is the positive infinite number, which Go has no constant for
+var Infinity = JSNumber(math.Inf(1))
+
+type JSBoolean bool
+
+func (self JSBoolean) Type() JSObjectType { return JS_OBJECT_TYPE_BOOLEAN }
+
 type JSFunction struct {
-	fn func([]Object)
+	JSObject
+	name   string
+	length int
+	fn     func(this Object, args []Object) Object
+	// construct implements [[Construct]], it's nil for functions that
+	// aren't constructors
+	construct func(args []Object, newTarget ObjectValue) Object
+	// isClass is true for the constructor of a class
+	isClass bool
+	// generator is true for a generator function, and async for an async
+	// function
+	generator bool
+	async     bool
+	// initialized is true once the name and length properties are created
+	initialized bool
+	// lazyPrototype is true for an ordinary function or a generator
+	// function, whose prototype property is created along with the name and
+	// length properties
+	lazyPrototype bool
+}
+
+func NewFunction(name string, length int, fn func(this Object, args []Object) Object) *JSFunction {
+	return &JSFunction{
+		JSObject: JSObject{class: "Function", proto: functionPrototype},
+		name:     name,
+		length:   length,
+		fn:       fn,
+	}
 }
 
-func (self *JSFunction) FuncName() string {
-	fullName := runtime.FuncForPC(reflect.ValueOf(self.fn).Pointer()).Name()
-	return strings.TrimPrefix(filepath.Ext(fullName), ".")
+func (self *JSFunction) Name() string {
+	return self.name
+}
+
+// Call calls the function, which is on the call stack until it returns
+func (self *JSFunction) Call(this Object, args []Object) Object {
+	checkCallDepth()
+	callStack = append(callStack, callFrame{function: self})
+	defer func() { callStack = callStack[:len(callStack)-1] }()
+
+	return self.fn(this, args)
+}
+
+// checkCallDepth throws a RangeError for a call that would exceed the
+// maximum depth of the call stack
+func checkCallDepth() {
+	if len(callStack) >= maxCallDepth {
+		panic(newRangeError("Maximum call stack size exceeded"))
+	}
+}
+
+// Construct calls the function as a constructor, which creates an object
+// that inherits from the prototype property of newTarget
+func (self *JSFunction) Construct(args []Object, newTarget ObjectValue) Object {
+	checkCallDepth()
+	callStack = append(callStack, callFrame{function: self, construct: true})
+	defer func() { callStack = callStack[:len(callStack)-1] }()
+
+	return self.construct(args, newTarget)
 }
 
 func (self *JSFunction) Type() JSObjectType { return JS_OBJECT_TYPE_FUNCTION }
+
+// init creates the length and name properties on first use of the
+// properties so that creating a function is cheap
+func (self *JSFunction) init() {
+	if self.initialized {
+		return
+	}
+
+	self.initialized = true
+	self.JSObject.setOwn(JSString("length"), &Property{value: JSNumber(self.length), configurable: true})
+	self.JSObject.setOwn(JSString("name"), &Property{value: JSString(self.name), configurable: true})
+	if self.lazyPrototype && self.generator {
+		self.JSObject.setOwn(JSString("prototype"), &Property{value: &JSObject{class: "Object", proto: generatorPrototype}, writable: true})
+	} else if self.lazyPrototype {
+		proto := NewObject()
+		proto.setOwn(JSString("constructor"), &Property{value: self, writable: true, configurable: true})
+		self.JSObject.setOwn(JSString("prototype"), &Property{value: proto, writable: true})
+	}
+}
+
+func (self *JSFunction) GetProperty(prop string) (Object, bool) {
+	self.init()
+	return lookupProperty(self, prop)
+}
+
+func (self *JSFunction) getOwnProperty(key PropertyKey) *Property {
+	self.init()
+	return self.JSObject.getOwnProperty(key)
+}
+
+func (self *JSFunction) defineOwnProperty(key PropertyKey, desc *PropertyDescriptor) bool {
+	self.init()
+	return self.JSObject.defineOwnProperty(key, desc)
+}
+
+func (self *JSFunction) deleteProperty(key PropertyKey) bool {
+	self.init()
+	return self.JSObject.deleteProperty(key)
+}
+
+func (self *JSFunction) ownKeys() []PropertyKey {
+	self.init()
+	return self.JSObject.ownKeys()
+}
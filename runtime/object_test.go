@@ -0,0 +1,70 @@
+package runtime
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPrototypeChain(t *testing.T) {
+	proto := NewObject()
+	proto.DefineProperty("inherited", JSString("proto"))
+
+	o := NewObject()
+	o.proto = proto
+	o.DefineProperty("own", JSNumber(1))
+
+	if v, ok := o.GetProperty("inherited"); !ok || v != JSString("proto") {
+		t.Fatalf("inherited property not found: v=%v", v)
+	}
+
+	if v, ok := o.GetProperty("missing"); ok || v != Undefined {
+		t.Fatalf("missing property should be undefined and not found: v=%v", v)
+	}
+
+	if !setProperty(o, JSString("inherited"), JSString("own"), o) || o.getOwnProperty(JSString("inherited")) == nil {
+		t.Fatal("assigning an inherited property should create an own property")
+	}
+
+	if v, _ := proto.GetProperty("inherited"); v != JSString("proto") {
+		t.Fatalf("assignment shouldn't change the prototype: v=%v", v)
+	}
+}
+
+func TestOwnKeysOrder(t *testing.T) {
+	o := NewObjectLiteral().
+		Init(JSString("b"), nil).
+		Init(JSString("10"), nil).
+		Init(JSString("a"), nil).
+		Init(JSString("2"), nil).
+		Init(JSString("01"), nil)
+
+	want := []PropertyKey{JSString("2"), JSString("10"), JSString("b"), JSString("a"), JSString("01")}
+	if got := o.ownKeys(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("keys not in order: want=%v got=%v", want, got)
+	}
+}
+
+func TestDefineOwnProperty(t *testing.T) {
+	o := NewObject()
+	if !o.defineOwnProperty(JSString("a"), &PropertyDescriptor{Value: JSNumber(1), HasValue: true}) {
+		t.Fatal("defining a new property should succeed")
+	}
+
+	p := o.getOwnProperty(JSString("a"))
+	if p.writable || p.enumerable || p.configurable {
+		t.Fatalf("absent attributes should default to false: %+v", p)
+	}
+
+	if o.defineOwnProperty(JSString("a"), &PropertyDescriptor{Value: JSNumber(2), HasValue: true}) {
+		t.Fatal("changing the value of a non-writable, non-configurable property should fail")
+	}
+
+	if !o.defineOwnProperty(JSString("a"), &PropertyDescriptor{Value: JSNumber(1), HasValue: true}) {
+		t.Fatal("redefining a property with the same value should succeed")
+	}
+
+	o.nonExtensible = true
+	if o.defineOwnProperty(JSString("b"), &PropertyDescriptor{}) {
+		t.Fatal("adding a property to a non-extensible object should fail")
+	}
+}
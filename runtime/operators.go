@@ -0,0 +1,393 @@
+package runtime
+
+import (
+	"fmt"
+	"math"
+)
+
+// SameValue compares two values like Object.is
+func SameValue(x, y Object) bool {
+	if a, ok := x.(JSNumber); ok {
+		b, ok := y.(JSNumber)
+		if !ok {
+			return false
+		}
+
+		if math.IsNaN(float64(a)) && math.IsNaN(float64(b)) {
+			return true
+		}
+
+		return a == b && math.Signbit(float64(a)) == math.Signbit(float64(b))
+	}
+
+	return x == y
+}
+
+// StrictEquals compares two values like the === operator
+func StrictEquals(x, y Object) bool {
+	if a, ok := x.(JSNumber); ok {
+		b, ok := y.(JSNumber)
+		return ok && a == b
+	}
+
+	return x == y
+}
+
+// sameValueZero compares two values like SameValue, except that +0 and -0
+// are equal
+func sameValueZero(x, y Object) bool {
+	if a, ok := x.(JSNumber); ok {
+		if b, ok := y.(JSNumber); ok && a != a && b != b {
+			return true
+		}
+	}
+
+	return StrictEquals(x, y)
+}
+
+// compareStrings compares two strings by their UTF-16 code units
+func compareStrings(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	for i := 0; i < len(ra) && i < len(rb); i++ {
+		if ra[i] == rb[i] {
+			continue
+		}
+
+		ca, cb := firstCodeUnit(ra[i]), firstCodeUnit(rb[i])
+		if ca == cb {
+			// both are surrogate pairs with the same high surrogate
+			ca, cb = ra[i], rb[i]
+		}
+		if ca < cb {
+			return -1
+		}
+
+		return 1
+	}
+
+	switch {
+	case len(ra) < len(rb):
+		return -1
+	case len(ra) > len(rb):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// firstCodeUnit returns the first UTF-16 code unit of a code point
+func firstCodeUnit(r rune) rune {
+	if r >= 0x10000 {
+		return 0xd800 + (r-0x10000)>>10
+	}
+
+	return r
+}
+
+// Add implements the + operator, which concatenates strings and adds numbers
+func Add(x, y Object) Object {
+	if a, ok := x.(JSNumber); ok {
+		if b, ok := y.(JSNumber); ok {
+			return a + b
+		}
+	}
+
+	px, py := ToPrimitive(x, "default"), ToPrimitive(y, "default")
+	sx, xIsString := px.(JSString)
+	sy, yIsString := py.(JSString)
+	if xIsString || yIsString {
+		if !xIsString {
+			sx = ToString(px)
+		}
+		if !yIsString {
+			sy = ToString(py)
+		}
+
+		return sx + sy
+	}
+
+	return ToNumber(px) + ToNumber(py)
+}
+
+func Sub(x, y Object) Object {
+	return ToNumber(x) - ToNumber(y)
+}
+
+func Mul(x, y Object) Object {
+	return ToNumber(x) * ToNumber(y)
+}
+
+func Div(x, y Object) Object {
+	return ToNumber(x) / ToNumber(y)
+}
+
+// Mod implements the % operator, whose result has the sign of the dividend
+func Mod(x, y Object) Object {
+	return JSNumber(math.Mod(float64(ToNumber(x)), float64(ToNumber(y))))
+}
+
+// Exp implements the ** operator
+func Exp(x, y Object) Object {
+	return JSNumber(pow(float64(ToNumber(x)), float64(ToNumber(y))))
+}
+
+// pow is Math.pow, which unlike the pow of C returns NaN for 1 ** NaN and
+// 1 ** Infinity
+func pow(x, y float64) float64 {
+	if math.IsNaN(y) || (math.Abs(x) == 1 && math.IsInf(y, 0)) {
+		return math.NaN()
+	}
+
+	return fdlibmPow(x, y)
+}
+
+func LeftShift(x, y Object) Object {
+	return JSNumber(ToInt32(x) << (ToUint32(y) & 31))
+}
+
+func SignedRightShift(x, y Object) Object {
+	return JSNumber(ToInt32(x) >> (ToUint32(y) & 31))
+}
+
+func UnsignedRightShift(x, y Object) Object {
+	return JSNumber(ToUint32(x) >> (ToUint32(y) & 31))
+}
+
+func BitwiseAnd(x, y Object) Object {
+	return JSNumber(ToInt32(x) & ToInt32(y))
+}
+
+func BitwiseOr(x, y Object) Object {
+	return JSNumber(ToInt32(x) | ToInt32(y))
+}
+
+func BitwiseXor(x, y Object) Object {
+	return JSNumber(ToInt32(x) ^ ToInt32(y))
+}
+
+// Negate implements the unary - operator
+func Negate(x Object) Object {
+	return -ToNumber(x)
+}
+
+func BitwiseNot(x Object) Object {
+	return JSNumber(^ToInt32(x))
+}
+
+// TypeOf implements the typeof operator
+func TypeOf(v Object) Object {
+	switch v {
+	case Undefined:
+		return JSString(JS_OBJECT_TYPE_UNDEFINED)
+	case Null:
+		return JSString(JS_OBJECT_TYPE_OBJECT)
+	}
+
+	return JSString(v.Type())
+}
+
+// Void implements the void operator, which discards the value of its operand
+func Void(v Object) Object {
+	return Undefined
+}
+
+// Delete implements the delete operator on a property reference
+// A property that can't be deleted is kept and false is returned.
+func Delete(v Object, key PropertyKey) Object {
+	return JSBoolean(ToObject(v).deleteProperty(key))
+}
+
+// DeleteStrict is Delete in strict mode code, where it's an error to delete
+// a property that can't be deleted
+func DeleteStrict(v Object, key PropertyKey) Object {
+	o := ToObject(v)
+	if !o.deleteProperty(key) {
+		panic(newTypeError(fmt.Sprintf("Cannot delete property '%s' of %s", toDisplayString(key), toDisplayString(o))))
+	}
+
+	return JSBoolean(true)
+}
+
+// LogicalAnd implements the && operator, evaluating right only if left is
+// truthy
+func LogicalAnd(left Object, right func() Object) Object {
+	if !ToBoolean(left) {
+		return left
+	}
+
+	return right()
+}
+
+// LogicalOr implements the || operator, evaluating right only if left is
+// falsy
+func LogicalOr(left Object, right func() Object) Object {
+	if ToBoolean(left) {
+		return left
+	}
+
+	return right()
+}
+
+// Conditional implements the conditional operator, evaluating one of the
+// branches
+func Conditional(test bool, consequent, alternate func() Object) Object {
+	if test {
+		return consequent()
+	}
+
+	return alternate()
+}
+
+// Sequence implements the comma operator, returning the last of the values
+func Sequence(values ...Object) Object {
+	return values[len(values)-1]
+}
+
+// LooseEquals compares two values like the == operator
+func LooseEquals(x, y Object) bool {
+	switch a := x.(type) {
+	case JSNumber:
+		switch b := y.(type) {
+		case JSString, JSBoolean:
+			return a == ToNumber(b)
+		case ObjectValue:
+			return LooseEquals(a, ToPrimitive(b, "default"))
+		}
+	case JSString:
+		switch b := y.(type) {
+		case JSNumber, JSBoolean:
+			return ToNumber(a) == ToNumber(b)
+		case ObjectValue:
+			return LooseEquals(a, ToPrimitive(b, "default"))
+		}
+	case JSBoolean:
+		return LooseEquals(ToNumber(a), y)
+	case *Symbol:
+		if b, ok := y.(ObjectValue); ok {
+			return LooseEquals(a, ToPrimitive(b, "default"))
+		}
+	case ObjectValue:
+		switch b := y.(type) {
+		case JSNumber, JSString, *Symbol:
+			return LooseEquals(ToPrimitive(a, "default"), b)
+		case JSBoolean:
+			return LooseEquals(a, ToNumber(b))
+		}
+	}
+
+	if isNullish(x) || isNullish(y) {
+		return isNullish(x) && isNullish(y)
+	}
+
+	return StrictEquals(x, y)
+}
+
+// isNullish tells whether a value is undefined or null
+func isNullish(v Object) bool {
+	return v == Undefined || v == Null
+}
+
+// isLessThan implements IsLessThan, whose result is undefined if either
+// operand is NaN
+// leftFirst tells whether x is converted to a primitive before y.
+func isLessThan(x, y Object, leftFirst bool) (less bool, undefined bool) {
+	if a, ok := x.(JSNumber); ok {
+		if b, ok := y.(JSNumber); ok {
+			return a < b, a != a || b != b
+		}
+	}
+
+	var px, py Object
+	if leftFirst {
+		px = ToPrimitive(x, "number")
+		py = ToPrimitive(y, "number")
+	} else {
+		py = ToPrimitive(y, "number")
+		px = ToPrimitive(x, "number")
+	}
+
+	if sx, ok := px.(JSString); ok {
+		if sy, ok := py.(JSString); ok {
+			return compareStrings(string(sx), string(sy)) < 0, false
+		}
+	}
+
+	a, b := ToNumber(px), ToNumber(py)
+	return a < b, a != a || b != b
+}
+
+// Less implements the < operator
+func Less(x, y Object) bool {
+	less, _ := isLessThan(x, y, true)
+	return less
+}
+
+// Greater implements the > operator
+func Greater(x, y Object) bool {
+	less, _ := isLessThan(y, x, false)
+	return less
+}
+
+// LessOrEqual implements the <= operator
+func LessOrEqual(x, y Object) bool {
+	less, undefined := isLessThan(y, x, false)
+	return !less && !undefined
+}
+
+// GreaterOrEqual implements the >= operator
+func GreaterOrEqual(x, y Object) bool {
+	less, undefined := isLessThan(x, y, true)
+	return !less && !undefined
+}
+
+// In implements the in operator
+func In(key, o Object) bool {
+	obj, ok := o.(ObjectValue)
+	if !ok {
+		panic(newTypeError(fmt.Sprintf("Cannot use 'in' operator to search for '%s' in %s", toDisplayString(key), toDisplayString(o))))
+	}
+
+	return hasProperty(obj, ToPropertyKey(key))
+}
+
+// InstanceOf implements the instanceof operator, which calls the
+// Symbol.hasInstance method of the target
+func InstanceOf(v, target Object) bool {
+	o, ok := target.(ObjectValue)
+	if !ok {
+		panic(newTypeError("Right-hand side of 'instanceof' is not an object"))
+	}
+
+	if h := getProperty(o, SymbolHasInstance, o); h != functionPrototypeHasInstance && !isNullish(h) {
+		return ToBoolean(Call(h, o, []Object{v}))
+	}
+
+	f, ok := target.(*JSFunction)
+	if !ok {
+		panic(newTypeError("Right-hand side of 'instanceof' is not callable"))
+	}
+
+	return ordinaryHasInstance(f, v)
+}
+
+// ordinaryHasInstance looks for the prototype property of a constructor in
+// the prototype chain of a value
+func ordinaryHasInstance(f *JSFunction, v Object) bool {
+	o, ok := v.(ObjectValue)
+	if !ok {
+		return false
+	}
+
+	proto, ok := getProperty(f, JSString("prototype"), f).(ObjectValue)
+	if !ok {
+		panic(newTypeError("Function has non-object prototype '" + toDisplayString(getProperty(f, JSString("prototype"), f)) + "' in instanceof check"))
+	}
+
+	for p := o.object().proto; p != nil; p = p.object().proto {
+		if p == proto {
+			return true
+		}
+	}
+
+	return false
+}
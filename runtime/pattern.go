@@ -0,0 +1,48 @@
+package runtime
+
+import "fmt"
+
+// CheckDestructurable returns a value destructured by an object pattern,
+// which can't be undefined or null
+// The error names the first property of the pattern unless it's empty.
+func CheckDestructurable(v Object, property string) Object {
+	if !isNullish(v) {
+		return v
+	}
+
+	if property == "" {
+		panic(newTypeError(fmt.Sprintf("Cannot destructure '%s' as it is %s.", ToString(v), ToString(v))))
+	}
+	panic(newTypeError(fmt.Sprintf("Cannot destructure property '%s' of '%s' as it is %s.", property, ToString(v), ToString(v))))
+}
+
+// ObjectRest implements the rest element of an object pattern, copying the
+// own enumerable properties of v that aren't excluded to a new object
+func ObjectRest(v Object, excluded []PropertyKey) *JSObject {
+	rest := NewObject()
+	copyDataProperties(rest, v, excluded)
+
+	return rest
+}
+
+// copyDataProperties copies the own enumerable properties of a value to an
+// object, skipping the excluded keys
+func copyDataProperties(target ObjectValue, v Object, excluded []PropertyKey) {
+	if isNullish(v) {
+		return
+	}
+
+	from := ToObject(v)
+next:
+	for _, k := range from.ownKeys() {
+		for _, e := range excluded {
+			if k == e {
+				continue next
+			}
+		}
+
+		if p := from.getOwnProperty(k); p != nil && p.enumerable {
+			createDataProperty(target, k, p.get(from))
+		}
+	}
+}
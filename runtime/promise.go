@@ -0,0 +1,295 @@
+package runtime
+
+import (
+	"fmt"
+	"os"
+)
+
+// Promise is a promise, the eventual result of an asynchronous operation
+// The reactions added by then run as jobs of the microtask queue once the
+// promise settles.
+type Promise struct {
+	JSObject
+	state promiseState
+	// result is the value of a fulfilled promise or the reason of a rejected
+	// promise
+	result           Object
+	fulfillReactions []*promiseReaction
+	rejectReactions  []*promiseReaction
+	// handled is true once a reaction is added, so that the rejection of
+	// the promise isn't reported
+	handled bool
+}
+
+type promiseState int
+
+const (
+	promisePending promiseState = iota
+	promiseFulfilled
+	promiseRejected
+)
+
+// promiseCapability is a promise with the functions that resolve and reject
+// it
+type promiseCapability struct {
+	promise Object
+	resolve Object
+	reject  Object
+}
+
+// promiseReaction is a handler added by then, whose result settles the
+// promise of the capability
+// A handler that isn't callable passes the result of the promise on. The
+// capability is nil for the handlers of await, which resume the body of an
+// async function.
+type promiseReaction struct {
+	capability *promiseCapability
+	fulfill    bool
+	handler    Object
+}
+
+var (
+	// jobQueue is the microtask queue, whose jobs run in order once the
+	// script completes
+	jobQueue []func()
+	// unhandledRejections holds the rejected promises without a handler in
+	// the order they're rejected
+	unhandledRejections []*Promise
+)
+
+func enqueueJob(job func()) {
+	jobQueue = append(jobQueue, job)
+}
+
+// RunJobs runs the jobs of the microtask queue until it's empty, and then
+// reports the first rejected promise that has no handler and exits like Node
+// It must be called by the main function after the script.
+func RunJobs() {
+	for len(jobQueue) > 0 {
+		job := jobQueue[0]
+		jobQueue = jobQueue[1:]
+		job()
+	}
+
+	if len(unhandledRejections) > 0 {
+		fmt.Fprintln(os.Stderr, describeRejection(unhandledRejections[0].result))
+		os.Exit(1)
+	}
+}
+
+// describeRejection formats the reason of an unhandled rejection like an
+// uncaught exception, wrapping a reason that isn't an error like Node does
+func describeRejection(reason Object) string {
+	if o, ok := reason.(ObjectValue); ok && o.object().class == "Error" {
+		return (&Exception{reason}).Error()
+	}
+
+	return "Uncaught UnhandledPromiseRejection: This error originated either by throwing inside of an async function without a catch block, or by rejecting a promise which was not handled with .catch(). The promise rejected with the reason \"" + toDisplayString(reason) + "\"."
+}
+
+// catchThrow runs f and returns the exception it throws
+func catchThrow(f func()) (thrown *Exception) {
+	defer func() {
+		if r := recover(); r != nil {
+			e, ok := r.(*Exception)
+			if !ok {
+				panic(r)
+			}
+			thrown = e
+		}
+	}()
+
+	f()
+	return nil
+}
+
+func newPromise(proto ObjectValue) *Promise {
+	return &Promise{JSObject: JSObject{class: "Object", proto: proto}}
+}
+
+// resolvingFunctions creates the resolve and reject functions passed to the
+// executor of a promise, which only settle the promise once
+func (p *Promise) resolvingFunctions() (resolve, reject *JSFunction) {
+	alreadyResolved := false
+	resolve = NewFunction("", 1, func(this Object, args []Object) Object {
+		if !alreadyResolved {
+			alreadyResolved = true
+			p.resolve(Arg(args, 0))
+		}
+
+		return Undefined
+	})
+	reject = NewFunction("", 1, func(this Object, args []Object) Object {
+		if !alreadyResolved {
+			alreadyResolved = true
+			p.reject(Arg(args, 0))
+		}
+
+		return Undefined
+	})
+
+	return resolve, reject
+}
+
+// resolve resolves a promise with a value, which the promise follows when
+// it's a thenable
+// The then method of the thenable is called by a job, so that a promise
+// resolved with another promise takes two more jobs to settle.
+func (p *Promise) resolve(resolution Object) {
+	if resolution == Object(p) {
+		p.reject(newTypeError("Chaining cycle detected for promise #<Promise>").Value)
+		return
+	}
+
+	if !isObject(resolution) {
+		p.fulfill(resolution)
+		return
+	}
+
+	var then Object
+	if thrown := catchThrow(func() { then = Get(resolution, JSString("then")) }); thrown != nil {
+		p.reject(thrown.Value)
+		return
+	}
+
+	if !isCallable(then) {
+		p.fulfill(resolution)
+		return
+	}
+
+	enqueueJob(func() {
+		resolve, reject := p.resolvingFunctions()
+		if thrown := catchThrow(func() { Call(then, resolution, []Object{resolve, reject}) }); thrown != nil {
+			Call(reject, Undefined, []Object{thrown.Value})
+		}
+	})
+}
+
+func (p *Promise) fulfill(v Object) {
+	reactions := p.fulfillReactions
+	p.state, p.result = promiseFulfilled, v
+	p.fulfillReactions, p.rejectReactions = nil, nil
+	for _, r := range reactions {
+		enqueueJob(r.job(v))
+	}
+}
+
+func (p *Promise) reject(reason Object) {
+	reactions := p.rejectReactions
+	p.state, p.result = promiseRejected, reason
+	p.fulfillReactions, p.rejectReactions = nil, nil
+	if !p.handled {
+		unhandledRejections = append(unhandledRejections, p)
+	}
+	for _, r := range reactions {
+		enqueueJob(r.job(reason))
+	}
+}
+
+// job creates the job that calls the handler of a reaction with the result
+// of the settled promise
+func (r *promiseReaction) job(argument Object) func() {
+	return func() {
+		var v Object
+		var thrown *Exception
+		switch {
+		case isCallable(r.handler):
+			thrown = catchThrow(func() { v = Call(r.handler, Undefined, []Object{argument}) })
+		case r.fulfill:
+			v = argument
+		default:
+			thrown = &Exception{argument}
+		}
+
+		if r.capability == nil {
+			return
+		}
+
+		if thrown != nil {
+			Call(r.capability.reject, Undefined, []Object{thrown.Value})
+		} else {
+			Call(r.capability.resolve, Undefined, []Object{v})
+		}
+	}
+}
+
+// then adds the reactions of a promise, which settle the promise of the
+// capability if it isn't nil
+func (p *Promise) then(onFulfilled, onRejected Object, capability *promiseCapability) Object {
+	fulfillReaction := &promiseReaction{capability: capability, fulfill: true, handler: onFulfilled}
+	rejectReaction := &promiseReaction{capability: capability, handler: onRejected}
+	switch p.state {
+	case promisePending:
+		p.fulfillReactions = append(p.fulfillReactions, fulfillReaction)
+		p.rejectReactions = append(p.rejectReactions, rejectReaction)
+	case promiseFulfilled:
+		enqueueJob(fulfillReaction.job(p.result))
+	case promiseRejected:
+		if !p.handled {
+			p.handle()
+		}
+		enqueueJob(rejectReaction.job(p.result))
+	}
+	p.handled = true
+
+	if capability == nil {
+		return Undefined
+	}
+
+	return capability.promise
+}
+
+// handle stops tracking a rejected promise that is given a handler
+func (p *Promise) handle() {
+	for i, rejected := range unhandledRejections {
+		if rejected == p {
+			unhandledRejections = append(unhandledRejections[:i], unhandledRejections[i+1:]...)
+			return
+		}
+	}
+}
+
+// newPromiseCapability creates a promise with a promise constructor, which
+// passes the functions that resolve and reject the promise to an executor
+func newPromiseCapability(c Object) *promiseCapability {
+	if c == Object(promiseConstructor) {
+		p := newPromise(promisePrototype)
+		resolve, reject := p.resolvingFunctions()
+		return &promiseCapability{promise: p, resolve: resolve, reject: reject}
+	}
+
+	f, ok := c.(*JSFunction)
+	if !ok || f.construct == nil {
+		panic(newTypeError(toDisplayString(c) + " is not a constructor"))
+	}
+
+	capability := &promiseCapability{resolve: Undefined, reject: Undefined}
+	executor := NewFunction("", 2, func(this Object, args []Object) Object {
+		if capability.resolve != Undefined || capability.reject != Undefined {
+			panic(newTypeError("Promise executor has already been invoked with non-undefined arguments"))
+		}
+		capability.resolve, capability.reject = Arg(args, 0), Arg(args, 1)
+
+		return Undefined
+	})
+	promise := f.Construct([]Object{executor}, f)
+	if !isCallable(capability.resolve) || !isCallable(capability.reject) {
+		panic(newTypeError("Promise resolve or reject function is not callable"))
+	}
+	capability.promise = promise
+
+	return capability
+}
+
+// promiseResolve returns a promise of a constructor that is resolved with a
+// value, which is the value itself if it's a promise of the constructor
+func promiseResolve(c Object, v Object) Object {
+	if p, ok := v.(*Promise); ok && Get(p, JSString("constructor")) == c {
+		return p
+	}
+
+	capability := newPromiseCapability(c)
+	Call(capability.resolve, Undefined, []Object{v})
+
+	return capability.promise
+}
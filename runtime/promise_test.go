@@ -0,0 +1,38 @@
+package runtime
+
+import "testing"
+
+func TestPromiseRejectionHandledLater(t *testing.T) {
+	defer func() { jobQueue, unhandledRejections = nil, nil }()
+
+	p := newPromise(promisePrototype)
+	p.reject(JSString("reason"))
+	if len(unhandledRejections) != 1 {
+		t.Fatalf("a rejected promise without a handler should be tracked: n=%d", len(unhandledRejections))
+	}
+
+	p.then(Undefined, Undefined, nil)
+	if len(unhandledRejections) != 0 {
+		t.Fatalf("a handler should stop tracking the rejection: n=%d", len(unhandledRejections))
+	}
+}
+
+func TestPromiseReactionsRunAsJobs(t *testing.T) {
+	defer func() { jobQueue, unhandledRejections = nil, nil }()
+
+	var got Object
+	p := newPromise(promisePrototype)
+	p.then(NewFunction("", 1, func(this Object, args []Object) Object {
+		got = Arg(args, 0)
+		return Undefined
+	}), Undefined, nil)
+	p.resolve(JSNumber(1))
+	if got != nil {
+		t.Fatal("a reaction shouldn't run before the jobs run")
+	}
+
+	RunJobs()
+	if got != JSNumber(1) {
+		t.Fatalf("unexpected value: got=%v", got)
+	}
+}
@@ -0,0 +1,229 @@
+package runtime
+
+import (
+	"sort"
+	"strconv"
+)
+
+// PropertyKey is a property name
+type PropertyKey interface {
+	Object
+	propertyKey()
+}
+
+// Property is either a data property with a value or an accessor property
+// with a getter and a setter
+type Property struct {
+	value        Object
+	getter       Object
+	setter       Object
+	accessor     bool
+	writable     bool
+	enumerable   bool
+	configurable bool
+}
+
+// get returns the value of the property for the receiver
+func (p *Property) get(receiver Object) Object {
+	if !p.accessor {
+		return p.value
+	}
+
+	if p.getter == nil {
+		return nil
+	}
+
+	return Call(p.getter, receiver, nil)
+}
+
+// PropertyDescriptor describes the attributes of a property
+// Attributes that are absent from a descriptor are left untouched when the
+// descriptor is applied to an existing property.
+type PropertyDescriptor struct {
+	Value        Object
+	Get          Object
+	Set          Object
+	Writable     bool
+	Enumerable   bool
+	Configurable bool
+
+	HasValue        bool
+	HasGet          bool
+	HasSet          bool
+	HasWritable     bool
+	HasEnumerable   bool
+	HasConfigurable bool
+}
+
+func (d *PropertyDescriptor) isAccessor() bool {
+	return d.HasGet || d.HasSet
+}
+
+func (d *PropertyDescriptor) isData() bool {
+	return d.HasValue || d.HasWritable
+}
+
+func (d *PropertyDescriptor) isGeneric() bool {
+	return !d.isAccessor() && !d.isData()
+}
+
+// toProperty creates a property with absent attributes set to their defaults
+func (d *PropertyDescriptor) toProperty() *Property {
+	p := &Property{}
+	d.applyTo(p)
+
+	return p
+}
+
+// applyTo updates a property with the attributes present in the descriptor
+func (d *PropertyDescriptor) applyTo(p *Property) {
+	if d.isAccessor() && !p.accessor {
+		p.accessor = true
+		p.value = nil
+		p.writable = false
+	} else if d.isData() && p.accessor {
+		p.accessor = false
+		p.getter = nil
+		p.setter = nil
+	}
+
+	if d.HasValue {
+		p.value = d.Value
+	}
+	if d.HasGet {
+		p.getter = d.Get
+	}
+	if d.HasSet {
+		p.setter = d.Set
+	}
+	if d.HasWritable {
+		p.writable = d.Writable
+	}
+	if d.HasEnumerable {
+		p.enumerable = d.Enumerable
+	}
+	if d.HasConfigurable {
+		p.configurable = d.Configurable
+	}
+}
+
+// arrayIndex returns the array index that a property key represents
+func arrayIndex(key PropertyKey) (uint32, bool) {
+	s, ok := key.(JSString)
+	if !ok || len(s) == 0 || len(s) > 10 || (len(s) > 1 && s[0] == '0') {
+		return 0, false
+	}
+
+	n, err := strconv.ParseUint(string(s), 10, 32)
+	if err != nil || n == 1<<32-1 {
+		return 0, false
+	}
+
+	return uint32(n), true
+}
+
+// orderKeys sorts property keys in the order of OrdinaryOwnPropertyKeys
+func orderKeys(keys []PropertyKey) []PropertyKey {
+	var indices []uint32
+	var strs, others []PropertyKey
+	for _, k := range keys {
+		if i, ok := arrayIndex(k); ok {
+			indices = append(indices, i)
+		} else if _, ok := k.(JSString); ok {
+			strs = append(strs, k)
+		} else {
+			others = append(others, k)
+		}
+	}
+
+	result := make([]PropertyKey, 0, len(keys))
+	if len(indices) > 0 {
+		sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+		for _, i := range indices {
+			result = append(result, JSString(strconv.FormatUint(uint64(i), 10)))
+		}
+	}
+	result = append(result, strs...)
+	result = append(result, others...)
+
+	return result
+}
+
+// getProperty implements [[Get]] along the prototype chain
+func getProperty(o ObjectValue, key PropertyKey, receiver Object) Object {
+	for ; o != nil; o = o.object().proto {
+		if p := o.getOwnProperty(key); p != nil {
+			return p.get(receiver)
+		}
+	}
+
+	return nil
+}
+
+// setProperty implements OrdinarySet: an inherited setter is called and
+// an inherited read-only property prevents the assignment, otherwise a data
+// property is created or updated on the receiver
+func setProperty(o ObjectValue, key PropertyKey, v Object, receiver Object) bool {
+	var found *Property
+	for obj := o; obj != nil; obj = obj.object().proto {
+		if found = obj.getOwnProperty(key); found != nil {
+			break
+		}
+	}
+
+	if found != nil {
+		if found.accessor {
+			if found.setter == nil {
+				return false
+			}
+
+			Call(found.setter, receiver, []Object{v})
+			return true
+		}
+
+		if !found.writable {
+			return false
+		}
+	}
+
+	r, ok := receiver.(ObjectValue)
+	if !ok {
+		return false
+	}
+
+	if existing := r.getOwnProperty(key); existing != nil {
+		if existing.accessor || !existing.writable {
+			return false
+		}
+
+		return r.defineOwnProperty(key, &PropertyDescriptor{Value: v, HasValue: true})
+	}
+
+	return createDataProperty(r, key, v)
+}
+
+// hasProperty implements [[HasProperty]] along the prototype chain
+func hasProperty(o ObjectValue, key PropertyKey) bool {
+	for ; o != nil; o = o.object().proto {
+		if o.getOwnProperty(key) != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// createDataProperty creates or replaces an enumerable, writable and
+// configurable own property
+func createDataProperty(o ObjectValue, key PropertyKey, v Object) bool {
+	return o.defineOwnProperty(key, &PropertyDescriptor{
+		Value:           v,
+		Writable:        true,
+		Enumerable:      true,
+		Configurable:    true,
+		HasValue:        true,
+		HasWritable:     true,
+		HasEnumerable:   true,
+		HasConfigurable: true,
+	})
+}
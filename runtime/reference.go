@@ -0,0 +1,280 @@
+package runtime
+
+import "fmt"
+
+// Assign stores v in a variable and returns v as the value of the assignment
+func Assign(ref *Object, v Object) Object {
+	*ref = v
+	return v
+}
+
+// GetGlobal resolves an identifier that isn't declared in any scope
+// It's an error to reference a missing property of the global object.
+func GetGlobal(global *JSObject, name string) Object {
+	v, ok := global.GetProperty(name)
+	if !ok {
+		panic(newReferenceError(name + " is not defined"))
+	}
+
+	return v
+}
+
+// SetGlobal assigns to an identifier that isn't declared in any scope,
+// creating a property of the global object if needed
+// Assigning a read-only property like undefined has no effect.
+func SetGlobal(global *JSObject, name string, v Object) Object {
+	setProperty(global, JSString(name), v, global)
+	return v
+}
+
+// SetGlobalStrict is SetGlobal in strict mode code, where it's an error to
+// assign to a missing property of the global object or to a read-only one
+func SetGlobalStrict(global *JSObject, name string, v Object) Object {
+	key := JSString(name)
+	if !hasProperty(global, key) {
+		panic(newReferenceError(name + " is not defined"))
+	}
+	if !setProperty(global, key, v, global) {
+		panic(assignmentError(global, key))
+	}
+
+	return v
+}
+
+// LookupGlobal resolves an identifier that isn't declared in any scope like
+// GetGlobal, but returns undefined for a missing property of the global object
+// It's used by typeof.
+func LookupGlobal(global *JSObject, name string) Object {
+	v, _ := global.GetProperty(name)
+	return v
+}
+
+// DeleteGlobal deletes a property of the global object for an identifier
+// that isn't declared in any scope
+func DeleteGlobal(global *JSObject, name string) Object {
+	return JSBoolean(global.deleteProperty(JSString(name)))
+}
+
+// UpdateGlobal implements ++ and -- on an identifier that isn't declared in
+// any scope
+func UpdateGlobal(global *JSObject, name string, delta JSNumber, prefix bool) Object {
+	old := ToNumber(GetGlobal(global, name))
+	SetGlobal(global, name, old+delta)
+	if prefix {
+		return old + delta
+	}
+
+	return old
+}
+
+// UpdateGlobalStrict is UpdateGlobal in strict mode code
+func UpdateGlobalStrict(global *JSObject, name string, delta JSNumber, prefix bool) Object {
+	old := ToNumber(GetGlobal(global, name))
+	SetGlobalStrict(global, name, old+delta)
+	if prefix {
+		return old + delta
+	}
+
+	return old
+}
+
+// Get implements a property reference like obj.prop
+// A primitive value is converted to an object, but it's the receiver of
+// getters.
+func Get(v Object, key PropertyKey) Object {
+	switch s := v.(type) {
+	case nil, null:
+		panic(newTypeError(fmt.Sprintf("Cannot read properties of %s (reading '%s')", ToString(v), toDisplayString(key))))
+	case JSString:
+		// the length and the characters of a string don't need a wrapper
+		if p := stringOwnProperty(s, key); p != nil {
+			return p.value
+		}
+	}
+
+	return getProperty(ToObject(v), key, v)
+}
+
+// GetElement implements a computed property reference like obj[key]
+// Dense arrays and strings are indexed by integer keys without converting
+// them to strings.
+func GetElement(v Object, key Object) Object {
+	if i, ok := integerIndex(key); ok {
+		switch v := v.(type) {
+		case *JSArray:
+			if !v.sparse && i < int64(len(v.elements)) {
+				return v.elements[i]
+			}
+		case JSString:
+			if c, ok := charAt(v, i); ok {
+				return c
+			}
+		}
+	}
+
+	return Get(v, ToPropertyKey(key))
+}
+
+// Set assigns to a property reference like obj.prop and returns v as the
+// value of the assignment
+func Set(v Object, key PropertyKey, value Object) Object {
+	if isNullish(v) {
+		panic(newTypeError(fmt.Sprintf("Cannot set properties of %s (setting '%s')", ToString(v), toDisplayString(key))))
+	}
+
+	setProperty(ToObject(v), key, value, v)
+	return value
+}
+
+// SetStrict is Set in strict mode code, where it's an error to assign to a
+// property that can't be assigned
+func SetStrict(v Object, key PropertyKey, value Object) Object {
+	if isNullish(v) {
+		panic(newTypeError(fmt.Sprintf("Cannot set properties of %s (setting '%s')", ToString(v), toDisplayString(key))))
+	}
+
+	if !setProperty(ToObject(v), key, value, v) {
+		panic(assignmentError(v, key))
+	}
+
+	return value
+}
+
+// assignmentError is the TypeError of a failed assignment to a property in
+// strict mode code, which tells why it failed like V8 does
+func assignmentError(v Object, key PropertyKey) *Exception {
+	var found *Property
+	for o := ToObject(v); o != nil && found == nil; o = o.object().proto {
+		found = o.getOwnProperty(key)
+	}
+
+	switch _, isObject := v.(ObjectValue); {
+	case found != nil && found.accessor:
+		return newTypeError(fmt.Sprintf("Cannot set property %s of %s which has only a getter", toDisplayString(key), toDisplayString(v)))
+	case found != nil:
+		return newTypeError(fmt.Sprintf("Cannot assign to read only property '%s' of %s '%s'", toDisplayString(key), TypeOf(v), toDisplayString(v)))
+	case isObject:
+		return newTypeError(fmt.Sprintf("Cannot add property %s, object is not extensible", toDisplayString(key)))
+	default:
+		return newTypeError(fmt.Sprintf("Cannot create property '%s' on %s '%s'", toDisplayString(key), TypeOf(v), toDisplayString(v)))
+	}
+}
+
+// SetElement assigns to a computed property reference like obj[key] = value
+// The elements of dense arrays are assigned directly.
+func SetElement(v Object, key Object, value Object) Object {
+	if a, ok := v.(*JSArray); ok && !a.sparse {
+		if i, ok := integerIndex(key); ok && i < int64(len(a.elements)) {
+			a.elements[i] = value
+			return value
+		}
+	}
+
+	return Set(v, ToPropertyKey(key), value)
+}
+
+// SetElementStrict is SetElement in strict mode code
+func SetElementStrict(v Object, key Object, value Object) Object {
+	if a, ok := v.(*JSArray); ok && !a.sparse {
+		if i, ok := integerIndex(key); ok && i < int64(len(a.elements)) {
+			a.elements[i] = value
+			return value
+		}
+	}
+
+	return SetStrict(v, ToPropertyKey(key), value)
+}
+
+// UpdateProperty implements ++ and -- on a property reference like obj[key]
+// The key is converted to a property key for both reading and assigning.
+func UpdateProperty(v Object, key Object, delta JSNumber, prefix bool) Object {
+	old := ToNumber(GetElement(v, key))
+	SetElement(v, key, old+delta)
+	if prefix {
+		return old + delta
+	}
+
+	return old
+}
+
+// UpdatePropertyStrict is UpdateProperty in strict mode code
+func UpdatePropertyStrict(v Object, key Object, delta JSNumber, prefix bool) Object {
+	old := ToNumber(GetElement(v, key))
+	SetElementStrict(v, key, old+delta)
+	if prefix {
+		return old + delta
+	}
+
+	return old
+}
+
+// integerIndex returns the index that a number key represents
+func integerIndex(key Object) (int64, bool) {
+	n, ok := key.(JSNumber)
+	if !ok {
+		return 0, false
+	}
+
+	i := int64(n)
+	return i, i >= 0 && JSNumber(i) == n
+}
+
+// Invoke calls a method like obj.method(), with the object as this
+func Invoke(v Object, key PropertyKey, args []Object) Object {
+	return Call(Get(v, key), v, args)
+}
+
+type uninitialized struct{}
+
+func (self uninitialized) Type() JSObjectType { return "" }
+
+// Uninitialized is the value of a let or const binding in its temporal dead zone
+var Uninitialized Object = uninitialized{}
+
+// CheckInit returns the value of a let or const binding
+// It's an error to reference a binding before it's initialized
+func CheckInit(v Object, name string) Object {
+	if v == Uninitialized {
+		panic(newReferenceError(fmt.Sprintf("Cannot access '%s' before initialization", name)))
+	}
+
+	return v
+}
+
+// CheckRef returns a let binding to be updated, which must be initialized
+func CheckRef(ref *Object, name string) *Object {
+	CheckInit(*ref, name)
+	return ref
+}
+
+// Update implements ++ and -- on a binding, returning the new value for a
+// prefix operator and the old value converted to a number for a postfix one
+func Update(ref *Object, delta JSNumber, prefix bool) Object {
+	old := ToNumber(*ref)
+	*ref = old + delta
+	if prefix {
+		return old + delta
+	}
+
+	return old
+}
+
+// AssignChecked stores v in a let binding that may not be initialized yet
+func AssignChecked(ref *Object, name string, v Object) Object {
+	CheckInit(*ref, name)
+	*ref = v
+	return v
+}
+
+// AssignConst fails an assignment to a const binding
+func AssignConst(name string, v Object) Object {
+	panic(newTypeError("Assignment to constant variable."))
+}
+
+// Load returns the value of a binding
+// Go evaluates variable operands after the function calls of an expression,
+// so a read that must happen before a later operand's side effects is
+// wrapped in a call.
+func Load(v Object) Object {
+	return v
+}
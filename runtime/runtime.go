@@ -1,22 +1,29 @@
 package runtime
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 var (
-	console = &JSObject{
-		properties: map[string]Object{
-			"log": &JSFunction{
-				fn: Console_Log,
-			},
-		},
-	}
+	console = NewObject()
 )
 
-func Console_Log(data []Object) {
-	var i []interface{}
+func init() {
+	console.DefineProperty("log", NewFunction("log", 0, Console_Log))
+}
+
+func Console_Log(this Object, data []Object) Object {
+	var s []string
 	for _, d := range data {
-		i = append(i, d)
+		if str, ok := d.(JSString); ok {
+			s = append(s, string(str))
+		} else {
+			s = append(s, Inspect(d))
+		}
 	}
 
-	fmt.Println(i...)
+	fmt.Println(strings.Join(s, " "))
+
+	return nil
 }
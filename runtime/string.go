@@ -0,0 +1,115 @@
+package runtime
+
+import (
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// stringObject is a String wrapper object, a String exotic object whose
+// length and indices are read-only properties of its string
+type stringObject struct {
+	JSObject
+}
+
+func newStringObject(s JSString) *stringObject {
+	return &stringObject{JSObject{class: "String", proto: stringPrototype, primitive: s}}
+}
+
+func (self *stringObject) GetProperty(prop string) (Object, bool) {
+	return lookupProperty(self, prop)
+}
+
+func (self *stringObject) getOwnProperty(key PropertyKey) *Property {
+	if p := stringOwnProperty(self.primitive.(JSString), key); p != nil {
+		return p
+	}
+
+	return self.JSObject.getOwnProperty(key)
+}
+
+func (self *stringObject) defineOwnProperty(key PropertyKey, desc *PropertyDescriptor) bool {
+	if p := stringOwnProperty(self.primitive.(JSString), key); p != nil {
+		return isCompatibleDescriptor(p, desc)
+	}
+
+	return self.JSObject.defineOwnProperty(key, desc)
+}
+
+func (self *stringObject) deleteProperty(key PropertyKey) bool {
+	if stringOwnProperty(self.primitive.(JSString), key) != nil {
+		return false
+	}
+
+	return self.JSObject.deleteProperty(key)
+}
+
+// ownKeys returns the indices of the string, then the other keys
+func (self *stringObject) ownKeys() []PropertyKey {
+	n := stringLength(string(self.primitive.(JSString)))
+	keys := make([]PropertyKey, 0, n+len(self.keys)+1)
+	for i := 0; i < n; i++ {
+		keys = append(keys, indexKey(int64(i)))
+	}
+
+	rest := self.JSObject.ownKeys()
+	i := 0
+	for ; i < len(rest); i++ {
+		if _, ok := arrayIndex(rest[i]); !ok {
+			break
+		}
+	}
+	keys = append(keys, rest[:i]...)
+	keys = append(keys, JSString("length"))
+
+	return append(keys, rest[i:]...)
+}
+
+// stringOwnProperty returns the length or a character of a string, which are
+// the own properties of its wrapper object
+func stringOwnProperty(s JSString, key PropertyKey) *Property {
+	if key == JSString("length") {
+		return &Property{value: JSNumber(stringLength(string(s)))}
+	}
+
+	if i, ok := arrayIndex(key); ok {
+		if c, ok := charAt(s, int64(i)); ok {
+			return &Property{value: c, enumerable: true}
+		}
+	}
+
+	return nil
+}
+
+// charAt returns the character of a string at an index, which counts UTF-16
+// code units
+// A surrogate that is half of a pair is replaced with U+FFFD.
+func charAt(s JSString, i int64) (JSString, bool) {
+	if i < 0 {
+		return "", false
+	}
+
+	if isASCII(s) {
+		if i >= int64(len(s)) {
+			return "", false
+		}
+
+		return s[i : i+1], true
+	}
+
+	units := utf16.Encode([]rune(string(s)))
+	if i >= int64(len(units)) {
+		return "", false
+	}
+
+	return JSString(string(utf16.Decode(units[i : i+1]))), true
+}
+
+func isASCII(s JSString) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,131 @@
+package runtime
+
+// Symbol is a symbol value, a property key that is unique to the symbol
+type Symbol struct {
+	// description is a JSString, or undefined for a symbol created without
+	// a description
+	description Object
+	// weakRef stands for the symbol in the weak collections that hold it
+	weakRef *weakRef
+}
+
+func (self *Symbol) Type() JSObjectType { return JS_OBJECT_TYPE_SYMBOL }
+
+func (self *Symbol) propertyKey() {}
+
+// String returns the descriptive string of a symbol, like Symbol(foo)
+func (self *Symbol) String() string {
+	if self.description == nil {
+		return "Symbol()"
+	}
+
+	return "Symbol(" + string(self.description.(JSString)) + ")"
+}
+
+// NewSymbol creates a symbol with a description
+func NewSymbol(description string) *Symbol {
+	return &Symbol{description: JSString(description)}
+}
+
+// the well-known symbols, which are the keys of the properties that customize
+// the behavior of builtin operations
+var (
+	SymbolHasInstance   = NewSymbol("Symbol.hasInstance")
+	SymbolIterator      = NewSymbol("Symbol.iterator")
+	SymbolToPrimitive   = NewSymbol("Symbol.toPrimitive")
+	SymbolToStringTag   = NewSymbol("Symbol.toStringTag")
+	SymbolAsyncIterator = NewSymbol("Symbol.asyncIterator")
+)
+
+var (
+	symbolPrototype = &JSObject{class: "Object", proto: objectPrototype}
+
+	// symbolConstructor is Symbol, which creates a symbol when it's called
+	// but can't be constructed
+	symbolConstructor = NewFunction("Symbol", 0, func(this Object, args []Object) Object {
+		description := Arg(args, 0)
+		if description == Undefined {
+			return &Symbol{}
+		}
+
+		return &Symbol{description: ToString(description)}
+	})
+
+	// symbolRegistry holds the symbols shared with Symbol.for
+	symbolRegistry = make(map[JSString]*Symbol)
+)
+
+func init() {
+	linkPrototype(symbolConstructor, symbolPrototype)
+	defineMethod(symbolConstructor, "for", 1, Symbol_For)
+	defineMethod(symbolConstructor, "keyFor", 1, Symbol_KeyFor)
+	defineValue(symbolConstructor, "asyncIterator", SymbolAsyncIterator)
+	defineValue(symbolConstructor, "hasInstance", SymbolHasInstance)
+	defineValue(symbolConstructor, "iterator", SymbolIterator)
+	defineValue(symbolConstructor, "toPrimitive", SymbolToPrimitive)
+	defineValue(symbolConstructor, "toStringTag", SymbolToStringTag)
+
+	defineMethod(symbolPrototype, "toString", 0, symbolPrototypeToString)
+	defineMethod(symbolPrototype, "valueOf", 0, symbolPrototypeValueOf)
+	symbolPrototype.defineOwnProperty(JSString("description"), &PropertyDescriptor{
+		Get:             NewFunction("get description", 0, symbolPrototypeDescription),
+		Configurable:    true,
+		HasGet:          true,
+		HasSet:          true,
+		HasEnumerable:   true,
+		HasConfigurable: true,
+	})
+	symbolPrototype.defineOwnProperty(SymbolToPrimitive, &PropertyDescriptor{Value: NewFunction("[Symbol.toPrimitive]", 1, symbolPrototypeValueOf), Configurable: true, HasValue: true, HasWritable: true, HasEnumerable: true, HasConfigurable: true})
+	defineToStringTag(symbolPrototype, "Symbol")
+}
+
+func Symbol_For(this Object, args []Object) Object {
+	key := ToString(Arg(args, 0))
+	s, ok := symbolRegistry[key]
+	if !ok {
+		s = &Symbol{description: key}
+		symbolRegistry[key] = s
+	}
+
+	return s
+}
+
+func Symbol_KeyFor(this Object, args []Object) Object {
+	s, ok := Arg(args, 0).(*Symbol)
+	if !ok {
+		panic(newTypeError(toDisplayString(Arg(args, 0)) + " is not a symbol"))
+	}
+
+	if symbolRegistry[ToString(s.description)] == s {
+		return s.description
+	}
+
+	return Undefined
+}
+
+// thisSymbolValue returns the symbol of this, which is either a symbol or a
+// Symbol wrapper object
+func thisSymbolValue(this Object, method string) *Symbol {
+	switch v := this.(type) {
+	case *Symbol:
+		return v
+	case ObjectValue:
+		if s, ok := v.object().primitive.(*Symbol); ok {
+			return s
+		}
+	}
+
+	panic(newTypeError(method + " requires that 'this' be a Symbol"))
+}
+
+func symbolPrototypeToString(this Object, args []Object) Object {
+	return JSString(thisSymbolValue(this, "Symbol.prototype.toString").String())
+}
+
+func symbolPrototypeValueOf(this Object, args []Object) Object {
+	return thisSymbolValue(this, "Symbol.prototype.valueOf")
+}
+
+func symbolPrototypeDescription(this Object, args []Object) Object {
+	return thisSymbolValue(this, "Symbol.prototype.description").description
+}
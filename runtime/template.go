@@ -0,0 +1,28 @@
+package runtime
+
+// templateObjects caches the template objects of tagged templates by call
+// site
+var templateObjects = make(map[int]*JSArray)
+
+// TemplateObject returns the strings passed to the tag function of a tagged
+// template, which is a frozen array of the cooked strings with a frozen raw
+// property of the raw strings
+// Every evaluation of a call site gets the same template object.
+func TemplateObject(site int, cooked []string, raw []string) *JSArray {
+	if t, ok := templateObjects[site]; ok {
+		return t
+	}
+
+	t, rawArray := NewArray(make([]Object, len(cooked))), NewArray(make([]Object, len(raw)))
+	for i := range cooked {
+		t.elements[i] = JSString(cooked[i])
+		rawArray.elements[i] = JSString(raw[i])
+	}
+	setIntegrityLevel(rawArray, true)
+	t.defineOwnProperty(JSString("raw"), &PropertyDescriptor{Value: rawArray, HasValue: true, HasWritable: true, HasEnumerable: true, HasConfigurable: true})
+	setIntegrityLevel(t, true)
+
+	templateObjects[site] = t
+
+	return t
+}
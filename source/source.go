@@ -14,10 +14,14 @@ import (
 )
 
 func main() {
+	defer ReportUncaught()
+
 	global := NewDefaultContext().Global
 	_ = global
 
 	{{.}}
+
+	RunJobs()
 }`
 
 func NewCode() *Code {
@@ -28,18 +32,23 @@ type Code struct {
 	buf *bytes.Buffer
 }
 
-func (c *Code) WriteTo(w io.Writer) error {
+func (c *Code) WriteTo(w io.Writer) (int64, error) {
 	t, err := template.New("main").Parse(tmpl)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	return t.Execute(w, strings.TrimSpace(c.buf.String()))
+	result := bytes.NewBuffer(nil)
+	if err := t.Execute(result, strings.TrimSpace(c.buf.String())); err != nil {
+		return 0, err
+	}
+
+	return result.WriteTo(w)
 }
 
 func (c *Code) String() string {
 	result := bytes.NewBuffer(nil)
-	err := c.WriteTo(result)
+	_, err := c.WriteTo(result)
 	if err != nil {
 		panic(err)
 	}
@@ -47,6 +56,11 @@ func (c *Code) String() string {
 	return result.String()
 }
 
+// Body returns the code written so far, without the main function around it
+func (c *Code) Body() string {
+	return c.buf.String()
+}
+
 func (c *Code) Write(s string) {
 	c.buf.WriteString(s)
 }